@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+const permalinkOutputKey = "SLACK_MESSAGE_PERMALINK"
+
+// channelTopicMaxLength is the longest channel topic accepted by Slack.
+const channelTopicMaxLength = 250
+
+// deletePreviousMessage deletes the message identified by the delete timestamp,
+// once the new message is sent. Failing to delete it is not fatal as the new
+// message is already posted.
+func deletePreviousMessage(conf config, response *SendMessageResponse) {
+	ts := strings.TrimSpace(conf.DeleteTs)
+	if ts == strings.TrimSpace(conf.Ts) {
+		log.Warnf("The message to delete is the one being updated, skipping the deletion")
+		return
+	}
+
+	channel := strings.TrimSpace(conf.Channel)
+	if response != nil && response.Channel != "" {
+		channel = response.Channel
+	}
+
+	params := url.Values{"channel": {channel}, "ts": {ts}}
+	if err := callAPI(string(conf.APIToken), "chat.delete", params, nil); err != nil {
+		log.Warnf("Failed to delete the previous message: %s", err)
+		return
+	}
+	log.Infof("Deleted the previous message (%s)", ts)
+}
+
+// pinMessage pins the sent message to the channel.
+func pinMessage(conf config, response *SendMessageResponse) error {
+	if response == nil {
+		return fmt.Errorf("the message can only be pinned when it is sent with an API token")
+	}
+
+	params := url.Values{"channel": {response.Channel}, "timestamp": {response.Timestamp}}
+	if err := callAPI(string(conf.APIToken), "pins.add", params, nil); err != nil && !isAPIError(err, "already_pinned") {
+		return fmt.Errorf("failed to pin the message: %s", err)
+	}
+	log.Infof("Pinned the message to the channel")
+	return nil
+}
+
+// setChannelTopic sets the topic of the channel the message is sent to, unless
+// it is already set. Failing to do so is not fatal as the message is already posted.
+func setChannelTopic(conf config, response *SendMessageResponse) {
+	if response == nil || response.Channel == "" {
+		log.Warnf("The channel topic can only be set when the message is sent with an API token")
+		return
+	}
+
+	topic := conf.ChannelTopic
+	if r := []rune(topic); len(r) > channelTopicMaxLength {
+		topic = string(r[:channelTopicMaxLength-1]) + "…"
+	}
+
+	var info struct {
+		Channel struct {
+			Topic struct {
+				Value string `json:"value"`
+			} `json:"topic"`
+		} `json:"channel"`
+	}
+	if err := callAPI(string(conf.APIToken), "conversations.info", url.Values{"channel": {response.Channel}}, &info); err != nil {
+		log.Debugf("Failed to get the channel topic: %s", err)
+	} else if info.Channel.Topic.Value == topic {
+		log.Debugf("The channel topic is already set")
+		return
+	}
+
+	params := url.Values{"channel": {response.Channel}, "topic": {topic}}
+	if err := callAPI(string(conf.APIToken), "conversations.setTopic", params, nil); err != nil {
+		log.Warnf("Failed to set the channel topic: %s", err)
+		return
+	}
+	log.Infof("Set the channel topic to %s", topic)
+}
+
+// exportPermalink fetches the permalink of the sent message and exports it.
+// Failing to do so is not fatal as the message is already posted.
+func exportPermalink(conf config, response *SendMessageResponse) {
+	if response == nil || response.Timestamp == "" {
+		return
+	}
+
+	var resp struct {
+		Permalink string `json:"permalink"`
+	}
+	params := url.Values{"channel": {response.Channel}, "message_ts": {response.Timestamp}}
+	if err := callAPI(string(conf.APIToken), "chat.getPermalink", params, &resp); err != nil {
+		log.Warnf("Failed to get the permalink of the message: %s", err)
+		return
+	}
+
+	log.Debugf("Exporting output: %s=%s\n", permalinkOutputKey, resp.Permalink)
+	if err := exportEnvVariable(permalinkOutputKey, resp.Permalink); err != nil {
+		log.Warnf("Failed to export %s: %s", permalinkOutputKey, err)
+	}
+}
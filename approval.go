@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// Approval decisions
+const (
+	approvalApproved = "APPROVED"
+	approvalRejected = "REJECTED"
+	approvalTimeout  = "TIMEOUT"
+)
+
+const approvalDecisionOutputKey = "SLACK_APPROVAL_DECISION"
+
+// approvalConfig describes the approval gate.
+type approvalConfig struct {
+	StatusURL    string
+	ApproveURL   string
+	RejectURL    string
+	Reactions    bool
+	Users        []string
+	Timeout      time.Duration
+	PollInterval time.Duration
+}
+
+// withApprovalButtons returns a copy of msg with the Approve and Reject buttons added.
+func withApprovalButtons(msg Message, c approvalConfig) Message {
+	if len(msg.Attachments) == 0 {
+		msg.Attachments = []Attachment{{}}
+	}
+
+	attachments := append([]Attachment{}, msg.Attachments...)
+	attachments[0].Buttons = append(append([]Button{}, attachments[0].Buttons...),
+		Button{Text: "Approve", URL: c.ApproveURL},
+		Button{Text: "Reject", URL: c.RejectURL},
+	)
+	msg.Attachments = attachments
+	return msg
+}
+
+// parseDecision parses the response of the approval status URL. The body is either
+// a JSON object with a decision key or the decision as plain text.
+func parseDecision(body []byte) string {
+	var resp struct {
+		Decision string `json:"decision"`
+	}
+	decision := strings.TrimSpace(string(body))
+	if err := json.Unmarshal(body, &resp); err == nil {
+		decision = resp.Decision
+	}
+
+	switch strings.ToLower(strings.TrimSpace(decision)) {
+	case "approved", "approve":
+		return approvalApproved
+	case "rejected", "reject":
+		return approvalRejected
+	}
+	return ""
+}
+
+// pollDecision requests the approval status URL once, it returns an empty
+// decision while nobody has responded yet.
+func pollDecision(statusURL string) (string, error) {
+	resp, err := httpClient.Get(statusURL)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Warnf("Failed to close response body: %s", err)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNoContent {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("approval status request failed: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return parseDecision(body), nil
+}
+
+// waitForDecision polls the approval status until a decision is made or the timeout expires.
+func waitForDecision(c approvalConfig) string {
+	log.Infof("Waiting for approval (timeout: %s)", c.Timeout)
+
+	deadline := time.Now().Add(c.Timeout)
+	for time.Now().Before(deadline) {
+		decision, err := pollDecision(c.StatusURL)
+		if err != nil {
+			log.Warnf("Failed to get the approval status: %s", err)
+		} else if decision != "" {
+			return decision
+		}
+		time.Sleep(c.PollInterval)
+	}
+	return approvalTimeout
+}
+
+// exportDecision exports the approval decision for the later steps.
+func exportDecision(decision string) error {
+	log.Infof("Approval decision: %s", decision)
+	return exportEnvVariable(approvalDecisionOutputKey, decision)
+}
+
+// Reactions recognized by the reaction approval gate
+var (
+	approveReactions = []string{"+1", "thumbsup"}
+	rejectReactions  = []string{"-1", "thumbsdown"}
+)
+
+// decisionFromReactions returns the decision made by the allowed users' reactions,
+// an empty allow list accepts anyone's reaction.
+func decisionFromReactions(reactions map[string][]string, allowed []string) string {
+	isAllowed := func(user string) bool {
+		if len(allowed) == 0 {
+			return true
+		}
+		for _, a := range allowed {
+			if a == user {
+				return true
+			}
+		}
+		return false
+	}
+	reacted := func(names []string) bool {
+		for _, name := range names {
+			for _, user := range reactions[name] {
+				if isAllowed(user) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	switch {
+	case reacted(rejectReactions):
+		return approvalRejected
+	case reacted(approveReactions):
+		return approvalApproved
+	}
+	return ""
+}
+
+// getReactions returns the users per reaction (skin tones are ignored) on the message.
+func getReactions(token string, response *SendMessageResponse) (map[string][]string, error) {
+	var resp struct {
+		Message struct {
+			Reactions []struct {
+				Name  string   `json:"name"`
+				Users []string `json:"users"`
+			} `json:"reactions"`
+		} `json:"message"`
+	}
+	params := url.Values{"channel": {response.Channel}, "timestamp": {response.Timestamp}, "full": {"true"}}
+	if err := callAPI(token, "reactions.get", params, &resp); err != nil {
+		return nil, err
+	}
+
+	reactions := map[string][]string{}
+	for _, r := range resp.Message.Reactions {
+		name := strings.SplitN(r.Name, "::", 2)[0]
+		reactions[name] = append(reactions[name], r.Users...)
+	}
+	return reactions, nil
+}
+
+// waitForReactionDecision polls the reactions of the sent message until an allowed
+// user reacts with 👍 or 👎, or the timeout expires.
+func waitForReactionDecision(token string, response *SendMessageResponse, c approvalConfig) string {
+	log.Infof("Waiting for a :+1: or :-1: reaction (timeout: %s)", c.Timeout)
+
+	deadline := time.Now().Add(c.Timeout)
+	for time.Now().Before(deadline) {
+		reactions, err := getReactions(token, response)
+		if err != nil {
+			log.Warnf("Failed to get the reactions: %s", err)
+		} else if decision := decisionFromReactions(reactions, c.Users); decision != "" {
+			return decision
+		}
+		time.Sleep(c.PollInterval)
+	}
+	return approvalTimeout
+}
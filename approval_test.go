@@ -0,0 +1,145 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_parseDecision(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "JSON approved", body: `{"decision": "approved"}`, want: approvalApproved},
+		{name: "JSON approve", body: `{"decision": " Approve "}`, want: approvalApproved},
+		{name: "JSON rejected", body: `{"decision": "REJECTED", "user": "jane"}`, want: approvalRejected},
+		{name: "JSON pending", body: `{"decision": ""}`, want: ""},
+		{name: "JSON without decision", body: `{"status": "approved"}`, want: ""},
+		{name: "Plain text approved", body: "approved", want: approvalApproved},
+		{name: "Plain text rejected", body: "REJECTED\n", want: approvalRejected},
+		{name: "Plain text reject", body: " reject ", want: approvalRejected},
+		{name: "Empty", body: "", want: ""},
+		{name: "Unknown", body: "maybe", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseDecision([]byte(tt.body)); got != tt.want {
+				t.Errorf("parseDecision() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_decisionFromReactions(t *testing.T) {
+	tests := []struct {
+		name      string
+		reactions map[string][]string
+		allowed   []string
+		want      string
+	}{
+		{
+			name:      "Anyone approves",
+			reactions: map[string][]string{"+1": {"U1"}},
+			want:      approvalApproved,
+		},
+		{
+			name:      "Reject wins",
+			reactions: map[string][]string{"+1": {"U1"}, "thumbsdown": {"U2"}},
+			want:      approvalRejected,
+		},
+		{
+			name:      "Not allowed user is ignored",
+			reactions: map[string][]string{"+1": {"U1"}, "-1": {"U3"}},
+			allowed:   []string{"U1", "U2"},
+			want:      approvalApproved,
+		},
+		{
+			name:      "Other reactions",
+			reactions: map[string][]string{"eyes": {"U1"}},
+			want:      "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decisionFromReactions(tt.reactions, tt.allowed); got != tt.want {
+				t.Errorf("decisionFromReactions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_pollDecision(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/not-found":
+			http.NotFound(w, r)
+		case "/no-content":
+			w.WriteHeader(http.StatusNoContent)
+		case "/approved":
+			_, _ = w.Write([]byte(`{"decision": "approved"}`))
+		case "/rejected":
+			_, _ = w.Write([]byte("rejected"))
+		default:
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{path: "/not-found", want: ""},
+		{path: "/no-content", want: ""},
+		{path: "/approved", want: approvalApproved},
+		{path: "/rejected", want: approvalRejected},
+		{path: "/error", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := pollDecision(server.URL + tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pollDecision() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("pollDecision() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_waitForDecision(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&polls, 1)
+		switch {
+		case r.URL.Path == "/pending":
+			w.WriteHeader(http.StatusNoContent)
+		case n == 1:
+			http.NotFound(w, r)
+		case n == 2:
+			http.Error(w, "boom", http.StatusBadGateway)
+		default:
+			_, _ = w.Write([]byte(`{"decision": "rejected"}`))
+		}
+	}))
+	defer server.Close()
+
+	c := approvalConfig{StatusURL: server.URL + "/decided", Timeout: 5 * time.Second, PollInterval: time.Millisecond}
+	if got := waitForDecision(c); got != approvalRejected {
+		t.Errorf("waitForDecision() = %v, want %v", got, approvalRejected)
+	}
+	if n := atomic.LoadInt32(&polls); n != 3 {
+		t.Errorf("waitForDecision() polled %d times, want 3", n)
+	}
+
+	c = approvalConfig{StatusURL: server.URL + "/pending", Timeout: 50 * time.Millisecond, PollInterval: 10 * time.Millisecond}
+	if got := waitForDecision(c); got != approvalTimeout {
+		t.Errorf("waitForDecision() = %v, want %v", got, approvalTimeout)
+	}
+}
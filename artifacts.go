@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// artifact is a file produced by the build.
+type artifact struct {
+	Name string
+	Size int64
+	URL  string
+}
+
+// findArtifacts lists the files matching the glob pattern, or the files of the
+// deploy dir if no pattern is given.
+func findArtifacts(deployDir, pattern string) ([]artifact, error) {
+	if pattern == "" {
+		if deployDir == "" {
+			return nil, nil
+		}
+		pattern = filepath.Join(deployDir, "*")
+	}
+
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifacts pattern: %s", err)
+	}
+
+	var artifacts []artifact
+	for _, pth := range paths {
+		info, err := os.Stat(pth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read artifact: %s", err)
+		}
+		// The undeliverable message is not an artifact of the build.
+		if info.IsDir() || info.Name() == failedPayloadFileName {
+			continue
+		}
+		artifacts = append(artifacts, artifact{Name: info.Name(), Size: info.Size()})
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Name < artifacts[j].Name })
+	return artifacts, nil
+}
+
+// fetchArtifactURLs returns the download or install page URLs of the build's
+// artifacts by their file name.
+func fetchArtifactURLs(token, appSlug, buildSlug string) (map[string]string, error) {
+	path := "apps/" + appSlug + "/builds/" + buildSlug + "/artifacts"
+	body, err := bitriseGet(token, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Data []struct {
+			Title string `json:"title"`
+			Slug  string `json:"slug"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse artifacts: %s", err)
+	}
+
+	urls := map[string]string{}
+	for _, a := range list.Data {
+		body, err := bitriseGet(token, path+"/"+a.Slug)
+		if err != nil {
+			return nil, err
+		}
+
+		var details struct {
+			Data struct {
+				PublicInstallPageURL string `json:"public_install_page_url"`
+				ExpiringDownloadURL  string `json:"expiring_download_url"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &details); err != nil {
+			return nil, fmt.Errorf("failed to parse artifact %s: %s", a.Title, err)
+		}
+
+		if u := details.Data.PublicInstallPageURL; u != "" {
+			urls[a.Title] = u
+		} else if u := details.Data.ExpiringDownloadURL; u != "" {
+			urls[a.Title] = u
+		}
+	}
+	return urls, nil
+}
+
+// formatSize formats the file size in a human readable form.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// artifactsField lists the artifacts with their sizes in a single field, titled
+// with tr.
+func artifactsField(artifacts []artifact, tr func(string) string) Field {
+	var lines []string
+	for _, a := range artifacts {
+		name := a.Name
+		if a.URL != "" {
+			name = fmt.Sprintf("<%s|%s>", a.URL, a.Name)
+		}
+		lines = append(lines, fmt.Sprintf("• %s (%s)", name, formatSize(a.Size)))
+	}
+	short := false
+	return Field{Title: tr("Artifacts"), Value: strings.Join(lines, "\n"), Short: &short}
+}
+
+// withArtifacts returns a copy of msg listing the artifacts below the other fields.
+func withArtifacts(msg Message, artifacts []artifact, tr func(string) string) Message {
+	if len(msg.Attachments) == 0 || len(artifacts) == 0 {
+		return msg
+	}
+
+	attachments := append([]Attachment{}, msg.Attachments...)
+	attachments[0].Fields = append(append([]Field{}, attachments[0].Fields...), artifactsField(artifacts, tr))
+	msg.Attachments = attachments
+	return msg
+}
+
+// listArtifacts finds the artifacts and links them to their Bitrise URLs if a
+// Bitrise API token is provided. Errors are only logged, the artifacts are optional.
+func listArtifacts(inp Input) []artifact {
+	artifacts, err := findArtifacts(inp.DeployDir, strings.TrimSpace(inp.ArtifactsPattern))
+	if err != nil {
+		log.Warnf("Failed to list the artifacts: %s", err)
+		return nil
+	}
+
+	if inp.BitriseAPIToken != "" && len(artifacts) > 0 {
+		urls, err := fetchArtifactURLs(string(inp.BitriseAPIToken), inp.BitriseAppSlug, inp.BuildSlug)
+		if err != nil {
+			log.Warnf("Failed to fetch the artifact URLs: %s", err)
+		}
+		for i := range artifacts {
+			artifacts[i].URL = urls[artifacts[i].Name]
+		}
+	}
+	return artifacts
+}
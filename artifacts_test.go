@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_findArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	for name, size := range map[string]int{"app.ipa": 2048, "app.dSYM.zip": 10, failedPayloadFileName: 5} {
+		if err := os.WriteFile(filepath.Join(dir, name), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "test_results"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []artifact
+	}{
+		{name: "Deploy dir", want: []artifact{{Name: "app.dSYM.zip", Size: 10}, {Name: "app.ipa", Size: 2048}}},
+		{name: "Glob", pattern: filepath.Join(dir, "*.ipa"), want: []artifact{{Name: "app.ipa", Size: 2048}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findArtifacts(dir, tt.pattern)
+			if err != nil {
+				t.Fatalf("findArtifacts() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findArtifacts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_artifactsField(t *testing.T) {
+	got := artifactsField([]artifact{
+		{Name: "app.ipa", Size: 52428800, URL: "https://app.bitrise.io/artifact/1"},
+		{Name: "app.dSYM.zip", Size: 512},
+	}, translate(nil)).Value
+	want := "• <https://app.bitrise.io/artifact/1|app.ipa> (50.0 MB)\n• app.dSYM.zip (512 B)"
+	if got != want {
+		t.Errorf("artifactsField() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// batchEntry is a single line of a batch file. Empty values fall back to the
+// ones configured by the step inputs.
+type batchEntry struct {
+	Text     string `json:"text"`
+	Channel  string `json:"channel"`
+	ThreadTs string `json:"thread_ts"`
+}
+
+// readBatchFile reads the newline-delimited JSON batch file at pth.
+func readBatchFile(pth string) ([]batchEntry, error) {
+	f, err := os.Open(pth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open batch file: %s", err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Warnf("Failed to close batch file: %s", err)
+		}
+	}()
+
+	return parseBatch(f)
+}
+
+// parseBatch parses newline-delimited JSON message specs, skipping empty lines.
+func parseBatch(r io.Reader) ([]batchEntry, error) {
+	var entries []batchEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var e batchEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return nil, fmt.Errorf("invalid batch entry in line %d: %s", n, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read batch file: %s", err)
+	}
+	return entries, nil
+}
+
+// deliveryResult is the outcome of sending a message to a single target.
+type deliveryResult struct {
+	Target string
+	Err    error
+}
+
+// sendBatch delivers a copy of msg for every batch entry using conf.BatchConcurrency
+// parallel workers, sending at most one message per interval to the same webhook
+// or channel. Failing entries do not stop the batch, the step only fails if more
+// than conf.BatchMaxFailedPercent percent of the messages failed.
+func sendBatch(conf config, msg Message, entries []batchEntry, send func(config, Message) (*SendMessageResponse, error)) error {
+	limiter := newRateLimiter(time.Duration(conf.BatchInterval) * time.Second)
+	workers := conf.BatchConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]deliveryResult, len(entries))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				m := batchMessage(msg, entries[i])
+				m.Text = maskText(m.Text, conf.MaskPatterns)
+				limiter.Wait(rateLimitTarget(conf, m))
+				log.Infof("Sending message %d/%d to %s", i+1, len(entries), m.Channel)
+				_, err := send(conf, m)
+				results[i] = deliveryResult{Target: m.Channel, Err: err}
+			}
+		}()
+	}
+	for i := range entries {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	log.Infof("Batch results:\n%s", resultsTable(results))
+
+	var failed int
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	if failed*100 > conf.BatchMaxFailedPercent*len(entries) {
+		return fmt.Errorf("%d of %d messages failed to send", failed, len(entries))
+	}
+	log.Warnf("%d of %d messages failed to send, within the allowed %d%%", failed, len(entries), conf.BatchMaxFailedPercent)
+	return nil
+}
+
+// batchMessage applies the overrides of a batch entry to a copy of msg.
+func batchMessage(msg Message, e batchEntry) Message {
+	m := msg
+	if e.Text != "" {
+		m.Text = e.Text
+	}
+	if e.Channel != "" {
+		m.Channel = strings.TrimSpace(e.Channel)
+	}
+	if e.ThreadTs != "" {
+		m.ThreadTs = e.ThreadTs
+	}
+	return m
+}
+
+// resultsTable formats the per-target results of a batch or of several providers as a table.
+func resultsTable(results []deliveryResult) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tTarget\tResult")
+	for i, r := range results {
+		target := r.Target
+		if target == "" {
+			target = "(default)"
+		}
+		result := "sent"
+		if r.Err != nil {
+			result = "failed: " + r.Err.Error()
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", i+1, target, result)
+	}
+	if err := w.Flush(); err != nil {
+		log.Warnf("Failed to format the results: %s", err)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseBatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    []batchEntry
+		wantErr bool
+	}{
+		{
+			name: "Entries with empty lines",
+			s:    "{\"text\":\"iOS passed\",\"channel\":\"#ios\"}\n\n{\"text\":\"Android failed\",\"thread_ts\":\"1405894322.002768\"}\n",
+			want: []batchEntry{
+				{Text: "iOS passed", Channel: "#ios"},
+				{Text: "Android failed", ThreadTs: "1405894322.002768"},
+			},
+		},
+		{
+			name:    "Invalid line",
+			s:       "{\"text\":\"ok\"}\nnot json",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBatch(strings.NewReader(tt.s))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseBatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseBatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_sendBatch(t *testing.T) {
+	entries := []batchEntry{{Channel: "#ios"}, {Channel: "#android"}, {Channel: "#web"}, {Channel: "#qa"}}
+	send := func(conf config, msg Message) (*SendMessageResponse, error) {
+		if msg.Channel == "#android" {
+			return nil, fmt.Errorf("channel_not_found")
+		}
+		return &SendMessageResponse{}, nil
+	}
+
+	tests := []struct {
+		name             string
+		maxFailedPercent int
+		wantErr          bool
+	}{
+		{name: "Any failure fails by default", wantErr: true},
+		{name: "Failures within the threshold", maxFailedPercent: 25},
+		{name: "Failures above the threshold", maxFailedPercent: 20, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := config{BatchConcurrency: 3, BatchMaxFailedPercent: tt.maxFailedPercent}
+			if err := sendBatch(conf, Message{}, entries, send); (err != nil) != tt.wantErr {
+				t.Errorf("sendBatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_resultsTable(t *testing.T) {
+	got := resultsTable([]deliveryResult{
+		{Target: "#ios"},
+		{Target: "#android", Err: fmt.Errorf("channel_not_found")},
+		{},
+	})
+	want := "#  Target     Result\n1  #ios       sent\n2  #android   failed: channel_not_found\n3  (default)  sent"
+	if got != want {
+		t.Errorf("resultsTable() = %q, want %q", got, want)
+	}
+}
+
+func Test_sendBatch_failedPayloads(t *testing.T) {
+	dir := t.TempDir()
+	var entries []batchEntry
+	for i := 0; i < 20; i++ {
+		entries = append(entries, batchEntry{Channel: fmt.Sprintf("#channel-%d", i), Text: strings.Repeat("x", 10000+i)})
+	}
+	send := func(conf config, msg Message) (*SendMessageResponse, error) {
+		err := fmt.Errorf("channel_not_found")
+		persistFailedPayload(conf, msg, err)
+		return nil, err
+	}
+
+	conf := config{BatchConcurrency: 8, BatchMaxFailedPercent: 100, DeployDir: dir}
+	if err := sendBatch(conf, Message{}, entries, send); err != nil {
+		t.Fatalf("sendBatch() error = %v", err)
+	}
+
+	// The workers write the file one at a time, so it holds one complete message.
+	b, err := os.ReadFile(filepath.Join(dir, failedPayloadFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg Message
+	if err := json.Unmarshal(b, &msg); err != nil {
+		t.Errorf("persistFailedPayload() wrote an invalid payload: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// bitriseAPIURL is the base URL of the Bitrise API.
+const bitriseAPIURL = "https://api.bitrise.io/v0.1/"
+
+// bitriseBuild holds the build details fetched from the Bitrise API.
+type bitriseBuild struct {
+	TriggeredBy string
+	Workflow    string
+	Stack       string
+	MachineType string
+	QueueTime   time.Duration
+	AbortReason string
+	PullRequest int
+	Tag         string
+}
+
+// parseBitriseBuild parses the response of the build details endpoint.
+func parseBitriseBuild(body []byte) (*bitriseBuild, error) {
+	var resp struct {
+		Data struct {
+			TriggeredAt       time.Time  `json:"triggered_at"`
+			StartedOnWorkerAt *time.Time `json:"started_on_worker_at"`
+			TriggeredBy       string     `json:"triggered_by"`
+			TriggeredWorkflow string     `json:"triggered_workflow"`
+			StackIdentifier   string     `json:"stack_identifier"`
+			MachineTypeID     string     `json:"machine_type_id"`
+			AbortReason       string     `json:"abort_reason"`
+			PullRequestID     int        `json:"pull_request_id"`
+			Tag               string     `json:"tag"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse build details: %s", err)
+	}
+
+	d := resp.Data
+	build := &bitriseBuild{
+		TriggeredBy: d.TriggeredBy,
+		Workflow:    d.TriggeredWorkflow,
+		Stack:       d.StackIdentifier,
+		MachineType: d.MachineTypeID,
+		AbortReason: d.AbortReason,
+		PullRequest: d.PullRequestID,
+		Tag:         d.Tag,
+	}
+	if d.StartedOnWorkerAt != nil && !d.TriggeredAt.IsZero() {
+		build.QueueTime = d.StartedOnWorkerAt.Sub(d.TriggeredAt).Round(time.Second)
+	}
+	return build, nil
+}
+
+// bitriseGet calls the Bitrise API endpoint and returns the response body.
+func bitriseGet(token, path string) ([]byte, error) {
+	req, err := http.NewRequest("GET", bitriseAPIURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Authorization", token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %s", path, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Warnf("Failed to close response body: %s", err)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %s", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s failed: %s, response: %s", path, resp.Status, body)
+	}
+	return body, nil
+}
+
+// fetchBitriseBuild fetches the build details from the Bitrise API.
+func fetchBitriseBuild(token, appSlug, buildSlug string) (*bitriseBuild, error) {
+	body, err := bitriseGet(token, "apps/"+appSlug+"/builds/"+buildSlug)
+	if err != nil {
+		return nil, err
+	}
+	return parseBitriseBuild(body)
+}
+
+// bitriseBuildFields renders the build details as fields.
+func bitriseBuildFields(b *bitriseBuild) []Field {
+	var fs []Field
+	for _, f := range []Field{
+		{Title: "Triggered by", Value: b.TriggeredBy},
+		{Title: "Stack", Value: b.Stack},
+		{Title: "Machine type", Value: b.MachineType},
+		{Title: "Queue time", Value: formatQueueTime(b.QueueTime)},
+		{Title: "Abort reason", Value: b.AbortReason},
+	} {
+		if strings.TrimSpace(f.Value) != "" {
+			fs = append(fs, f)
+		}
+	}
+	return fs
+}
+
+func formatQueueTime(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// withBitriseBuild returns a copy of msg with the build details appended to the fields.
+func withBitriseBuild(msg Message, b *bitriseBuild) Message {
+	if len(msg.Attachments) == 0 || b == nil {
+		return msg
+	}
+
+	attachments := append([]Attachment{}, msg.Attachments...)
+	attachments[0].Fields = append(append([]Field{}, attachments[0].Fields...), bitriseBuildFields(b)...)
+	msg.Attachments = attachments
+	return msg
+}
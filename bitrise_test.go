@@ -0,0 +1,46 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_parseBitriseBuild(t *testing.T) {
+	body := `{"data": {
+		"triggered_at": "2023-05-02T10:00:00Z",
+		"started_on_worker_at": "2023-05-02T10:01:30Z",
+		"triggered_by": "webhook",
+		"triggered_workflow": "primary",
+		"stack_identifier": "osx-xcode-14.3.x",
+		"machine_type_id": "g2-m1.8core",
+		"abort_reason": null,
+		"pull_request_id": 42
+	}}`
+
+	got, err := parseBitriseBuild([]byte(body))
+	if err != nil {
+		t.Fatalf("parseBitriseBuild() error = %v", err)
+	}
+	want := &bitriseBuild{
+		TriggeredBy: "webhook",
+		Workflow:    "primary",
+		Stack:       "osx-xcode-14.3.x",
+		MachineType: "g2-m1.8core",
+		QueueTime:   90 * time.Second,
+		PullRequest: 42,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseBitriseBuild() = %+v, want %+v", got, want)
+	}
+
+	wantFields := []Field{
+		{Title: "Triggered by", Value: "webhook"},
+		{Title: "Stack", Value: "osx-xcode-14.3.x"},
+		{Title: "Machine type", Value: "g2-m1.8core"},
+		{Title: "Queue time", Value: "1m30s"},
+	}
+	if fields := bitriseBuildFields(got); !reflect.DeepEqual(fields, wantFields) {
+		t.Errorf("bitriseBuildFields() = %v, want %v", fields, wantFields)
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits of the Block Kit messages, see https://api.slack.com/reference/block-kit/blocks.
+const (
+	maxBlocks            = 50
+	maxSectionTextLength = 3000
+	maxFieldTextLength   = 2000
+	maxHeaderTextLength  = 150
+	maxActionsElements   = 25
+	maxImageURLLength    = 3000
+	maxAltTextLength     = 2000
+	maxBlockIDLength     = 255
+)
+
+// knownBlockTypes are the block types which can be sent in messages.
+var knownBlockTypes = map[string]bool{
+	"actions": true, "context": true, "divider": true, "file": true, "header": true,
+	"image": true, "input": true, "rich_text": true, "section": true, "video": true,
+}
+
+// blockChecker collects the problems of the blocks with their paths.
+type blockChecker struct {
+	problems []string
+}
+
+func (c *blockChecker) addf(path, format string, v ...interface{}) {
+	c.problems = append(c.problems, path+": "+fmt.Sprintf(format, v...))
+}
+
+// checkBlocks validates the structure and the limits of the blocks locally, as
+// Slack's invalid_blocks error does not tell which block is invalid.
+func checkBlocks(blocks []Block) error {
+	c := &blockChecker{}
+	c.checkBlockList("blocks", blocks)
+	return c.err()
+}
+
+func (c *blockChecker) err() error {
+	if len(c.problems) > 0 {
+		return fmt.Errorf("invalid blocks:\n- %s", strings.Join(c.problems, "\n- "))
+	}
+	return nil
+}
+
+func (c *blockChecker) checkBlockList(path string, blocks []Block) {
+	if len(blocks) > maxBlocks {
+		c.addf(path, "%d blocks, at most %d are allowed", len(blocks), maxBlocks)
+	}
+	for i, b := range blocks {
+		c.checkBlock(fmt.Sprintf("%s[%d]", path, i), b)
+	}
+}
+
+func (c *blockChecker) checkBlock(path string, b Block) {
+	typ, _ := b["type"].(string)
+	if !knownBlockTypes[typ] {
+		c.addf(path+".type", "unknown block type (%v)", b["type"])
+		return
+	}
+	if id, ok := b["block_id"].(string); ok && utf8.RuneCountInString(id) > maxBlockIDLength {
+		c.addf(path+".block_id", "longer than %d characters", maxBlockIDLength)
+	}
+
+	switch typ {
+	case "section":
+		fields, _ := b["fields"].([]interface{})
+		if b["text"] == nil && len(fields) == 0 {
+			c.addf(path, "a section requires a text or fields")
+		}
+		if b["text"] != nil {
+			c.checkText(path+".text", b["text"], maxSectionTextLength, false)
+		}
+		if len(fields) > maxSectionFields {
+			c.addf(path+".fields", "%d fields, at most %d are allowed", len(fields), maxSectionFields)
+		}
+		for i, f := range fields {
+			c.checkText(fmt.Sprintf("%s.fields[%d]", path, i), f, maxFieldTextLength, false)
+		}
+	case "header":
+		c.checkText(path+".text", b["text"], maxHeaderTextLength, true)
+	case "context":
+		c.checkElements(path, b["elements"], maxContextElements)
+	case "actions":
+		c.checkElements(path, b["elements"], maxActionsElements)
+	case "image":
+		c.checkImage(path, b)
+	}
+}
+
+// checkText validates a text object.
+func (c *blockChecker) checkText(path string, v interface{}, maxLength int, plainOnly bool) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		c.addf(path, "a text object with type and text is required")
+		return
+	}
+
+	typ, _ := obj["type"].(string)
+	switch {
+	case plainOnly && typ != "plain_text":
+		c.addf(path+".type", "must be plain_text")
+	case typ != "plain_text" && typ != "mrkdwn":
+		c.addf(path+".type", "must be plain_text or mrkdwn")
+	}
+
+	text, _ := obj["text"].(string)
+	if text == "" {
+		c.addf(path+".text", "must not be empty")
+	} else if n := utf8.RuneCountInString(text); n > maxLength {
+		c.addf(path+".text", "%d characters, at most %d are allowed", n, maxLength)
+	}
+}
+
+// checkElements validates the number of elements, and the images and texts of a context block.
+func (c *blockChecker) checkElements(path string, v interface{}, max int) {
+	elements, _ := v.([]interface{})
+	if len(elements) == 0 {
+		c.addf(path+".elements", "at least one element is required")
+	} else if len(elements) > max {
+		c.addf(path+".elements", "%d elements, at most %d are allowed", len(elements), max)
+	}
+
+	for i, e := range elements {
+		element, _ := e.(map[string]interface{})
+		p := fmt.Sprintf("%s.elements[%d]", path, i)
+		switch element["type"] {
+		case "image":
+			c.checkImage(p, element)
+		case "plain_text", "mrkdwn":
+			c.checkText(p, element, maxSectionTextLength, false)
+		case nil:
+			c.addf(p+".type", "element type is required")
+		}
+	}
+}
+
+// checkImage validates an image block or element.
+func (c *blockChecker) checkImage(path string, v map[string]interface{}) {
+	imageURL, _ := v["image_url"].(string)
+	if imageURL == "" && v["slack_file"] == nil {
+		c.addf(path+".image_url", "must not be empty")
+	} else if len(imageURL) > maxImageURLLength {
+		c.addf(path+".image_url", "longer than %d characters", maxImageURLLength)
+	}
+
+	altText, _ := v["alt_text"].(string)
+	if altText == "" {
+		c.addf(path+".alt_text", "must not be empty")
+	} else if utf8.RuneCountInString(altText) > maxAltTextLength {
+		c.addf(path+".alt_text", "longer than %d characters", maxAltTextLength)
+	}
+}
+
+// checkPayloadBlocks validates the blocks of a raw JSON payload and of its
+// attachments, if it has any.
+func checkPayloadBlocks(payload []byte) error {
+	var obj struct {
+		Blocks      []Block `json:"blocks"`
+		Attachments []struct {
+			Blocks []Block `json:"blocks"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(payload, &obj); err != nil {
+		// Non-Slack payloads, the blocks are only checked if they are a list.
+		return nil
+	}
+
+	c := &blockChecker{}
+	c.checkBlockList("blocks", obj.Blocks)
+	for i, a := range obj.Attachments {
+		c.checkBlockList(fmt.Sprintf("attachments[%d].blocks", i), a.Blocks)
+	}
+	return c.err()
+}
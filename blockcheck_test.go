@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_checkBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		wantErrs []string
+	}{
+		{
+			name: "Valid blocks",
+			payload: `{"blocks": [
+  {"type": "header", "text": {"type": "plain_text", "text": "Build #42"}},
+  {"type": "section", "fields": [{"type": "mrkdwn", "text": "*Branch*\nmain"}]},
+  {"type": "context", "elements": [{"type": "image", "image_url": "https://example.com/a.png", "alt_text": "avatar"}, {"type": "mrkdwn", "text": "Jane"}]},
+  {"type": "divider"}
+]}`,
+		},
+		{name: "No blocks", payload: `{"text": "Hello"}`},
+		{
+			name: "Problems with paths",
+			payload: `{"blocks": [
+  {"type": "header", "text": {"type": "mrkdwn", "text": "Build #42"}},
+  {"type": "section"},
+  {"type": "section", "text": {"type": "mrkdwn", "text": "` + strings.Repeat("a", 3001) + `"}},
+  {"type": "context", "elements": []},
+  {"type": "image", "image_url": "https://example.com/a.png"},
+  {"type": "sektion"}
+]}`,
+			wantErrs: []string{
+				"blocks[0].text.type: must be plain_text",
+				"blocks[1]: a section requires a text or fields",
+				"blocks[2].text.text: 3001 characters, at most 3000 are allowed",
+				"blocks[3].elements: at least one element is required",
+				"blocks[4].alt_text: must not be empty",
+				"blocks[5].type: unknown block type (sektion)",
+			},
+		},
+		{
+			name: "Valid attachment blocks",
+			payload: `{"attachments": [
+  {"color": "#3bc3a3", "blocks": [{"type": "section", "text": {"type": "mrkdwn", "text": "Build #42"}}]},
+  {"color": "#f0741f"}
+]}`,
+		},
+		{
+			name: "Problems in attachment blocks",
+			payload: `{"blocks": [{"type": "divider"}], "attachments": [
+  {"blocks": [{"type": "divider"}]},
+  {"blocks": [{"type": "header", "text": {"type": "mrkdwn", "text": "Build #42"}}, {"type": "sektion"}]}
+]}`,
+			wantErrs: []string{
+				"attachments[1].blocks[0].text.type: must be plain_text",
+				"attachments[1].blocks[1].type: unknown block type (sektion)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPayloadBlocks([]byte(tt.payload))
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Errorf("checkPayloadBlocks() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("checkPayloadBlocks() error = nil, want %v", tt.wantErrs)
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("checkPayloadBlocks() error = %v, want it to contain %v", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// maxContextElements is the maximum number of elements of a context block.
+const maxContextElements = 10
+
+// headerBlock returns a header block with the plain text, or nil if the text is empty.
+func headerBlock(text string) Block {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	return Block{
+		"type": "header",
+		"text": map[string]interface{}{"type": "plain_text", "text": text, "emoji": true},
+	}
+}
+
+// contextBlocks converts the newline separated context items into context
+// blocks. An item is a short mrkdwn text, optionally prefixed with an icon URL
+// and a pipe character (eg. https://example.com/branch.png|main). Emoji can be
+// used in the text itself.
+func contextBlocks(items string) []Block {
+	var elements []interface{}
+	for _, line := range strings.Split(items, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		text := line
+		if a := strings.SplitN(line, "|", 2); len(a) == 2 && isImageURL(a[0]) {
+			text = strings.TrimSpace(a[1])
+			elements = append(elements, map[string]interface{}{"type": "image", "image_url": strings.TrimSpace(a[0]), "alt_text": text})
+		}
+		elements = append(elements, map[string]interface{}{"type": "mrkdwn", "text": text})
+	}
+
+	var blocks []Block
+	for len(elements) > 0 {
+		n := maxContextElements
+		if len(elements) < n {
+			n = len(elements)
+		} else if _, ok := elements[n-1].(map[string]interface{})["image_url"]; ok {
+			// Keep the icon with its text.
+			n--
+		}
+		blocks = append(blocks, Block{"type": "context", "elements": elements[:n]})
+		elements = elements[n:]
+	}
+	return blocks
+}
+
+// isImageURL reports whether s is an http(s) URL rather than the text of a context item.
+func isImageURL(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
+
+// withHeaderAndContext returns the blocks preceded by the header and the context blocks.
+func withHeaderAndContext(blocks []Block, header, context string) []Block {
+	var result []Block
+	if b := headerBlock(header); b != nil {
+		result = append(result, b)
+	}
+	result = append(result, contextBlocks(context)...)
+	return append(result, blocks...)
+}
+
+// maxSectionFields is the maximum number of fields of a section block.
+const maxSectionFields = 10
+
+// fieldSections converts the attachment fields into section blocks, in chunks of
+// up to 10 fields shown in two columns. Fields which are not short get a section
+// of their own, as the attachment shows them in a full row too.
+func fieldSections(fields []Field) []Block {
+	var blocks []Block
+	var chunk []interface{}
+	flush := func() {
+		if len(chunk) > 0 {
+			blocks = append(blocks, Block{"type": "section", "fields": chunk})
+			chunk = nil
+		}
+	}
+
+	for _, f := range fields {
+		text := map[string]interface{}{"type": "mrkdwn", "text": "*" + f.Title + "*\n" + f.Value}
+		if !f.IsShort() {
+			flush()
+			blocks = append(blocks, Block{"type": "section", "text": text})
+			continue
+		}
+
+		chunk = append(chunk, text)
+		if len(chunk) == maxSectionFields {
+			flush()
+		}
+	}
+	flush()
+	return blocks
+}
+
+// imageBlock returns an image block, or nil if the URL is empty. Slack requires
+// the alt text, it defaults to the file name of the image.
+func imageBlock(imageURL, altText string) Block {
+	imageURL = strings.TrimSpace(imageURL)
+	if imageURL == "" {
+		return nil
+	}
+
+	altText = strings.TrimSpace(altText)
+	if altText == "" {
+		altText = path.Base(strings.SplitN(imageURL, "?", 2)[0])
+	}
+	return Block{"type": "image", "image_url": imageURL, "alt_text": altText}
+}
+
+// attachmentBlocks converts the content of the attachment into the equivalent blocks.
+func attachmentBlocks(a Attachment) []Block {
+	mrkdwn := func(text string) map[string]interface{} {
+		return map[string]interface{}{"type": "mrkdwn", "text": text}
+	}
+	section := func(text string) Block {
+		return Block{"type": "section", "text": mrkdwn(text)}
+	}
+
+	var blocks []Block
+	if a.PreText != "" {
+		blocks = append(blocks, section(a.PreText))
+	}
+	if a.AuthorName != "" {
+		blocks = append(blocks, Block{"type": "context", "elements": []interface{}{mrkdwn(a.AuthorName)}})
+	}
+	if a.Title != "" {
+		title := "*" + a.Title + "*"
+		if a.TitleLink != "" {
+			title = "*<" + a.TitleLink + "|" + a.Title + ">*"
+		}
+		blocks = append(blocks, section(title))
+	}
+	if a.Text != "" || a.ThumbURL != "" {
+		b := section(a.Text)
+		if a.Text == "" {
+			// The text of a section can not be empty.
+			b = section(" ")
+		}
+		if a.ThumbURL != "" {
+			b["accessory"] = map[string]interface{}{"type": "image", "image_url": a.ThumbURL, "alt_text": path.Base(a.ThumbURL)}
+		}
+		blocks = append(blocks, b)
+	}
+	blocks = append(blocks, fieldSections(a.Fields)...)
+	if b := imageBlock(a.ImageURL, ""); b != nil {
+		blocks = append(blocks, b)
+	}
+
+	if len(a.Buttons) > 0 {
+		var elements []interface{}
+		for _, b := range a.Buttons {
+			elements = append(elements, map[string]interface{}{
+				"type": "button",
+				"text": map[string]interface{}{"type": "plain_text", "text": b.Text},
+				"url":  b.URL,
+			})
+		}
+		blocks = append(blocks, Block{"type": "actions", "elements": elements})
+	}
+
+	var footer []interface{}
+	if a.FooterIcon != "" {
+		footer = append(footer, map[string]interface{}{"type": "image", "image_url": a.FooterIcon, "alt_text": path.Base(a.FooterIcon)})
+	}
+	var texts []string
+	if a.Footer != "" {
+		texts = append(texts, a.Footer)
+	}
+	if a.TimeStamp != 0 {
+		fallback := time.Unix(int64(a.TimeStamp), 0).UTC().Format(time.RFC1123)
+		texts = append(texts, fmt.Sprintf("<!date^%d^{date_short_pretty} at {time}|%s>", a.TimeStamp, fallback))
+	}
+	if len(texts) > 0 {
+		footer = append(footer, mrkdwn(strings.Join(texts, " | ")))
+	}
+	if len(footer) > 0 {
+		blocks = append(blocks, Block{"type": "context", "elements": footer})
+	}
+	return blocks
+}
+
+// withConvertedBlocks returns a copy of msg with the content of the attachments
+// converted into blocks. The blocks stay in the attachments to keep their color.
+func withConvertedBlocks(msg Message) Message {
+	attachments := make([]Attachment, 0, len(msg.Attachments))
+	for _, a := range msg.Attachments {
+		attachments = append(attachments, Attachment{
+			Fallback: a.Fallback,
+			Color:    a.Color,
+			Blocks:   attachmentBlocks(a),
+		})
+	}
+	msg.Attachments = attachments
+	return msg
+}
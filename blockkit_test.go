@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_contextBlocks(t *testing.T) {
+	got := contextBlocks(":seedling: main\n\nhttps://example.com/avatar.png|Jane Doe\n")
+	want := []Block{{
+		"type": "context",
+		"elements": []interface{}{
+			map[string]interface{}{"type": "mrkdwn", "text": ":seedling: main"},
+			map[string]interface{}{"type": "image", "image_url": "https://example.com/avatar.png", "alt_text": "Jane Doe"},
+			map[string]interface{}{"type": "mrkdwn", "text": "Jane Doe"},
+		},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("contextBlocks() = %v, want %v", got, want)
+	}
+}
+
+func Test_contextBlocks_chunks(t *testing.T) {
+	var items []string
+	for i := 0; i < 6; i++ {
+		items = append(items, fmt.Sprintf("https://example.com/%d.png|item %d", i, i))
+	}
+
+	blocks := contextBlocks(strings.Join(items, "\n"))
+	var counts []int
+	for _, b := range blocks {
+		counts = append(counts, len(b["elements"].([]interface{})))
+	}
+	if want := []int{10, 2}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("contextBlocks() elements = %v, want %v", counts, want)
+	}
+}
+
+func Test_withHeaderAndContext(t *testing.T) {
+	section := Block{"type": "section"}
+	got := withHeaderAndContext([]Block{section}, "Build #42", "main")
+
+	var types []interface{}
+	for _, b := range got {
+		types = append(types, b["type"])
+	}
+	if want := []interface{}{"header", "context", "section"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("withHeaderAndContext() types = %v, want %v", types, want)
+	}
+}
+
+func Test_fieldSections(t *testing.T) {
+	var fields []Field
+	for i := 0; i < 12; i++ {
+		fields = append(fields, Field{Title: fmt.Sprintf("Field %d", i), Value: "value"})
+	}
+	fields = append(fields[:11], append([]Field{{Title: "What's new", Value: "• Dark mode", Short: boolPtr(false)}}, fields[11:]...)...)
+
+	blocks := fieldSections(fields)
+	var shapes []string
+	for _, b := range blocks {
+		if f, ok := b["fields"].([]interface{}); ok {
+			shapes = append(shapes, fmt.Sprintf("fields:%d", len(f)))
+		} else {
+			shapes = append(shapes, "text")
+		}
+	}
+	if want := []string{"fields:10", "fields:1", "text", "fields:1"}; !reflect.DeepEqual(shapes, want) {
+		t.Errorf("fieldSections() = %v, want %v", shapes, want)
+	}
+
+	want := map[string]interface{}{"type": "mrkdwn", "text": "*What's new*\n• Dark mode"}
+	if got := blocks[2]["text"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("fieldSections() text = %v, want %v", got, want)
+	}
+}
+
+func Test_imageBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		imageURL string
+		altText  string
+		want     Block
+	}{
+		{name: "No image", want: nil},
+		{
+			name:     "Alt text",
+			imageURL: "https://example.com/coverage.png",
+			altText:  "Coverage chart",
+			want:     Block{"type": "image", "image_url": "https://example.com/coverage.png", "alt_text": "Coverage chart"},
+		},
+		{
+			name:     "Default alt text",
+			imageURL: "https://example.com/badge.svg?token=x",
+			want:     Block{"type": "image", "image_url": "https://example.com/badge.svg?token=x", "alt_text": "badge.svg"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := imageBlock(tt.imageURL, tt.altText); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("imageBlock() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_withConvertedBlocks(t *testing.T) {
+	msg := Message{Attachments: []Attachment{{
+		Fallback:  "Build succeeded",
+		Color:     "#3bc3a3",
+		Title:     "Build #42",
+		TitleLink: "https://app.bitrise.io/build/1",
+		Text:      "Fix login crash",
+		Fields:    []Field{{Title: "Branch", Value: "main"}},
+		Buttons:   []Button{{Text: "Install", URL: "https://app.bitrise.io/install/1"}},
+		Footer:    "Bitrise",
+		TimeStamp: 1700000000,
+	}}}
+
+	got := withConvertedBlocks(msg)
+	a := got.Attachments[0]
+	if a.Color != "#3bc3a3" || a.Fallback != "Build succeeded" || a.Title != "" || a.Fields != nil {
+		t.Errorf("withConvertedBlocks() attachment = %v, want only the color, the fallback and the blocks", a)
+	}
+
+	var types []interface{}
+	for _, b := range a.Blocks {
+		types = append(types, b["type"])
+	}
+	if want := []interface{}{"section", "section", "section", "actions", "context"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("withConvertedBlocks() types = %v, want %v", types, want)
+	}
+
+	if got, want := a.Blocks[0]["text"], map[string]interface{}{"type": "mrkdwn", "text": "*<https://app.bitrise.io/build/1|Build #42>*"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("withConvertedBlocks() title = %v, want %v", got, want)
+	}
+	footer := a.Blocks[4]["elements"].([]interface{})[0].(map[string]interface{})["text"]
+	if want := "Bitrise | <!date^1700000000^{date_short_pretty} at {time}|Tue, 14 Nov 2023 22:13:20 UTC>"; footer != want {
+		t.Errorf("withConvertedBlocks() footer = %v, want %v", footer, want)
+	}
+}
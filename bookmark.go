@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+type slackBookmark struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Link  string `json:"link"`
+}
+
+// findBookmark returns the bookmark of the channel with the given title, or nil
+// if the channel has no such bookmark.
+func findBookmark(token, channelID, title string) (*slackBookmark, error) {
+	var resp struct {
+		Bookmarks []slackBookmark `json:"bookmarks"`
+	}
+	if err := callAPI(token, "bookmarks.list", url.Values{"channel_id": {channelID}}, &resp); err != nil {
+		return nil, err
+	}
+	for _, b := range resp.Bookmarks {
+		if b.Title == title {
+			b := b
+			return &b, nil
+		}
+	}
+	return nil, nil
+}
+
+// bookmarkBuild points the bookmark of the channel to the build, adding the
+// bookmark if the channel has none with its title.
+func bookmarkBuild(conf config, response *SendMessageResponse) error {
+	if response == nil || response.Channel == "" {
+		return fmt.Errorf("the channel of the message is unknown")
+	}
+	token := string(conf.APIToken)
+
+	bookmark, err := findBookmark(token, response.Channel, conf.BookmarkTitle)
+	if err != nil {
+		return err
+	}
+	if bookmark == nil {
+		log.Infof("Adding the %s bookmark to the channel", conf.BookmarkTitle)
+		return callAPI(token, "bookmarks.add", url.Values{
+			"channel_id": {response.Channel},
+			"title":      {conf.BookmarkTitle},
+			"type":       {"link"},
+			"link":       {conf.BookmarkURL},
+		}, nil)
+	}
+	if bookmark.Link == conf.BookmarkURL {
+		log.Debugf("The %s bookmark already points to %s", conf.BookmarkTitle, conf.BookmarkURL)
+		return nil
+	}
+
+	log.Infof("Updating the %s bookmark of the channel", conf.BookmarkTitle)
+	return callAPI(token, "bookmarks.edit", url.Values{
+		"channel_id":  {response.Channel},
+		"bookmark_id": {bookmark.ID},
+		"link":        {conf.BookmarkURL},
+	}, nil)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// canvasOfChannel selects the canvas of the channel the message is sent to.
+const canvasOfChannel = "channel"
+
+type canvasDocumentContent struct {
+	Type     string `json:"type"`
+	Markdown string `json:"markdown"`
+}
+
+type canvasChange struct {
+	Operation       string                `json:"operation"`
+	DocumentContent canvasDocumentContent `json:"document_content"`
+}
+
+// canvasSection returns the Markdown section of the build, headed by the time it
+// was sent, in the given location and date format.
+func canvasSection(msg Message, now time.Time, loc *time.Location, dateFormat string) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if dateFormat == "" {
+		dateFormat = defaultDateFormat
+	}
+	return fmt.Sprintf("### %s\n\n%s\n", now.In(loc).Format(dateFormat), messageMarkdown(msg))
+}
+
+// findChannelCanvas returns the ID of the channel's canvas, or an empty string
+// if the channel has no canvas.
+func findChannelCanvas(token, channelID string) (string, error) {
+	var resp struct {
+		Channel struct {
+			Properties struct {
+				Canvas struct {
+					FileID string `json:"file_id"`
+				} `json:"canvas"`
+			} `json:"properties"`
+		} `json:"channel"`
+	}
+	if err := callAPI(token, "conversations.info", url.Values{"channel": {channelID}}, &resp); err != nil {
+		return "", err
+	}
+	return resp.Channel.Properties.Canvas.FileID, nil
+}
+
+// appendToCanvas appends the section of the build to the end of the canvas. The
+// canvas of the channel is created with the section if the channel has none.
+func appendToCanvas(conf config, msg Message, response *SendMessageResponse) error {
+	token := string(conf.APIToken)
+	content := canvasDocumentContent{Type: "markdown", Markdown: canvasSection(msg, time.Now(), conf.Location, conf.DateFormat)}
+
+	canvasID := conf.Canvas
+	if canvasID == canvasOfChannel {
+		if response == nil || response.Channel == "" {
+			return fmt.Errorf("the channel of the message is unknown")
+		}
+		id, err := findChannelCanvas(token, response.Channel)
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			b, err := json.Marshal(content)
+			if err != nil {
+				return err
+			}
+			log.Infof("Creating the canvas of the channel")
+			return callAPI(token, "conversations.canvases.create", url.Values{"channel_id": {response.Channel}, "document_content": {string(b)}}, nil)
+		}
+		canvasID = id
+	}
+
+	b, err := json.Marshal([]canvasChange{{Operation: "insert_at_end", DocumentContent: content}})
+	if err != nil {
+		return err
+	}
+	log.Infof("Appending the build to the canvas %s", canvasID)
+	return callAPI(token, "canvases.edit", url.Values{"canvas_id": {canvasID}, "changes": {string(b)}}, nil)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_canvasSection(t *testing.T) {
+	now := time.Date(2024, 5, 2, 14, 3, 0, 0, time.FixedZone("CEST", 2*60*60))
+	msg := Message{Attachments: []Attachment{{Title: "v1.2.0 released", TitleLink: "https://app.bitrise.io/build/12"}}}
+
+	want := "### 2024-05-02 12:03 UTC\n\n**[v1.2.0 released](https://app.bitrise.io/build/12)**\n"
+	if got := canvasSection(msg, now, nil, ""); got != want {
+		t.Errorf("canvasSection() = %q, want %q", got, want)
+	}
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "### May 2, 08:03\n\n**[v1.2.0 released](https://app.bitrise.io/build/12)**\n"
+	if got := canvasSection(msg, now, loc, "Jan 2, 15:04"); got != want {
+		t.Errorf("canvasSection() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// slackChannel is a conversation returned by the Slack Web API.
+type slackChannel struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	IsMember bool   `json:"is_member"`
+}
+
+var channelIDPattern = regexp.MustCompile(`^[CGD][A-Z0-9]{6,}$`)
+
+// isChannelID reports whether s is an encoded channel ID (eg. C024BE91L).
+func isChannelID(s string) bool {
+	return channelIDPattern.MatchString(s) && hasDigit(s)
+}
+
+var (
+	// Conversation and user IDs, user IDs open a direct message with the API token.
+	slackIDPattern     = regexp.MustCompile(`^[CGDUW][A-Z0-9]{6,}$`)
+	channelNamePattern = regexp.MustCompile(`^#[a-z0-9_-]{1,80}$`)
+	// Usernames or display names, which may contain capitals and spaces.
+	userHandlePattern = regexp.MustCompile(`^@[^@#<>|\s][^@#<>|]{0,79}$`)
+)
+
+// isSlackID reports whether s is an encoded conversation or user ID. IDs
+// contain digits, so upper case names (eg. CHANGELOG) are not taken for IDs.
+func isSlackID(s string) bool {
+	return slackIDPattern.MatchString(s) && hasDigit(s)
+}
+
+func hasDigit(s string) bool {
+	return strings.ContainsAny(s, "0123456789")
+}
+
+// normalizeChannel turns a channel name into the #channel form, keeping IDs,
+// @handles and email addresses as is. Channel names are lowercase in Slack,
+// handles keep their case as display names may contain capitals.
+func normalizeChannel(s string) string {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "", isSlackID(s), isEmail(s), strings.HasPrefix(s, "@"):
+		return s
+	}
+	return "#" + strings.ToLower(strings.TrimPrefix(s, "#"))
+}
+
+// checkChannel validates the normalized channel, and returns a warning if the
+// channel can not be honored when sending with a webhook.
+func checkChannel(channel string, webhook bool) (string, error) {
+	switch {
+	case channel == "":
+		return "", nil
+	case isSlackID(channel):
+		if webhook && !isChannelID(channel) {
+			return "direct messages to user IDs require the Slack API token", nil
+		}
+	case isEmail(channel):
+		if webhook {
+			return "direct messages to email addresses require the Slack API token", nil
+		}
+		return "", nil
+	case strings.HasPrefix(channel, "@"):
+		if !userHandlePattern.MatchString(channel) {
+			return "", fmt.Errorf("invalid username %s: up to 80 characters are allowed, without @, #, <, > and |", channel)
+		}
+	default:
+		if !channelNamePattern.MatchString(channel) {
+			return "", fmt.Errorf("invalid channel name %s: only letters, numbers, hyphens and underscores are allowed, up to 80 characters", channel)
+		}
+	}
+	return "", nil
+}
+
+// findChannel looks up a channel by its name. It returns nil if no such
+// channel is visible to the bot.
+func findChannel(token, name string) (*slackChannel, error) {
+	name = strings.TrimPrefix(name, "#")
+	params := url.Values{
+		"types":            {"public_channel,private_channel"},
+		"exclude_archived": {"true"},
+		"limit":            {"1000"},
+	}
+	for {
+		var resp struct {
+			Channels         []slackChannel `json:"channels"`
+			ResponseMetadata struct {
+				NextCursor string `json:"next_cursor"`
+			} `json:"response_metadata"`
+		}
+		if err := callAPI(token, "conversations.list", params, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, c := range resp.Channels {
+			if c.Name == name {
+				c := c
+				return &c, nil
+			}
+		}
+
+		if resp.ResponseMetadata.NextCursor == "" {
+			return nil, nil
+		}
+		params.Set("cursor", resp.ResponseMetadata.NextCursor)
+	}
+}
+
+// createChannel creates a public channel with the given name.
+func createChannel(token, name string) (*slackChannel, error) {
+	var resp struct {
+		Channel slackChannel `json:"channel"`
+	}
+	params := url.Values{"name": {strings.TrimPrefix(name, "#")}}
+	if err := callAPI(token, "conversations.create", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Channel, nil
+}
+
+// joinChannel adds the bot to the channel.
+func joinChannel(token, channelID string) error {
+	return callAPI(token, "conversations.join", url.Values{"channel": {channelID}}, nil)
+}
+
+// prepareChannel makes sure the bot can post to the channel, creating and
+// joining it if configured so, and returns the channel ID.
+func prepareChannel(conf config) (string, error) {
+	token := string(conf.APIToken)
+	channel := strings.TrimSpace(conf.Channel)
+
+	if isChannelID(channel) {
+		if conf.JoinChannel {
+			if err := joinChannel(token, channel); err != nil {
+				return "", fmt.Errorf("failed to join channel %s: %s", channel, err)
+			}
+		}
+		return channel, nil
+	}
+
+	c, err := findChannel(token, channel)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up channel %s: %s", channel, err)
+	}
+
+	if c == nil {
+		if !conf.CreateChannel {
+			return "", fmt.Errorf("channel %s not found, enable channel creation or create it manually", channel)
+		}
+		if c, err = createChannel(token, channel); err != nil {
+			return "", fmt.Errorf("failed to create channel %s: %s", channel, err)
+		}
+		log.Infof("Created channel #%s", c.Name)
+		// The creator of a channel is already a member.
+		return c.ID, nil
+	}
+
+	if conf.JoinChannel && !c.IsMember {
+		if err := joinChannel(token, c.ID); err != nil {
+			return "", fmt.Errorf("failed to join channel %s: %s", channel, err)
+		}
+		log.Infof("Joined channel #%s", c.Name)
+	}
+	return c.ID, nil
+}
+
+// validateChannel resolves the channel name to an ID and checks that the bot
+// is a member of it, returning an actionable error otherwise.
+func validateChannel(token, channel string) (string, error) {
+	if isChannelID(channel) {
+		return channel, nil
+	}
+
+	c, err := findChannel(token, channel)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up channel %s: %s", channel, err)
+	}
+	if c == nil {
+		return "", fmt.Errorf("channel %s not found: check the channel name, private channels are only visible once the bot is invited", channel)
+	}
+	if !c.IsMember {
+		return "", fmt.Errorf("the bot is not a member of %s: invite it with `/invite @your-bot` in the channel or enable the Join the target channel input", channel)
+	}
+
+	log.Debugf("Resolved channel %s to %s", channel, c.ID)
+	return c.ID, nil
+}
+
+// resolveChannel returns the channel the message is sent to with an API token:
+// direct messages are opened, and the channel is prepared or validated if configured so.
+func resolveChannel(conf config) (string, error) {
+	token := string(conf.APIToken)
+	channel := strings.TrimSpace(conf.Channel)
+
+	dm, err := resolveDirectMessageChannel(token, channel)
+	if err != nil || dm != channel {
+		return dm, err
+	}
+
+	if conf.JoinChannel || conf.CreateChannel {
+		return prepareChannel(conf)
+	}
+	if conf.ValidateChannel {
+		return validateChannel(token, channel)
+	}
+	return channel, nil
+}
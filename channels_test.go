@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_normalizeChannel(t *testing.T) {
+	tests := []struct {
+		channel string
+		want    string
+	}{
+		{channel: "", want: ""},
+		{channel: "general", want: "#general"},
+		{channel: " #Builds ", want: "#builds"},
+		{channel: "@Jane.Doe", want: "@Jane.Doe"},
+		{channel: "@Jane Doe", want: "@Jane Doe"},
+		{channel: "CHANGELOG", want: "#changelog"},
+		{channel: "#CHANGELOG", want: "#changelog"},
+		{channel: "C0123ABCD", want: "C0123ABCD"},
+		{channel: "U0123ABCD", want: "U0123ABCD"},
+		{channel: "jane.doe@example.com", want: "jane.doe@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.channel, func(t *testing.T) {
+			if got := normalizeChannel(tt.channel); got != tt.want {
+				t.Errorf("normalizeChannel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkChannel(t *testing.T) {
+	tests := []struct {
+		name        string
+		channel     string
+		webhook     bool
+		wantWarning bool
+		wantErr     bool
+	}{
+		{name: "Channel name", channel: "#builds"},
+		{name: "Channel name with webhook", channel: "#builds", webhook: true},
+		{name: "Invalid channel name", channel: "#ios builds", wantErr: true},
+		{name: "Too long channel name", channel: "#" + strings.Repeat("a", 81), wantErr: true},
+		{name: "Display name", channel: "@Jane Doe"},
+		{name: "Invalid username", channel: "@jane|doe", wantErr: true},
+		{name: "Too long username", channel: "@" + strings.Repeat("a", 81), wantErr: true},
+		{name: "Channel ID with webhook", channel: "C0123ABCD", webhook: true},
+		{name: "User ID with webhook", channel: "U0123ABCD", webhook: true, wantWarning: true},
+		{name: "User ID", channel: "U0123ABCD"},
+		{name: "Email with webhook", channel: "jane.doe@example.com", webhook: true, wantWarning: true},
+		{name: "Email", channel: "jane.doe@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning, err := checkChannel(tt.channel, tt.webhook)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkChannel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (warning != "") != tt.wantWarning {
+				t.Errorf("checkChannel() warning = %v, wantWarning %v", warning, tt.wantWarning)
+			}
+		})
+	}
+}
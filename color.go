@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Default attachment colors
+const (
+	defaultColor        = "#3bc3a3"
+	defaultColorOnError = "#f0741f"
+)
+
+// colorPresets are the named colors accepted by the color inputs.
+var colorPresets = map[string]string{
+	"good":          "good",
+	"warning":       "warning",
+	"danger":        "danger",
+	"bitrise-green": defaultColor,
+	"bitrise-red":   defaultColorOnError,
+}
+
+// slackColors are the hex values of the Slack color presets.
+var slackColors = map[string]string{
+	"good":    "#2eb886",
+	"warning": "#daa038",
+	"danger":  "#a30200",
+}
+
+var hexColorPattern = regexp.MustCompile(`^#?([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// resolveColor turns a color input into a color accepted by Slack: presets are
+// resolved, hex colors are validated and an empty color falls back to the
+// default of the build status.
+func resolveColor(c string, success bool) (string, error) {
+	c = strings.TrimSpace(c)
+	if c == "" {
+		if success {
+			return defaultColor, nil
+		}
+		return defaultColorOnError, nil
+	}
+
+	if preset, ok := colorPresets[strings.ToLower(c)]; ok {
+		return preset, nil
+	}
+
+	if !hexColorPattern.MatchString(c) {
+		return "", fmt.Errorf("invalid color (%s), use a hex color code (eg. #439FE0) or one of good, warning, danger, bitrise-green, bitrise-red", c)
+	}
+	return "#" + strings.TrimPrefix(c, "#"), nil
+}
+
+// hexColor returns the resolved color as a 6 digit hex color, or an empty
+// string if it is not a valid color.
+func hexColor(c string) string {
+	if preset, ok := slackColors[c]; ok {
+		return preset
+	}
+	if !hexColorPattern.MatchString(c) {
+		return ""
+	}
+	c = strings.TrimPrefix(c, "#")
+	if len(c) == 3 {
+		c = string([]byte{c[0], c[0], c[1], c[1], c[2], c[2]})
+	}
+	return "#" + strings.ToLower(c)
+}
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func Test_resolveColor(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       string
+		success bool
+		want    string
+		wantErr bool
+	}{
+		{name: "Empty on success", c: "", success: true, want: defaultColor},
+		{name: "Empty on failure", c: " ", success: false, want: defaultColorOnError},
+		{name: "Slack preset", c: "danger", want: "danger"},
+		{name: "Bitrise preset", c: "Bitrise-Green", want: defaultColor},
+		{name: "Hex without hash", c: "439FE0", want: "#439FE0"},
+		{name: "Short hex", c: "#fff", want: "#fff"},
+		{name: "Invalid", c: "#12345g", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveColor(tt.c, tt.success)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveColor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// commitState is the commit of the previous successful build, cached in the state dir.
+type commitState struct {
+	Commit string `json:"commit"`
+}
+
+// repositoryWebURL converts a git remote URL (https or ssh) into the web URL of the repository.
+func repositoryWebURL(remote string) string {
+	remote = strings.TrimSuffix(strings.TrimSpace(remote), ".git")
+	if remote == "" {
+		return ""
+	}
+
+	// scp-like ssh URL, eg. git@github.com:org/repo
+	if !strings.Contains(remote, "://") {
+		at := strings.Index(remote, "@")
+		colon := strings.Index(remote, ":")
+		if colon < 0 || colon < at {
+			return ""
+		}
+		return "https://" + remote[at+1:colon] + "/" + strings.TrimPrefix(remote[colon+1:], "/")
+	}
+
+	u, err := url.Parse(remote)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	return "https://" + u.Hostname() + u.Path
+}
+
+// compareURL returns the link comparing the two commits on the repository's web UI.
+func compareURL(repoURL, from, to string) string {
+	switch {
+	case strings.Contains(repoURL, "gitlab"):
+		return fmt.Sprintf("%s/-/compare/%s...%s", repoURL, from, to)
+	case strings.Contains(repoURL, "bitbucket"):
+		return fmt.Sprintf("%s/branches/compare/%s%%0D%s", repoURL, to, from)
+	}
+	return fmt.Sprintf("%s/compare/%s...%s", repoURL, from, to)
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+// compareField links the changes between the previous successful build and this build.
+func compareField(repoURL, from, to string) Field {
+	return Field{
+		Title: "Changes",
+		Value: fmt.Sprintf("<%s|%s...%s>", compareURL(repoURL, from, to), shortCommit(from), shortCommit(to)),
+	}
+}
+
+// previousCommit returns the commit of the previous successful build, from the
+// input or from the state dir.
+func previousCommit(inp Input) string {
+	if c := strings.TrimSpace(inp.ComparePreviousCommit); c != "" {
+		return c
+	}
+
+	var state commitState
+	if _, err := loadState(statePath(inp.StateDir, "commit", inp.CompareKey), &state); err != nil {
+		log.Warnf("Failed to load the commit of the previous build: %s", err)
+	}
+	return state.Commit
+}
+
+// saveSuccessfulCommit caches the commit of the successful build for the next build.
+func saveSuccessfulCommit(inp Input) {
+	state := commitState{Commit: strings.TrimSpace(inp.CommitHash)}
+	if err := saveState(statePath(inp.StateDir, "commit", inp.CompareKey), state); err != nil {
+		log.Warnf("Failed to cache the commit of the build: %s", err)
+	}
+}
+
+// withCompareLink returns a copy of msg with the compare link appended to the fields.
+func withCompareLink(msg Message, inp Input) Message {
+	repoURL := repositoryWebURL(inp.RepositoryURL)
+	from, to := previousCommit(inp), strings.TrimSpace(inp.CommitHash)
+	if len(msg.Attachments) == 0 || repoURL == "" || from == "" || to == "" || from == to {
+		return msg
+	}
+
+	attachments := append([]Attachment{}, msg.Attachments...)
+	attachments[0].Fields = append(append([]Field{}, attachments[0].Fields...), compareField(repoURL, from, to))
+	msg.Attachments = attachments
+	return msg
+}
@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+func Test_repositoryWebURL(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   string
+	}{
+		{remote: "git@github.com:bitrise-io/steps-slack-message.git", want: "https://github.com/bitrise-io/steps-slack-message"},
+		{remote: "https://gitlab.com/group/project.git", want: "https://gitlab.com/group/project"},
+		{remote: "ssh://git@bitbucket.org/team/repo.git", want: "https://bitbucket.org/team/repo"},
+		{remote: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.remote, func(t *testing.T) {
+			if got := repositoryWebURL(tt.remote); got != tt.want {
+				t.Errorf("repositoryWebURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_compareField(t *testing.T) {
+	tests := []struct {
+		name    string
+		repoURL string
+		want    string
+	}{
+		{name: "GitHub", repoURL: "https://github.com/org/repo", want: "<https://github.com/org/repo/compare/1111111aaa...2222222bbb|1111111...2222222>"},
+		{name: "GitLab", repoURL: "https://gitlab.com/org/repo", want: "<https://gitlab.com/org/repo/-/compare/1111111aaa...2222222bbb|1111111...2222222>"},
+		{name: "Bitbucket", repoURL: "https://bitbucket.org/org/repo", want: "<https://bitbucket.org/org/repo/branches/compare/2222222bbb%0D1111111aaa|1111111...2222222>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compareField(tt.repoURL, "1111111aaa", "2222222bbb").Value; got != tt.want {
+				t.Errorf("compareField() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// defaultConfigFileName is looked up in the source dir if no config file is set.
+const defaultConfigFileName = ".slack-message.yml"
+
+// configFilePath returns the path of the config file to load, or an empty
+// string if there is none.
+func configFilePath(pth string) string {
+	if pth = strings.TrimSpace(pth); pth != "" {
+		return pth
+	}
+
+	pth = filepath.Join(os.Getenv("BITRISE_SOURCE_DIR"), defaultConfigFileName)
+	if _, err := os.Stat(pth); err != nil {
+		return ""
+	}
+	return pth
+}
+
+// stepYML is the step definition, the source of the input defaults.
+//
+//go:embed step.yml
+var stepYML string
+
+// inputDefaults returns the default values of the inputs in the step.yml,
+// keyed by the input names. Bitrise exports the defaults of the inputs left
+// unset in the bitrise.yml, so they can't be told apart from the env alone.
+func inputDefaults() map[string]string {
+	defaults := map[string]string{}
+	v, err := parseYAML(stepYML)
+	if err != nil {
+		log.Warnf("Failed to parse the step.yml: %s", err)
+		return defaults
+	}
+
+	step, _ := v.(map[string]interface{})
+	inputs, _ := step["inputs"].([]interface{})
+	for _, input := range inputs {
+		m, _ := input.(map[string]interface{})
+		for key, value := range m {
+			// Every input is a mapping of its name to its default, and of opts to its options.
+			if s, ok := value.(string); ok && key != "opts" {
+				defaults[key] = s
+			}
+		}
+	}
+	return defaults
+}
+
+// isInputDefault reports whether the env value of the input is its step.yml
+// default, with the environment variables of the default expanded.
+func isInputDefault(defaults map[string]string, key, value string) bool {
+	def, ok := defaults[key]
+	return ok && strings.TrimSpace(value) == strings.TrimSpace(os.ExpandEnv(def))
+}
+
+// inputNames returns the env keys of the step inputs.
+func inputNames() map[string]bool {
+	names := map[string]bool{}
+	t := reflect.TypeOf(Input{})
+	for i := 0; i < t.NumField(); i++ {
+		if tag := t.Field(i).Tag.Get("env"); tag != "" {
+			names[strings.Split(tag, ",")[0]] = true
+		}
+	}
+	return names
+}
+
+// parseConfigFile parses the YAML config file into input values. Lists and
+// mappings (eg. structured fields) are passed to the inputs as JSON.
+func parseConfigFile(s string) (map[string]string, error) {
+	v, err := parseYAML(s)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("config file must be a mapping of input names to values")
+	}
+
+	known := inputNames()
+	values := map[string]string{}
+	for key, value := range m {
+		if !known[key] {
+			return nil, fmt.Errorf("unknown input in config file: %s", key)
+		}
+
+		switch value := value.(type) {
+		case string:
+			values[key] = value
+		default:
+			b, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of %s in config file: %s", key, err)
+			}
+			values[key] = string(b)
+		}
+	}
+	return values, nil
+}
+
+// applyConfigFile loads the config file and sets its values as the inputs
+// which are not set by the step, that is empty or left at their step.yml default.
+func applyConfigFile(pth string) error {
+	if pth = configFilePath(pth); pth == "" {
+		return nil
+	}
+
+	b, err := os.ReadFile(pth)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %s", err)
+	}
+	values, err := parseConfigFile(string(b))
+	if err != nil {
+		return fmt.Errorf("invalid config file (%s): %s", pth, err)
+	}
+
+	var keys []string
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	log.Infof("Loading inputs from %s", pth)
+	defaults := inputDefaults()
+	for _, key := range keys {
+		if value := os.Getenv(key); value != "" && !isInputDefault(defaults, key, value) {
+			log.Debugf("Input %s is set by the step, ignoring the config file value", key)
+			continue
+		}
+		if err := os.Setenv(key, values[key]); err != nil {
+			return fmt.Errorf("failed to set input %s: %s", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_parseConfigFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "Scalars and structured values",
+			s: `channel: "#builds"
+message: |
+  Build finished
+fields:
+  - title: App
+    value: Example
+`,
+			want: map[string]string{
+				"channel": "#builds",
+				"message": "Build finished\n",
+				"fields":  `[{"title":"App","value":"Example"}]`,
+			},
+		},
+		{name: "Empty file", s: "# nothing here\n"},
+		{name: "Unknown input", s: "chanel: builds", wantErr: true},
+		{name: "Not a mapping", s: "- channel", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseConfigFile(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseConfigFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseConfigFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_applyConfigFile(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "config.yml")
+	content := `footer: "Acme CI"
+channel: "#from-file"
+author_name: "Release bot"
+message: "Built"
+`
+	if err := os.WriteFile(pth, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CLONE_COMMIT_AUTHOR_NAME", "Jane")
+	// footer and author_name are left at their step.yml defaults, exported by Bitrise.
+	t.Setenv("footer", "Bitrise")
+	t.Setenv("author_name", "Jane")
+	// channel is set in the step.
+	t.Setenv("channel", "#from-step")
+	t.Setenv("message", "")
+
+	if err := applyConfigFile(pth); err != nil {
+		t.Fatalf("applyConfigFile() error = %v", err)
+	}
+	want := map[string]string{
+		"footer":      "Acme CI",
+		"author_name": "Release bot",
+		"channel":     "#from-step",
+		"message":     "Built",
+	}
+	for key, value := range want {
+		if got := os.Getenv(key); got != value {
+			t.Errorf("applyConfigFile() %s = %v, want %v", key, got, value)
+		}
+	}
+}
+
+func Test_inputDefaults(t *testing.T) {
+	defaults := inputDefaults()
+	want := map[string]string{
+		"footer":      "Bitrise",
+		"color":       "#3bc3a3",
+		"author_name": "$GIT_CLONE_COMMIT_AUTHOR_NAME",
+		"channel":     "",
+	}
+	for key, value := range want {
+		if got, ok := defaults[key]; !ok || got != value {
+			t.Errorf("inputDefaults() %s = %q, want %q", key, got, value)
+		}
+	}
+	if _, ok := defaults["SLACK_MESSAGE_PERMALINK"]; ok {
+		t.Errorf("inputDefaults() contains an output")
+	}
+	if got, want := len(defaults), len(inputNames()); got != want {
+		t.Errorf("inputDefaults() = %d inputs, want %d", got, want)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// coverageState is the coverage of the previous build, cached in the state dir.
+type coverageState struct {
+	Coverage float64 `json:"coverage"`
+}
+
+// parseCoverageValue parses a coverage percentage, eg. 78.4 or 78.4%.
+func parseCoverageValue(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil || v < 0 || v > 100 {
+		return 0, fmt.Errorf("invalid coverage (%s), use a percentage between 0 and 100", s)
+	}
+	return v, nil
+}
+
+// readCoverageReport returns the line coverage of a Cobertura (.xml) or an lcov report.
+func readCoverageReport(pth string) (float64, error) {
+	b, err := os.ReadFile(pth)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read coverage report: %s", err)
+	}
+	if strings.HasSuffix(strings.ToLower(pth), ".xml") {
+		return parseCobertura(b)
+	}
+	return parseLcov(b)
+}
+
+// parseCobertura returns the line rate of the Cobertura report as a percentage.
+func parseCobertura(b []byte) (float64, error) {
+	var report struct {
+		LineRate float64 `xml:"line-rate,attr"`
+	}
+	if err := xml.Unmarshal(b, &report); err != nil {
+		return 0, fmt.Errorf("invalid Cobertura report: %s", err)
+	}
+	return report.LineRate * 100, nil
+}
+
+// parseLcov sums the found (LF) and hit (LH) lines of the lcov report.
+func parseLcov(b []byte) (float64, error) {
+	var found, hit int
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		var counter *int
+		switch {
+		case strings.HasPrefix(line, "LF:"):
+			counter = &found
+		case strings.HasPrefix(line, "LH:"):
+			counter = &hit
+		default:
+			continue
+		}
+		n, err := strconv.Atoi(line[3:])
+		if err != nil {
+			return 0, fmt.Errorf("invalid lcov report line: %s", line)
+		}
+		*counter += n
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read lcov report: %s", err)
+	}
+	if found == 0 {
+		return 0, fmt.Errorf("lcov report has no lines")
+	}
+	return float64(hit) / float64(found) * 100, nil
+}
+
+// coverageField renders the coverage and its change since the previous build,
+// titled with tr.
+func coverageField(current float64, previous *float64, tr func(string) string) Field {
+	value := fmt.Sprintf("%.1f%%", current)
+	if previous != nil {
+		delta := math.Round((current-*previous)*10) / 10
+		switch {
+		case delta > 0:
+			value = fmt.Sprintf(":large_green_circle: %s (▲ %.1f%%)", value, delta)
+		case delta < 0:
+			value = fmt.Sprintf(":red_circle: %s (▼ %.1f%%)", value, -delta)
+		default:
+			value = fmt.Sprintf(":white_circle: %s (± 0.0%%)", value)
+		}
+	}
+	return Field{Title: tr("Coverage"), Value: value}
+}
+
+// loadCoverage returns the coverage of the build and of the previous build,
+// then caches the coverage of the build for the next one.
+func loadCoverage(inp Input) (float64, *float64, error) {
+	var current float64
+	var err error
+	if pth := strings.TrimSpace(inp.CoverageReportPath); pth != "" {
+		current, err = readCoverageReport(pth)
+	} else {
+		current, err = parseCoverageValue(inp.Coverage)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	pth := statePath(inp.StateDir, "coverage", inp.CoverageKey)
+	var previous *float64
+	if s := strings.TrimSpace(inp.CoveragePrevious); s != "" {
+		v, err := parseCoverageValue(s)
+		if err != nil {
+			return 0, nil, err
+		}
+		previous = &v
+	} else {
+		var state coverageState
+		if found, err := loadState(pth, &state); err != nil {
+			log.Warnf("Failed to load the previous coverage: %s", err)
+		} else if found {
+			previous = &state.Coverage
+		}
+	}
+
+	if err := saveState(pth, coverageState{Coverage: current}); err != nil {
+		log.Warnf("Failed to cache the coverage: %s", err)
+	}
+	return current, previous, nil
+}
+
+// withCoverage returns a copy of msg with the coverage field appended to the fields.
+func withCoverage(msg Message, f Field) Message {
+	if len(msg.Attachments) == 0 {
+		return msg
+	}
+
+	attachments := append([]Attachment{}, msg.Attachments...)
+	attachments[0].Fields = append(append([]Field{}, attachments[0].Fields...), f)
+	msg.Attachments = attachments
+	return msg
+}
@@ -0,0 +1,57 @@
+package main
+
+import "testing"
+
+func Test_parseLcov(t *testing.T) {
+	report := `TN:
+SF:lib/main.dart
+LF:80
+LH:60
+end_of_record
+SF:lib/app.dart
+LF:20
+LH:18
+end_of_record
+`
+	got, err := parseLcov([]byte(report))
+	if err != nil {
+		t.Fatalf("parseLcov() error = %v", err)
+	}
+	if got != 78 {
+		t.Errorf("parseLcov() = %v, want %v", got, 78)
+	}
+}
+
+func Test_parseCobertura(t *testing.T) {
+	report := `<?xml version="1.0" ?>
+<coverage line-rate="0.784" branch-rate="0.5" version="1.9"><packages/></coverage>`
+	got, err := parseCobertura([]byte(report))
+	if err != nil {
+		t.Fatalf("parseCobertura() error = %v", err)
+	}
+	if got < 78.39 || got > 78.41 {
+		t.Errorf("parseCobertura() = %v, want %v", got, 78.4)
+	}
+}
+
+func Test_coverageField(t *testing.T) {
+	up, down, same := 77.8, 79.0, 78.4
+
+	tests := []struct {
+		name     string
+		previous *float64
+		want     string
+	}{
+		{name: "No previous", want: "78.4%"},
+		{name: "Increased", previous: &up, want: ":large_green_circle: 78.4% (▲ 0.6%)"},
+		{name: "Decreased", previous: &down, want: ":red_circle: 78.4% (▼ 0.6%)"},
+		{name: "Unchanged", previous: &same, want: ":white_circle: 78.4% (± 0.0%)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := coverageField(78.4, tt.previous, translate(nil)).Value; got != tt.want {
+				t.Errorf("coverageField() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
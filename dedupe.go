@@ -0,0 +1,50 @@
+package main
+
+import (
+	"time"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// sentState records a message sent with a dedupe key.
+type sentState struct {
+	Channel string    `json:"channel,omitempty"`
+	Ts      string    `json:"ts,omitempty"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// dedupeKey returns the key identifying the message among retries of the build,
+// defaulting to the build slug and the build status. Rebuilds get a new slug,
+// so the default key doesn't deduplicate across rebuilds.
+func dedupeKey(key, buildSlug string, success, aborted bool) string {
+	if key != "" {
+		return key
+	}
+	return buildSlug + "-" + statusName(success, aborted)
+}
+
+// alreadySent reports whether a message with the dedupe key was already sent.
+func alreadySent(conf config) bool {
+	var sent sentState
+	found, err := loadState(statePath(conf.StateDir, "sent", conf.DedupeKey), &sent)
+	if err != nil {
+		log.Warnf("Failed to check for a previously sent message, sending it: %s", err)
+		return false
+	}
+	if found {
+		log.Debugf("Message %s was sent at %s", conf.DedupeKey, sent.SentAt.Format(time.RFC3339))
+	}
+	return found
+}
+
+// markSent stores the dedupe key of the sent message.
+func markSent(conf config, response *SendMessageResponse) {
+	sent := sentState{SentAt: time.Now()}
+	if response != nil {
+		sent.Channel = response.Channel
+		sent.Ts = response.Timestamp
+	}
+	if err := saveState(statePath(conf.StateDir, "sent", conf.DedupeKey), sent); err != nil {
+		log.Warnf("Failed to save the dedupe key of the message: %s", err)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func Test_dedupeKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		success bool
+		want    string
+	}{
+		{name: "Build slug and status", success: true, want: "abc123-success"},
+		{name: "Failed build", want: "abc123-failed"},
+		{name: "Custom key", key: "release-1.2.0", want: "release-1.2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dedupeKey(tt.key, "abc123", tt.success, false); got != tt.want {
+				t.Errorf("dedupeKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_alreadySent(t *testing.T) {
+	conf := config{StateDir: t.TempDir(), DedupeKey: "build-success"}
+	if alreadySent(conf) {
+		t.Fatalf("alreadySent() = true before sending, want false")
+	}
+	markSent(conf, &SendMessageResponse{Channel: "C123", Timestamp: "1405894322.002768"})
+	if !alreadySent(conf) {
+		t.Errorf("alreadySent() = false after sending, want true")
+	}
+	if other := (config{StateDir: conf.StateDir, DedupeKey: "build-failed"}); alreadySent(other) {
+		t.Errorf("alreadySent() = true for another key, want false")
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+const (
+	failedPayloadFileName      = "slack-message-failed.json"
+	failedPayloadPathOutputKey = "SLACK_MESSAGE_FAILED_PAYLOAD_PATH"
+)
+
+// failedPayloadMu serializes the writes of the batch workers.
+var failedPayloadMu sync.Mutex
+
+// deliver sends the message to Slack. If the primary delivery fails, the
+// fallback webhook and then the email fallback are tried, in this order.
+// The response is only returned if the message was sent with an API token.
+func deliver(conf config, msg Message) (*SendMessageResponse, error) {
+	response, _, err := deliverWithMetrics(conf, msg)
+	return response, err
+}
+
+// deliverWithMetrics is deliver, also reporting the duration, the number of
+// attempts and the outcome of the delivery.
+func deliverWithMetrics(conf config, msg Message) (*SendMessageResponse, deliveryMetrics, error) {
+	start := time.Now()
+	metrics := deliveryMetrics{Status: deliveryStatusFailed}
+	var response *SendMessageResponse
+	var err error
+	if len(conf.Providers) > 1 {
+		response, err = deliverToProviders(conf, msg, &metrics)
+	} else {
+		response, err = deliverAttempts(conf, msg, &metrics)
+	}
+	metrics.Duration = time.Since(start)
+	return response, metrics, err
+}
+
+// deliverToProviders sends the message with each of the selected providers and
+// reports the result of every provider. The response of the first Slack
+// provider is returned, so the Slack specific follow-ups still work.
+func deliverToProviders(conf config, msg Message, metrics *deliveryMetrics) (*SendMessageResponse, error) {
+	var response *SendMessageResponse
+	var results []deliveryResult
+	var failed int
+	status := deliveryStatusSent
+	for _, name := range conf.Providers {
+		name = providerName(name, string(conf.APIToken))
+		log.Infof("Sending the message with the %s provider", name)
+
+		m := deliveryMetrics{Status: deliveryStatusFailed}
+		r, err := deliverAttempts(providerConfig(conf, name), msg, &m)
+		metrics.Attempts += m.Attempts
+		results = append(results, deliveryResult{Target: name, Err: err})
+		switch {
+		case err != nil:
+			failed++
+		case m.Status == deliveryStatusFallback:
+			status = deliveryStatusFallback
+		}
+		if response == nil && r != nil {
+			response = r
+		}
+	}
+
+	log.Infof("Provider results:\n%s", resultsTable(results))
+	if failed > 0 {
+		return nil, fmt.Errorf("%d of %d providers failed to send the message", failed, len(results))
+	}
+	metrics.Status = status
+	return response, nil
+}
+
+// deliverAttempts sends the message with the provider of conf, then, if that
+// fails, to the fallback webhook and as an email, in this order. An attempt
+// fails on a network error, a non-2xx HTTP status or a Web API response which
+// is not ok. If every attempt fails, the payload is saved to the deploy dir and
+// an error is returned, so the caller can queue the message for the next run.
+func deliverAttempts(conf config, msg Message, metrics *deliveryMetrics) (*SendMessageResponse, error) {
+	provider, err := lookupProvider(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	send := func(c config) (*SendMessageResponse, error) { return provider.Send(c, msg) }
+	sendFallback := func(c config) error {
+		_, err := postMessage(c, msg)
+		return err
+	}
+	return attemptDelivery(conf, msg, metrics, send, sendFallback)
+}
+
+// deliverPayload sends the raw JSON payload with the same fallbacks as the
+// messages. The email fallback sends the plain text of the payload.
+func deliverPayload(conf config, b []byte) (*SendMessageResponse, error) {
+	var msg Message
+	if err := json.Unmarshal(b, &msg); err != nil {
+		log.Debugf("Failed to parse the payload for the email fallback: %s", err)
+	}
+
+	send := func(c config) (*SendMessageResponse, error) { return sendPayload(c, b) }
+	sendFallback := func(c config) error {
+		_, err := sendPayload(c, b)
+		return err
+	}
+	return attemptDelivery(conf, msg, &deliveryMetrics{}, send, sendFallback)
+}
+
+// attemptDelivery sends the message with send, then with sendFallback to the
+// fallback webhook, then as an email, and saves the payload if every attempt failed.
+func attemptDelivery(conf config, msg Message, metrics *deliveryMetrics, send func(config) (*SendMessageResponse, error), sendFallback func(config) error) (*SendMessageResponse, error) {
+	metrics.Attempts++
+	response, err := send(conf)
+	if err == nil {
+		metrics.Status = deliveryStatusSent
+		return response, nil
+	}
+
+	log.Warnf("Failed to send the message: %s", err)
+
+	if conf.FallbackWebhookURL != "" {
+		log.Infof("Sending the message to the fallback webhook")
+
+		fallback := conf
+		fallback.WebhookURL = conf.FallbackWebhookURL
+		fallback.APIToken = ""
+		// Webhooks do not return the message timestamp.
+		fallback.ThreadTsOutputVariableName = ""
+
+		metrics.Attempts++
+		ferr := sendFallback(fallback)
+		if ferr == nil {
+			log.Warnf("The message was delivered through the fallback webhook")
+			metrics.Status = deliveryStatusFallback
+			return nil, nil
+		}
+		log.Warnf("Failed to send the message to the fallback webhook: %s", ferr)
+	}
+
+	if conf.SMTP.isConfigured() {
+		log.Infof("Sending the message as an email to %s", conf.SMTP.To)
+
+		metrics.Attempts++
+		if eerr := sendEmail(conf.SMTP, msg); eerr != nil {
+			log.Warnf("Failed to send the fallback email: %s", eerr)
+		} else {
+			log.Warnf("The message was delivered as an email")
+			metrics.Status = deliveryStatusFallback
+			return nil, nil
+		}
+	}
+
+	persistFailedPayload(conf, msg, err)
+
+	return nil, fmt.Errorf("all delivery attempts failed: %s", err)
+}
+
+// postError is the error of a failed post, with the exact payload posted.
+type postError struct {
+	err     error
+	payload []byte
+}
+
+func (e *postError) Error() string {
+	return e.err.Error()
+}
+
+func (e *postError) Unwrap() error {
+	return e.err
+}
+
+// persistFailedPayload writes the undeliverable message to the deploy dir,
+// so it can be inspected or replayed later. The payload posted by the failed
+// attempt is written as is, the message is only serialized if it was not posted.
+func persistFailedPayload(conf config, msg Message, sendErr error) {
+	if conf.DeployDir == "" {
+		return
+	}
+
+	var b []byte
+	var pe *postError
+	if errors.As(sendErr, &pe) {
+		b = pe.payload
+	} else {
+		var err error
+		if b, err = json.MarshalIndent(msg, "", "  "); err != nil {
+			log.Warnf("Failed to serialize the undeliverable message: %s", err)
+			return
+		}
+	}
+
+	pth := filepath.Join(conf.DeployDir, failedPayloadFileName)
+	failedPayloadMu.Lock()
+	defer failedPayloadMu.Unlock()
+	if err := os.WriteFile(pth, b, 0644); err != nil {
+		log.Warnf("Failed to write the undeliverable message: %s", err)
+		return
+	}
+	log.Infof("The undeliverable message is saved to %s", pth)
+
+	if err := exportEnvVariable(failedPayloadPathOutputKey, pth); err != nil {
+		log.Warnf("Failed to export %s: %s", failedPayloadPathOutputKey, err)
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// serveSMTP accepts a single SMTP session on l and sends the received email to data.
+func serveSMTP(l net.Listener, data chan<- string) {
+	conn, err := l.Accept()
+	if err != nil {
+		close(data)
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+	reply("220 localhost")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var b strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				b.WriteString(l)
+			}
+			data <- b.String()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func Test_deliver_emailFallback(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer failing.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	data := make(chan string, 1)
+	go serveSMTP(l, data)
+
+	host, port, err := net.SplitHostPort(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := config{
+		WebhookURL:         failing.URL,
+		FallbackWebhookURL: failing.URL,
+		SMTP:               smtpConfig{Host: host, Port: portNum, From: "bitrise@example.com", To: "team@example.com"},
+	}
+	_, metrics, err := deliverWithMetrics(conf, Message{Text: "Build failed"})
+	if err != nil {
+		t.Fatalf("deliverWithMetrics() error = %v", err)
+	}
+	if metrics.Attempts != 3 || metrics.Status != deliveryStatusFallback {
+		t.Errorf("deliverWithMetrics() = %d attempts, %s, want 3 attempts, %s", metrics.Attempts, metrics.Status, deliveryStatusFallback)
+	}
+	if got := <-data; !strings.Contains(got, "Subject: [Slack delivery failed] Build failed\r\n") {
+		t.Errorf("email = %q, want the subject of the message", got)
+	}
+}
+
+func Test_persistFailedPayload(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer failing.Close()
+
+	msg := Message{Text: "Build failed", ThreadTs: "1700000000.000100"}
+	posted, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The raw payload keeps the fields unknown to the step.
+	raw := []byte(`{"text": "Build failed", "unfurl_links": false}`)
+
+	tests := []struct {
+		name    string
+		deliver func(conf config) error
+		want    []byte
+	}{
+		{
+			name: "Message",
+			deliver: func(conf config) error {
+				_, err := deliver(conf, msg)
+				return err
+			},
+			want: posted,
+		},
+		{
+			name: "Raw payload",
+			deliver: func(conf config) error {
+				_, err := deliverPayload(conf, raw)
+				return err
+			},
+			want: raw,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := config{WebhookURL: failing.URL, DeployDir: t.TempDir()}
+			if err := tt.deliver(conf); err == nil {
+				t.Fatalf("deliver() error = nil, want an error")
+			}
+			got, err := os.ReadFile(filepath.Join(conf.DeployDir, failedPayloadFileName))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(tt.want) {
+				t.Errorf("failed payload = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
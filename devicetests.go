@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// deviceResult is the outcome of the tests on a single device of the matrix.
+type deviceResult struct {
+	Device  string `json:"axis_value"`
+	Outcome string `json:"outcome"`
+	Details string `json:"test_details"`
+}
+
+func (r deviceResult) passed() bool {
+	return strings.EqualFold(r.Outcome, "passed")
+}
+
+// readDeviceResults reads the device test results, either the JSON output of
+// gcloud or the downloaded results directory with a JUnit report per device.
+func readDeviceResults(pth string) ([]deviceResult, error) {
+	info, err := os.Stat(pth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read device test results: %s", err)
+	}
+	if info.IsDir() {
+		return readDeviceResultsDir(pth)
+	}
+
+	b, err := os.ReadFile(pth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read device test results: %s", err)
+	}
+	var results []deviceResult
+	if err := json.Unmarshal(b, &results); err != nil {
+		return nil, fmt.Errorf("invalid device test results: %s", err)
+	}
+	return results, nil
+}
+
+// junitSuite is a JUnit test suite, or the test suites root element.
+type junitSuite struct {
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+// counts returns the number of tests and failed tests, summing the nested suites if there is any.
+func (s junitSuite) counts() (tests, failed int) {
+	if len(s.Suites) == 0 {
+		return s.Tests, s.Failures + s.Errors
+	}
+	for _, child := range s.Suites {
+		t, f := child.counts()
+		tests += t
+		failed += f
+	}
+	return
+}
+
+// readDeviceResultsDir reads the JUnit reports of the results directory, where
+// every top level directory holds the results of a device.
+func readDeviceResultsDir(dir string) ([]deviceResult, error) {
+	tests := map[string]int{}
+	failed := map[string]int{}
+
+	err := filepath.Walk(dir, func(pth string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || filepath.Ext(pth) != ".xml" {
+			return err
+		}
+		rel, err := filepath.Rel(dir, pth)
+		if err != nil {
+			return err
+		}
+		parts := strings.SplitN(filepath.ToSlash(rel), "/", 2)
+		if len(parts) < 2 {
+			return nil
+		}
+
+		b, err := os.ReadFile(pth)
+		if err != nil {
+			return err
+		}
+		var suite junitSuite
+		if err := xml.Unmarshal(b, &suite); err != nil {
+			return fmt.Errorf("invalid JUnit report %s: %s", rel, err)
+		}
+		t, f := suite.counts()
+		tests[parts[0]] += t
+		failed[parts[0]] += f
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read device test results: %s", err)
+	}
+
+	var devices []string
+	for device := range tests {
+		devices = append(devices, device)
+	}
+	sort.Strings(devices)
+
+	var results []deviceResult
+	for _, device := range devices {
+		r := deviceResult{Device: device, Outcome: "Passed", Details: fmt.Sprintf("%d tests", tests[device])}
+		if failed[device] > 0 {
+			r.Outcome = "Failed"
+			r.Details = fmt.Sprintf("%d of %d tests failed", failed[device], tests[device])
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// deviceMatrixField lists the outcome of every device in a single field.
+func deviceMatrixField(results []deviceResult) Field {
+	passed := 0
+	var lines []string
+	for _, r := range results {
+		emoji := ":x:"
+		if r.passed() {
+			emoji = ":white_check_mark:"
+			passed++
+		}
+		line := emoji + " " + r.Device
+		if r.Details != "" {
+			line += ": " + strings.SplitN(r.Details, "\n", 2)[0]
+		}
+		lines = append(lines, line)
+	}
+	short := false
+	return Field{
+		Title: fmt.Sprintf("Device tests (%d/%d passed)", passed, len(results)),
+		Value: strings.Join(lines, "\n"),
+		Short: &short,
+	}
+}
+
+// withDeviceResults returns a copy of msg with the device matrix below the other fields.
+func withDeviceResults(msg Message, results []deviceResult) Message {
+	if len(msg.Attachments) == 0 || len(results) == 0 {
+		return msg
+	}
+
+	attachments := append([]Attachment{}, msg.Attachments...)
+	attachments[0].Fields = append(append([]Field{}, attachments[0].Fields...), deviceMatrixField(results))
+	msg.Attachments = attachments
+	return msg
+}
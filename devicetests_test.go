@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_readDeviceResults(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		pth := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(pth, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("results.json", `[
+  {"axis_value": "Pixel2-28-en-portrait", "outcome": "Passed", "test_details": "12 test cases passed"},
+  {"axis_value": "NexusLowRes-29-en-portrait", "outcome": "Failed", "test_details": "1 test cases failed, 11 passed"}
+]`)
+	write("results/Pixel2-28-en-portrait/test_result_1.xml", `<testsuite tests="12" failures="0" errors="0"></testsuite>`)
+	write("results/NexusLowRes-29-en-portrait/test_result_1.xml", `<testsuites><testsuite tests="8" failures="1"></testsuite><testsuite tests="4" errors="1"></testsuite></testsuites>`)
+	write("results/matrix.xml", `<testsuite tests="24"></testsuite>`)
+
+	tests := []struct {
+		name string
+		pth  string
+		want []deviceResult
+	}{
+		{
+			name: "gcloud JSON",
+			pth:  "results.json",
+			want: []deviceResult{
+				{Device: "Pixel2-28-en-portrait", Outcome: "Passed", Details: "12 test cases passed"},
+				{Device: "NexusLowRes-29-en-portrait", Outcome: "Failed", Details: "1 test cases failed, 11 passed"},
+			},
+		},
+		{
+			name: "Results directory",
+			pth:  "results",
+			want: []deviceResult{
+				{Device: "NexusLowRes-29-en-portrait", Outcome: "Failed", Details: "2 of 12 tests failed"},
+				{Device: "Pixel2-28-en-portrait", Outcome: "Passed", Details: "12 tests"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readDeviceResults(filepath.Join(dir, tt.pth))
+			if err != nil {
+				t.Fatalf("readDeviceResults() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readDeviceResults() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_deviceMatrixField(t *testing.T) {
+	got := deviceMatrixField([]deviceResult{
+		{Device: "Pixel2-28-en-portrait", Outcome: "Passed", Details: "12 tests"},
+		{Device: "NexusLowRes-29-en-portrait", Outcome: "Failed", Details: "2 of 12 tests failed"},
+	})
+
+	want := "Device tests (1/2 passed)"
+	if got.Title != want {
+		t.Errorf("deviceMatrixField().Title = %v, want %v", got.Title, want)
+	}
+	want = ":white_check_mark: Pixel2-28-en-portrait: 12 tests\n:x: NexusLowRes-29-en-portrait: 2 of 12 tests failed"
+	if got.Value != want {
+		t.Errorf("deviceMatrixField().Value = %v, want %v", got.Value, want)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// Digest modes
+const (
+	digestModeOff    = "off"
+	digestModeAppend = "append"
+	digestModeSend   = "send"
+)
+
+// digestEntry is the result of a single workflow, appended to the digest file.
+type digestEntry struct {
+	Name     string `json:"name"`
+	Success  bool   `json:"success"`
+	BuildURL string `json:"build_url,omitempty"`
+}
+
+// appendDigest adds the entry as a new line to the digest file.
+func appendDigest(pth string, e digestEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(pth, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open digest file: %s", err)
+	}
+	// A single write keeps concurrent appends from interleaving.
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write digest file: %s", err)
+	}
+	return f.Close()
+}
+
+// readDigest returns the entries collected in the digest file.
+func readDigest(pth string) ([]digestEntry, error) {
+	f, err := os.Open(pth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open digest file: %s", err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Warnf("Failed to close digest file: %s", err)
+		}
+	}()
+
+	var entries []digestEntry
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var e digestEntry
+		if err := dec.Decode(&e); err != nil {
+			return nil, fmt.Errorf("invalid digest file: %s", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// digestSucceeded reports whether every collected workflow succeeded.
+func digestSucceeded(entries []digestEntry) bool {
+	for _, e := range entries {
+		if !e.Success {
+			return false
+		}
+	}
+	return true
+}
+
+// digestFields renders one field per collected workflow.
+func digestFields(entries []digestEntry) []Field {
+	var fs []Field
+	for _, e := range entries {
+		status := ":white_check_mark: Succeeded"
+		if !e.Success {
+			status = ":x: Failed"
+		}
+		if e.BuildURL != "" {
+			status = fmt.Sprintf("<%s|%s>", e.BuildURL, status)
+		}
+		fs = append(fs, Field{Title: e.Name, Value: status})
+	}
+	return fs
+}
+
+// withDigest returns a copy of msg listing the digest entries above the other fields.
+func withDigest(msg Message, entries []digestEntry) Message {
+	if len(msg.Attachments) == 0 {
+		return msg
+	}
+
+	attachments := append([]Attachment{}, msg.Attachments...)
+	attachments[0].Fields = append(digestFields(entries), attachments[0].Fields...)
+	msg.Attachments = attachments
+	return msg
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_appendDigest(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "digest.ndjson")
+	want := []digestEntry{
+		{Name: "ios", Success: true, BuildURL: "https://app.bitrise.io/build/1"},
+		{Name: "android", Success: false},
+	}
+	for _, e := range want {
+		if err := appendDigest(pth, e); err != nil {
+			t.Fatalf("appendDigest() error = %v", err)
+		}
+	}
+
+	b, err := os.ReadFile(pth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFile := "{\"name\":\"ios\",\"success\":true,\"build_url\":\"https://app.bitrise.io/build/1\"}\n{\"name\":\"android\",\"success\":false}\n"
+	if string(b) != wantFile {
+		t.Errorf("digest file = %q, want %q", b, wantFile)
+	}
+
+	got, err := readDigest(pth)
+	if err != nil {
+		t.Fatalf("readDigest() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readDigest() = %v, want %v", got, want)
+	}
+}
+
+func Test_readDigest(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.ndjson")
+	if err := os.WriteFile(invalid, []byte("{\"name\":\"ios\"}\nnot json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(dir, "empty.ndjson")
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readDigest(invalid); err == nil {
+		t.Errorf("readDigest() error = nil, want an error for an invalid line")
+	}
+	if _, err := readDigest(filepath.Join(dir, "missing.ndjson")); err == nil {
+		t.Errorf("readDigest() error = nil, want an error for a missing file")
+	}
+	if got, err := readDigest(empty); err != nil || len(got) != 0 {
+		t.Errorf("readDigest() = %v, %v, want no entries", got, err)
+	}
+}
+
+func Test_digestSucceeded(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []digestEntry
+		want    bool
+	}{
+		{name: "No entries", want: true},
+		{name: "All succeeded", entries: []digestEntry{{Name: "ios", Success: true}, {Name: "android", Success: true}}, want: true},
+		{name: "One failed", entries: []digestEntry{{Name: "ios", Success: true}, {Name: "android"}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := digestSucceeded(tt.entries); got != tt.want {
+				t.Errorf("digestSucceeded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_withDigest(t *testing.T) {
+	msg := Message{Attachments: []Attachment{{Fields: []Field{{Title: "Branch", Value: "main"}}}}}
+	got := withDigest(msg, []digestEntry{
+		{Name: "ios", Success: true, BuildURL: "https://app.bitrise.io/build/1"},
+		{Name: "android"},
+	})
+
+	want := []Field{
+		{Title: "ios", Value: "<https://app.bitrise.io/build/1|:white_check_mark: Succeeded>"},
+		{Title: "android", Value: ":x: Failed"},
+		{Title: "Branch", Value: "main"},
+	}
+	if !reflect.DeepEqual(got.Attachments[0].Fields, want) {
+		t.Errorf("withDigest() fields = %v, want %v", got.Attachments[0].Fields, want)
+	}
+	if len(msg.Attachments[0].Fields) != 1 {
+		t.Errorf("withDigest() modified the original message")
+	}
+}
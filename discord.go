@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	registerProvider("discord", discordProvider{})
+}
+
+// discordProvider sends the message as an embed to a Discord webhook.
+type discordProvider struct{}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordURL struct {
+	URL string `json:"url"`
+}
+
+type discordFooter struct {
+	Text    string `json:"text"`
+	IconURL string `json:"icon_url,omitempty"`
+}
+
+type discordAuthor struct {
+	Name string `json:"name"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title,omitempty"`
+	URL         string         `json:"url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       int64          `json:"color,omitempty"`
+	Author      *discordAuthor `json:"author,omitempty"`
+	Fields      []discordField `json:"fields,omitempty"`
+	Image       *discordURL    `json:"image,omitempty"`
+	Thumbnail   *discordURL    `json:"thumbnail,omitempty"`
+	Footer      *discordFooter `json:"footer,omitempty"`
+}
+
+type discordMessage struct {
+	Username  string         `json:"username,omitempty"`
+	AvatarURL string         `json:"avatar_url,omitempty"`
+	Content   string         `json:"content,omitempty"`
+	Embeds    []discordEmbed `json:"embeds,omitempty"`
+}
+
+// newDiscordMessage converts the message into a Discord webhook message.
+func newDiscordMessage(msg Message) discordMessage {
+	m := discordMessage{
+		Username:  msg.Username,
+		AvatarURL: msg.IconURL,
+		Content:   msg.Text,
+	}
+
+	if len(msg.Attachments) == 0 {
+		m.Content = msg.PlainText()
+		return m
+	}
+
+	a := msg.Attachments[0]
+	e := discordEmbed{
+		Title:       a.Title,
+		URL:         a.TitleLink,
+		Description: strings.TrimSpace(a.PreText + "\n" + a.Text),
+	}
+	if c := hexColor(a.Color); c != "" {
+		e.Color, _ = strconv.ParseInt(strings.TrimPrefix(c, "#"), 16, 64)
+	}
+	if a.AuthorName != "" {
+		e.Author = &discordAuthor{Name: a.AuthorName}
+	}
+	for _, f := range a.Fields {
+		e.Fields = append(e.Fields, discordField{Name: f.Title, Value: f.Value, Inline: f.IsShort()})
+	}
+	if a.ImageURL != "" {
+		e.Image = &discordURL{URL: a.ImageURL}
+	}
+	if a.ThumbURL != "" {
+		e.Thumbnail = &discordURL{URL: a.ThumbURL}
+	}
+	if a.Footer != "" {
+		e.Footer = &discordFooter{Text: a.Footer, IconURL: a.FooterIcon}
+	}
+	m.Embeds = []discordEmbed{e}
+	return m
+}
+
+// Send implements Provider.
+func (discordProvider) Send(conf config, msg Message) (*SendMessageResponse, error) {
+	b, err := json.Marshal(newDiscordMessage(msg))
+	if err != nil {
+		return nil, err
+	}
+	return sendPayload(conf, b)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+// smtpConfig describes the SMTP server used for the email fallback.
+type smtpConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       string
+}
+
+// isConfigured reports whether enough settings are provided to send an email.
+func (c smtpConfig) isConfigured() bool {
+	return c.Host != "" && c.To != ""
+}
+
+// sendEmail sends the plain text version of the message through the SMTP server.
+func sendEmail(c smtpConfig, msg Message) error {
+	port := c.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(c.Host, strconv.Itoa(port))
+
+	from := c.From
+	if from == "" {
+		from = c.Username
+	}
+
+	var auth smtp.Auth
+	if c.Username != "" {
+		auth = smtp.PlainAuth("", c.Username, c.Password, c.Host)
+	}
+
+	to := splitList(c.To)
+	if err := smtp.SendMail(addr, auth, from, to, composeEmail(from, to, msg)); err != nil {
+		return fmt.Errorf("failed to send email through %s: %s", addr, err)
+	}
+	return nil
+}
+
+// headerLineBreaks replaces the line breaks, which would start a new header.
+var headerLineBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// composeEmail builds an RFC 822 email from the message.
+func composeEmail(from string, to []string, msg Message) []byte {
+	subject := "Slack message"
+	if len(msg.Attachments) > 0 && msg.Attachments[0].Title != "" {
+		subject = msg.Attachments[0].Title
+	} else if msg.Text != "" {
+		subject = strings.SplitN(msg.Text, "\n", 2)[0]
+	}
+	subject = strings.TrimSpace(headerLineBreaks.Replace(subject))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: [Slack delivery failed] %s\r\n", subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.Replace(msg.PlainText(), "\n", "\r\n", -1))
+	return []byte(b.String())
+}
@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func Test_composeEmail(t *testing.T) {
+	header := "From: bitrise@example.com\r\nTo: a@example.com, b@example.com\r\n"
+	mime := "MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n"
+	tests := []struct {
+		name string
+		msg  Message
+		want string
+	}{
+		{
+			name: "Text",
+			msg:  Message{Text: "Build succeeded\nCommit message"},
+			want: header + "Subject: [Slack delivery failed] Build succeeded\r\n" + mime + "Build succeeded\r\nCommit message",
+		},
+		{
+			name: "Attachment title",
+			msg:  Message{Text: "Build succeeded", Attachments: []Attachment{{Title: "Build #12"}}},
+			want: header + "Subject: [Slack delivery failed] Build #12\r\n" + mime + "Build succeeded\r\nBuild #12",
+		},
+		{
+			name: "Line breaks in the title",
+			msg:  Message{Attachments: []Attachment{{Title: "Build #12\r\nBcc: attacker@example.com"}}},
+			want: header + "Subject: [Slack delivery failed] Build #12 Bcc: attacker@example.com\r\n" + mime + "Build #12\r\r\nBcc: attacker@example.com",
+		},
+		{
+			name: "Carriage return in the text",
+			msg:  Message{Text: "Build succeeded\rBcc: attacker@example.com"},
+			want: header + "Subject: [Slack delivery failed] Build succeeded Bcc: attacker@example.com\r\n" + mime + "Build succeeded\rBcc: attacker@example.com",
+		},
+		{
+			name: "Empty message",
+			msg:  Message{},
+			want: header + "Subject: [Slack delivery failed] Slack message\r\n" + mime,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(composeEmail("bitrise@example.com", []string{"a@example.com", "b@example.com"}, tt.msg))
+			if got != tt.want {
+				t.Errorf("composeEmail() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
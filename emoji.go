@@ -0,0 +1,145 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// emojiShortcodes are the names of the standard emoji, one per line.
+//
+//go:embed emoji/shortcodes.txt
+var emojiShortcodes string
+
+var standardEmoji = func() map[string]bool {
+	m := map[string]bool{}
+	for _, name := range strings.Split(emojiShortcodes, "\n") {
+		if name = strings.TrimSpace(name); name != "" {
+			m[name] = true
+		}
+	}
+	return m
+}()
+
+// emojiRanges are the Unicode blocks of the emoji characters.
+var emojiRanges = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00a9, Hi: 0x00ae, Stride: 5},  // © ®
+		{Lo: 0x203c, Hi: 0x2049, Stride: 13}, // ‼ ⁉
+		{Lo: 0x2122, Hi: 0x2139, Stride: 23}, // ™ ℹ
+		{Lo: 0x2194, Hi: 0x21aa, Stride: 1},  // Arrows
+		{Lo: 0x2300, Hi: 0x23ff, Stride: 1},  // Miscellaneous Technical
+		{Lo: 0x24c2, Hi: 0x24c2, Stride: 1},  // Ⓜ
+		{Lo: 0x25aa, Hi: 0x25fe, Stride: 1},  // Geometric Shapes
+		{Lo: 0x2600, Hi: 0x27bf, Stride: 1},  // Miscellaneous Symbols, Dingbats
+		{Lo: 0x2934, Hi: 0x2935, Stride: 1},  // ⤴ ⤵
+		{Lo: 0x2b05, Hi: 0x2b55, Stride: 1},  // Miscellaneous Symbols and Arrows
+		{Lo: 0x3030, Hi: 0x303d, Stride: 13}, // 〰 〽
+		{Lo: 0x3297, Hi: 0x3299, Stride: 2},  // ㊗ ㊙
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f000, Hi: 0x1faff, Stride: 1}, // Mahjong Tiles to Symbols and Pictographs Extended-A
+	},
+}
+
+// emojiModifiers are the characters that only combine emoji: the variation
+// selectors, the zero width joiner, the keycap and the tags of flags.
+var emojiModifiers = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x200d, Hi: 0x200d, Stride: 1},
+		{Lo: 0x20e3, Hi: 0x20e3, Stride: 1},
+		{Lo: 0xfe0e, Hi: 0xfe0f, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0xe0020, Hi: 0xe007f, Stride: 1},
+	},
+}
+
+// isUnicodeEmoji reports whether s is an emoji character rather than a shortcode:
+// it contains an emoji or the emoji presentation selector, and nothing but
+// emoji, their modifiers and the digits, # and * of keycaps.
+func isUnicodeEmoji(s string) bool {
+	var emoji bool
+	for _, r := range s {
+		switch {
+		case r == 0xfe0f || unicode.Is(emojiRanges, r):
+			emoji = true
+		case unicode.Is(emojiModifiers, r), r == '#', r == '*', '0' <= r && r <= '9':
+		default:
+			return false
+		}
+	}
+	return emoji
+}
+
+// normalizeEmoji wraps the emoji shortcode in colons if they are missing.
+// Unicode emoji are passed through as is.
+func normalizeEmoji(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" || isUnicodeEmoji(s) {
+		return s
+	}
+	return ":" + strings.Trim(s, ":") + ":"
+}
+
+// isKnownEmoji reports whether the emoji is a unicode emoji or the shortcode of
+// a standard emoji, with an optional skin tone modifier.
+func isKnownEmoji(s string) bool {
+	s = strings.TrimSpace(s)
+	if isUnicodeEmoji(s) {
+		return true
+	}
+	name := strings.SplitN(strings.Trim(s, ":"), "::skin-tone-", 2)[0]
+	return standardEmoji[name]
+}
+
+// defaultStatusEmoji are the emoji of the statuses missing from the emoji map.
+var defaultStatusEmoji = map[string]string{
+	buildStatusSuccess: ":white_check_mark:",
+	buildStatusFailed:  ":x:",
+	buildStatusAborted: ":no_entry_sign:",
+}
+
+// parseEmojiMap parses the comma or newline separated status=emoji pairs,
+// falling back to the default emoji of the missing statuses. It returns nil if s is empty.
+func parseEmojiMap(s string) (map[string]string, error) {
+	items := splitList(strings.Replace(s, "\n", ",", -1))
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	m := map[string]string{}
+	for status, emoji := range defaultStatusEmoji {
+		m[status] = emoji
+	}
+	for _, item := range items {
+		kv := strings.SplitN(item, "=", 2)
+		status := strings.ToLower(strings.TrimSpace(kv[0]))
+		if _, ok := defaultStatusEmoji[status]; !ok || len(kv) != 2 || strings.TrimSpace(kv[1]) == "" {
+			return nil, fmt.Errorf("invalid emoji map item (%s), expected success, failed or aborted=emoji", item)
+		}
+		m[status] = normalizeEmoji(kv[1])
+	}
+	return m, nil
+}
+
+// statusEmoji returns the emoji of the build status from the emoji map, or
+// the default emoji if the map is not set.
+func statusEmoji(emojiMap map[string]string, emoji string, success, aborted bool) string {
+	if e, ok := emojiMap[statusName(success, aborted)]; ok {
+		return e
+	}
+	return emoji
+}
+
+// statusName returns the name of the build status used in the emoji map.
+func statusName(success, aborted bool) string {
+	switch {
+	case aborted:
+		return buildStatusAborted
+	case success:
+		return buildStatusSuccess
+	}
+	return buildStatusFailed
+}
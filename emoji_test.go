@@ -0,0 +1,89 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseEmojiMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "Empty", s: " ", want: nil},
+		{
+			name: "Every status",
+			s:    "success=:rocket:, failed=:boom:\naborted=:warning:",
+			want: map[string]string{"success": ":rocket:", "failed": ":boom:", "aborted": ":warning:"},
+		},
+		{
+			name: "Defaults",
+			s:    "Failed=boom",
+			want: map[string]string{"success": ":white_check_mark:", "failed": ":boom:", "aborted": ":no_entry_sign:"},
+		},
+		{name: "Unknown status", s: "skipped=:zzz:", wantErr: true},
+		{name: "Missing emoji", s: "success", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEmojiMap(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseEmojiMap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseEmojiMap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_normalizeEmoji(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{s: "", want: ""},
+		{s: "rocket", want: ":rocket:"},
+		{s: " :rocket: ", want: ":rocket:"},
+		{s: "rocket:", want: ":rocket:"},
+		{s: "🚀", want: "🚀"},
+		{s: "👍🏽", want: "👍🏽"},
+		{s: "❤️", want: "❤️"},
+		{s: "1️⃣", want: "1️⃣"},
+		{s: "🇭🇺", want: "🇭🇺"},
+		{s: "👩‍💻", want: "👩‍💻"},
+		{s: "café", want: ":café:"},
+		{s: "火箭", want: ":火箭:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := normalizeEmoji(tt.s); got != tt.want {
+				t.Errorf("normalizeEmoji() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isKnownEmoji(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{s: ":white_check_mark:", want: true},
+		{s: "+1", want: true},
+		{s: ":wave::skin-tone-3:", want: true},
+		{s: "🚀", want: true},
+		{s: ":party_parrot:", want: false},
+		{s: "é", want: false},
+		{s: "🚀 launch", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := isKnownEmoji(tt.s); got != tt.want {
+				t.Errorf("isKnownEmoji() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// pagerDutyEventsURL is the endpoint of the PagerDuty Events API v2.
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// defaultOpsgenieAPIURL is the Opsgenie API of the US region.
+const defaultOpsgenieAPIURL = "https://api.opsgenie.com"
+
+// opsgenieMessageMaxLength is the longest alert message accepted by Opsgenie.
+const opsgenieMessageMaxLength = 130
+
+// escalationConfig is the alerting service an alert is triggered on, once the
+// builds with the same key failed Threshold times in a row.
+type escalationConfig struct {
+	Threshold           int
+	Key                 string
+	PagerDutyRoutingKey string
+	OpsgenieAPIKey      string
+	OpsgenieAPIURL      string
+}
+
+func (c escalationConfig) enabled() bool {
+	return c.PagerDutyRoutingKey != "" || c.OpsgenieAPIKey != ""
+}
+
+// alias identifies the alert of the key, so it is triggered only once and can be resolved.
+func (c escalationConfig) alias() string {
+	return "slack-message-" + c.Key
+}
+
+// failureState counts the consecutive failed builds, cached in the state dir.
+type failureState struct {
+	Failures  int  `json:"failures"`
+	Escalated bool `json:"escalated"`
+}
+
+type escalationAction int
+
+const (
+	escalationNone escalationAction = iota
+	escalationTrigger
+	escalationResolve
+)
+
+// nextFailureState returns the failure state after the build and whether the
+// alert should be triggered or resolved. A successful build resets the count.
+func nextFailureState(state failureState, success bool, threshold int) (failureState, escalationAction) {
+	if success {
+		if state.Escalated {
+			return failureState{Escalated: true}, escalationResolve
+		}
+		return failureState{}, escalationNone
+	}
+
+	state.Failures++
+	if state.Failures >= threshold && !state.Escalated {
+		return state, escalationTrigger
+	}
+	return state, escalationNone
+}
+
+// escalate counts the consecutive failed builds and triggers an alert once the
+// threshold is reached, then resolves it when a build succeeds. Aborted builds
+// are not counted. Failing to escalate does not fail the step.
+func escalate(conf config, msg Message) {
+	if conf.Aborted {
+		return
+	}
+
+	pth := statePath(conf.StateDir, "failures", conf.Escalation.Key)
+	var state failureState
+	if _, err := loadState(pth, &state); err != nil {
+		log.Warnf("Failed to load the number of failed builds: %s", err)
+	}
+
+	state, action := nextFailureState(state, conf.Success, conf.Escalation.Threshold)
+	switch action {
+	case escalationTrigger:
+		log.Warnf("The build failed %d times in a row, triggering an alert", state.Failures)
+		if err := triggerAlert(conf, msg, state.Failures); err != nil {
+			log.Warnf("Failed to trigger the alert: %s", err)
+		} else {
+			state.Escalated = true
+		}
+	case escalationResolve:
+		log.Infof("Resolving the alert of the failed builds")
+		if err := resolveAlert(conf); err != nil {
+			// The alert is resolved by the next successful build.
+			log.Warnf("Failed to resolve the alert: %s", err)
+		} else {
+			state.Escalated = false
+		}
+	}
+
+	if err := saveState(pth, state); err != nil {
+		log.Warnf("Failed to cache the number of failed builds: %s", err)
+	}
+}
+
+// escalationSource returns the app the alert is raised for.
+func escalationSource() string {
+	if title := os.Getenv("BITRISE_APP_TITLE"); title != "" {
+		return title
+	}
+	return "Bitrise"
+}
+
+// escalationSummary returns the one line summary of the alert.
+func escalationSummary(msg Message, failures int) string {
+	summary := fmt.Sprintf("%d consecutive failed builds", failures)
+	if title := messageTitle(msg); title != "" {
+		summary += ": " + title
+	}
+	return summary
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+	Links       []pagerDutyLink   `json:"links,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// newPagerDutyEvent returns the trigger event of the failed builds.
+func newPagerDutyEvent(conf config, msg Message, failures int) pagerDutyEvent {
+	event := pagerDutyEvent{
+		RoutingKey:  conf.Escalation.PagerDutyRoutingKey,
+		EventAction: "trigger",
+		DedupKey:    conf.Escalation.alias(),
+		Payload: &pagerDutyPayload{
+			Summary:  escalationSummary(msg, failures),
+			Source:   escalationSource(),
+			Severity: "error",
+			CustomDetails: map[string]string{
+				"consecutive_failures": strconv.Itoa(failures),
+				"message":              msg.PlainText(),
+			},
+		},
+	}
+	if conf.BuildURL != "" {
+		event.Links = []pagerDutyLink{{Href: conf.BuildURL, Text: "View Build"}}
+	}
+	return event
+}
+
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Source      string            `json:"source"`
+	Priority    string            `json:"priority"`
+	Details     map[string]string `json:"details,omitempty"`
+}
+
+// newOpsgenieAlert returns the alert of the failed builds.
+func newOpsgenieAlert(conf config, msg Message, failures int) opsgenieAlert {
+	message := escalationSummary(msg, failures)
+	if r := []rune(message); len(r) > opsgenieMessageMaxLength {
+		message = string(r[:opsgenieMessageMaxLength-1]) + "…"
+	}
+	alert := opsgenieAlert{
+		Message:     message,
+		Alias:       conf.Escalation.alias(),
+		Description: msg.PlainText(),
+		Source:      escalationSource(),
+		Priority:    "P2",
+		Details:     map[string]string{"consecutive_failures": strconv.Itoa(failures)},
+	}
+	if conf.BuildURL != "" {
+		alert.Details["build_url"] = conf.BuildURL
+	}
+	return alert
+}
+
+// opsgenieURL returns the URL of the Opsgenie API endpoint.
+func opsgenieURL(conf escalationConfig, endpoint string) string {
+	base := strings.TrimSpace(conf.OpsgenieAPIURL)
+	if base == "" {
+		base = defaultOpsgenieAPIURL
+	}
+	return strings.TrimSuffix(base, "/") + "/v2/alerts" + endpoint
+}
+
+// triggerAlert triggers the alert on each configured alerting service.
+func triggerAlert(conf config, msg Message, failures int) error {
+	var errs []string
+	if conf.Escalation.PagerDutyRoutingKey != "" {
+		if err := postJSON(pagerDutyEventsURL, "", newPagerDutyEvent(conf, msg, failures)); err != nil {
+			errs = append(errs, "PagerDuty: "+err.Error())
+		}
+	}
+	if conf.Escalation.OpsgenieAPIKey != "" {
+		if err := postJSON(opsgenieURL(conf.Escalation, ""), "GenieKey "+conf.Escalation.OpsgenieAPIKey, newOpsgenieAlert(conf, msg, failures)); err != nil {
+			errs = append(errs, "Opsgenie: "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// resolveAlert resolves the alert on each configured alerting service.
+func resolveAlert(conf config) error {
+	var errs []string
+	if conf.Escalation.PagerDutyRoutingKey != "" {
+		event := pagerDutyEvent{
+			RoutingKey:  conf.Escalation.PagerDutyRoutingKey,
+			EventAction: "resolve",
+			DedupKey:    conf.Escalation.alias(),
+		}
+		if err := postJSON(pagerDutyEventsURL, "", event); err != nil {
+			errs = append(errs, "PagerDuty: "+err.Error())
+		}
+	}
+	if conf.Escalation.OpsgenieAPIKey != "" {
+		endpoint := "/" + url.PathEscape(conf.Escalation.alias()) + "/close?identifierType=alias"
+		if err := postJSON(opsgenieURL(conf.Escalation, endpoint), "GenieKey "+conf.Escalation.OpsgenieAPIKey, map[string]string{"source": escalationSource()}); err != nil {
+			errs = append(errs, "Opsgenie: "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, ", "))
+	}
+	return nil
+}
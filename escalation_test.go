@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func Test_nextFailureState(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      failureState
+		success    bool
+		want       failureState
+		wantAction escalationAction
+	}{
+		{name: "Failure below the threshold", state: failureState{Failures: 1}, want: failureState{Failures: 2}, wantAction: escalationNone},
+		{name: "Failure reaching the threshold", state: failureState{Failures: 2}, want: failureState{Failures: 3}, wantAction: escalationTrigger},
+		{name: "Failure after the escalation", state: failureState{Failures: 3, Escalated: true}, want: failureState{Failures: 4, Escalated: true}, wantAction: escalationNone},
+		{name: "Success resets the count", state: failureState{Failures: 2}, success: true, want: failureState{}, wantAction: escalationNone},
+		{name: "Success resolves the alert", state: failureState{Failures: 5, Escalated: true}, success: true, want: failureState{Escalated: true}, wantAction: escalationResolve},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, action := nextFailureState(tt.state, tt.success, 3)
+			if got != tt.want || action != tt.wantAction {
+				t.Errorf("nextFailureState() = %v, %v, want %v, %v", got, action, tt.want, tt.wantAction)
+			}
+		})
+	}
+}
+
+func Test_newPagerDutyEvent(t *testing.T) {
+	os.Setenv("BITRISE_APP_TITLE", "My App")
+	defer os.Unsetenv("BITRISE_APP_TITLE")
+
+	conf := config{
+		BuildURL:   "https://app.bitrise.io/build/12",
+		Escalation: escalationConfig{PagerDutyRoutingKey: "routing-key", Key: "main"},
+	}
+	msg := Message{Attachments: []Attachment{{Title: "Build #12 failed", Text: "Fix login"}}}
+
+	want := pagerDutyEvent{
+		RoutingKey:  "routing-key",
+		EventAction: "trigger",
+		DedupKey:    "slack-message-main",
+		Payload: &pagerDutyPayload{
+			Summary:  "3 consecutive failed builds: Build #12 failed",
+			Source:   "My App",
+			Severity: "error",
+			CustomDetails: map[string]string{
+				"consecutive_failures": "3",
+				"message":              msg.PlainText(),
+			},
+		},
+		Links: []pagerDutyLink{{Href: "https://app.bitrise.io/build/12", Text: "View Build"}},
+	}
+	if got := newPagerDutyEvent(conf, msg, 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("newPagerDutyEvent() = %v, want %v", got, want)
+	}
+}
+
+func Test_opsgenieURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiURL   string
+		endpoint string
+		want     string
+	}{
+		{name: "Default region", want: "https://api.opsgenie.com/v2/alerts"},
+		{name: "EU region", apiURL: "https://api.eu.opsgenie.com/", endpoint: "/slack-message-main/close?identifierType=alias", want: "https://api.eu.opsgenie.com/v2/alerts/slack-message-main/close?identifierType=alias"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := opsgenieURL(escalationConfig{OpsgenieAPIURL: tt.apiURL}, tt.endpoint); got != tt.want {
+				t.Errorf("opsgenieURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// failedStep is a failed step of the build.
+type failedStep struct {
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Error  string `json:"error"`
+}
+
+// readFailedSteps returns the failed steps listed in the steps summary file, or
+// the failed step reported by Bitrise if there is no summary file.
+func readFailedSteps(summaryPath string) ([]failedStep, error) {
+	if summaryPath == "" {
+		title := strings.TrimSpace(os.Getenv("BITRISE_FAILED_STEP_TITLE"))
+		if title == "" {
+			return nil, nil
+		}
+		return []failedStep{{
+			Title: title,
+			Error: strings.TrimSpace(os.Getenv("BITRISE_FAILED_STEP_ERROR_MESSAGE")),
+		}}, nil
+	}
+
+	b, err := os.ReadFile(summaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read steps summary: %s", err)
+	}
+	return parseStepsSummary(b)
+}
+
+// parseStepsSummary returns the failed steps of the JSON steps summary, a list
+// of objects with title, status and optional error keys.
+func parseStepsSummary(b []byte) ([]failedStep, error) {
+	var steps []failedStep
+	if err := json.Unmarshal(b, &steps); err != nil {
+		return nil, fmt.Errorf("invalid steps summary: %s", err)
+	}
+
+	var failed []failedStep
+	for _, s := range steps {
+		if strings.EqualFold(s.Status, "failed") {
+			failed = append(failed, s)
+		}
+	}
+	return failed, nil
+}
+
+// failedStepsField lists the failed steps in a single field, titled with tr.
+func failedStepsField(steps []failedStep, tr func(string) string) Field {
+	var lines []string
+	for _, s := range steps {
+		line := s.Title + " failed"
+		if s.Error != "" {
+			line += ": " + strings.SplitN(s.Error, "\n", 2)[0]
+		}
+		lines = append(lines, line)
+	}
+	short := false
+	return Field{Title: tr("Failed steps"), Value: strings.Join(lines, "\n"), Short: &short}
+}
+
+// withFailedSteps returns a copy of msg listing the failed steps above the other fields.
+func withFailedSteps(msg Message, steps []failedStep, tr func(string) string) Message {
+	if len(msg.Attachments) == 0 || len(steps) == 0 {
+		return msg
+	}
+
+	attachments := append([]Attachment{}, msg.Attachments...)
+	attachments[0].Fields = append([]Field{failedStepsField(steps, tr)}, attachments[0].Fields...)
+	msg.Attachments = attachments
+	return msg
+}
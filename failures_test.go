@@ -0,0 +1,40 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseStepsSummary(t *testing.T) {
+	summary := `[
+		{"title": "git-clone", "status": "success"},
+		{"title": "xcode-test", "status": "failed", "error": "2 tests failed\nSee the test report"},
+		{"title": "deploy-to-bitrise-io", "status": "skipped"}
+	]`
+
+	got, err := parseStepsSummary([]byte(summary))
+	if err != nil {
+		t.Fatalf("parseStepsSummary() error = %v", err)
+	}
+	want := []failedStep{{Title: "xcode-test", Status: "failed", Error: "2 tests failed\nSee the test report"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseStepsSummary() = %v, want %v", got, want)
+	}
+
+	if value := failedStepsField(got, translate(nil)).Value; value != "xcode-test failed: 2 tests failed" {
+		t.Errorf("failedStepsField() = %v, want %v", value, "xcode-test failed: 2 tests failed")
+	}
+}
+
+func Test_readFailedSteps_env(t *testing.T) {
+	t.Setenv("BITRISE_FAILED_STEP_TITLE", "xcode-test")
+	t.Setenv("BITRISE_FAILED_STEP_ERROR_MESSAGE", "")
+
+	got, err := readFailedSteps("")
+	if err != nil {
+		t.Fatalf("readFailedSteps() error = %v", err)
+	}
+	if want := []failedStep{{Title: "xcode-test"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("readFailedSteps() = %v, want %v", got, want)
+	}
+}
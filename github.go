@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// GitHub mirror modes
+const (
+	githubMirrorOff     = "off"
+	githubMirrorStatus  = "status"
+	githubMirrorComment = "comment"
+	githubMirrorBoth    = "both"
+)
+
+// defaultGitHubAPIURL is the API of github.com.
+const defaultGitHubAPIURL = "https://api.github.com"
+
+// githubStatusDescriptionMaxLength is the longest commit status description accepted by GitHub.
+const githubStatusDescriptionMaxLength = 140
+
+// githubConfig is the repository the notification is mirrored to, as a commit
+// status and/or as a pull request comment.
+type githubConfig struct {
+	Mirror        string
+	Token         string
+	APIURL        string
+	Repository    string
+	Commit        string
+	PullRequest   string
+	StatusContext string
+}
+
+func (c githubConfig) mirrorsStatus() bool {
+	return c.Mirror == githubMirrorStatus || c.Mirror == githubMirrorBoth
+}
+
+func (c githubConfig) mirrorsComment() bool {
+	return c.Mirror == githubMirrorComment || c.Mirror == githubMirrorBoth
+}
+
+// githubRepository returns the owner/name of the repository, from the input or
+// from the path of the repository URL.
+func githubRepository(repository, repositoryURL string) string {
+	if repository = strings.Trim(strings.TrimSpace(repository), "/"); repository != "" {
+		return repository
+	}
+
+	webURL := repositoryWebURL(repositoryURL)
+	if webURL == "" {
+		return ""
+	}
+	u, err := url.Parse(webURL)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+// githubAPIURL returns the URL of the repository's API endpoint.
+func githubAPIURL(conf githubConfig, endpoint string) string {
+	base := strings.TrimSpace(conf.APIURL)
+	if base == "" {
+		base = defaultGitHubAPIURL
+	}
+	return strings.TrimSuffix(base, "/") + "/repos/" + conf.Repository + endpoint
+}
+
+type githubStatus struct {
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description,omitempty"`
+	Context     string `json:"context"`
+}
+
+// newGitHubStatus returns the commit status of the build, described by the title of the message.
+func newGitHubStatus(conf config, msg Message) githubStatus {
+	state := "failure"
+	switch {
+	case conf.Aborted:
+		state = "error"
+	case conf.Success:
+		state = "success"
+	}
+
+	description := messageTitle(msg)
+	if r := []rune(description); len(r) > githubStatusDescriptionMaxLength {
+		description = string(r[:githubStatusDescriptionMaxLength-1]) + "…"
+	}
+	return githubStatus{
+		State:       state,
+		TargetURL:   conf.BuildURL,
+		Description: description,
+		Context:     conf.GitHub.StatusContext,
+	}
+}
+
+// mirrorToGitHub mirrors the message as a commit status and/or as a comment on
+// the pull request of the build. Failing to mirror does not fail the step.
+func mirrorToGitHub(conf config, msg Message) {
+	authorization := "Bearer " + conf.GitHub.Token
+
+	if conf.GitHub.mirrorsStatus() {
+		if conf.GitHub.Commit == "" {
+			log.Warnf("The commit of the build is unknown, skipping the GitHub commit status")
+		} else {
+			log.Infof("Setting the GitHub commit status of %s", shortCommit(conf.GitHub.Commit))
+			endpoint := "/statuses/" + url.PathEscape(conf.GitHub.Commit)
+			if err := postJSON(githubAPIURL(conf.GitHub, endpoint), authorization, newGitHubStatus(conf, msg)); err != nil {
+				log.Warnf("Failed to set the GitHub commit status: %s", err)
+			}
+		}
+	}
+
+	if conf.GitHub.mirrorsComment() {
+		if conf.GitHub.PullRequest == "" {
+			log.Debugf("The build is not a pull request build, skipping the GitHub comment")
+		} else {
+			log.Infof("Commenting on the GitHub pull request #%s", conf.GitHub.PullRequest)
+			endpoint := fmt.Sprintf("/issues/%s/comments", url.PathEscape(conf.GitHub.PullRequest))
+			if err := postJSON(githubAPIURL(conf.GitHub, endpoint), authorization, map[string]string{"body": messageMarkdown(msg)}); err != nil {
+				log.Warnf("Failed to comment on the GitHub pull request: %s", err)
+			}
+		}
+	}
+}
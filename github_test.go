@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+)
+
+func Test_githubRepository(t *testing.T) {
+	tests := []struct {
+		name          string
+		repository    string
+		repositoryURL string
+		want          string
+	}{
+		{name: "Input", repository: " bitrise-io/steps-slack-message/ ", repositoryURL: "git@github.com:other/repo.git", want: "bitrise-io/steps-slack-message"},
+		{name: "SSH URL", repositoryURL: "git@github.com:bitrise-io/steps-slack-message.git", want: "bitrise-io/steps-slack-message"},
+		{name: "HTTPS URL", repositoryURL: "https://github.com/bitrise-io/steps-slack-message", want: "bitrise-io/steps-slack-message"},
+		{name: "Nested group", repositoryURL: "https://gitlab.com/group/subgroup/repo.git", want: ""},
+		{name: "Empty", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := githubRepository(tt.repository, tt.repositoryURL); got != tt.want {
+				t.Errorf("githubRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newGitHubStatus(t *testing.T) {
+	msg := Message{Attachments: []Attachment{{Title: "Build #12 failed"}}}
+	tests := []struct {
+		name    string
+		success bool
+		aborted bool
+		want    string
+	}{
+		{name: "Success", success: true, want: "success"},
+		{name: "Failure", want: "failure"},
+		{name: "Aborted", aborted: true, want: "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := config{
+				Success:  tt.success,
+				Aborted:  tt.aborted,
+				BuildURL: "https://app.bitrise.io/build/12",
+				GitHub:   githubConfig{StatusContext: "bitrise/slack-message"},
+			}
+			want := githubStatus{State: tt.want, TargetURL: conf.BuildURL, Description: "Build #12 failed", Context: "bitrise/slack-message"}
+			if got := newGitHubStatus(conf, msg); got != want {
+				t.Errorf("newGitHubStatus() = %v, want %v", got, want)
+			}
+		})
+	}
+}
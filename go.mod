@@ -5,9 +5,7 @@ go 1.17
 require (
 	github.com/bitrise-io/go-utils v0.0.0-20171214135957-b33f6bcef9b5
 	github.com/bitrise-tools/go-steputils v0.0.0-20180209154519-b0e1079aa921
+	gopkg.in/yaml.v2 v2.4.0
 )
 
-require (
-	github.com/stretchr/testify v1.8.0 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
-)
+require github.com/stretchr/testify v1.8.0 // indirect
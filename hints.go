@@ -0,0 +1,68 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// errorHints are remediation hints for the Slack error codes, which are
+// returned by the Web API in the error field or by webhooks as the response body.
+var errorHints = map[string]string{
+	"invalid_token":                     "The API token is invalid. Check the Slack API token input, it should be a bot token starting with xoxb-.",
+	"not_authed":                        "No API token was sent. Check the Slack API token input.",
+	"token_revoked":                     "The API token was revoked. Reinstall the Slack app and update the Slack API token input.",
+	"account_inactive":                  "The API token belongs to a deleted user or a deactivated app. Reinstall the Slack app.",
+	"missing_scope":                     "The Slack app is missing a required scope (eg. chat:write). Add it under OAuth & Permissions and reinstall the app.",
+	"channel_not_found":                 "The channel does not exist or the app can not see it. Use the channel ID, and invite the app to private channels.",
+	"not_in_channel":                    "The app is not a member of the channel. Invite it with /invite, or enable the Join channel input.",
+	"is_archived":                       "The channel is archived. Unarchive it or send the message to another channel.",
+	"no_text":                           "The message is empty. Set the Text or Message input, or use a Block Kit layout.",
+	"invalid_blocks":                    "The blocks are invalid. Check the rendered blocks in the debug log with the Block Kit Builder (https://app.slack.com/block-kit-builder).",
+	"invalid_blocks_format":             "The blocks are not a JSON array of block objects. Check the Block Kit template input.",
+	"invalid_payload":                   "The webhook could not parse the payload. Check the raw payload or the payload transform script.",
+	"no_service":                        "The webhook is disabled or was removed. Create a new incoming webhook and update the Slack Webhook URL input.",
+	"no_team":                           "The workspace of the webhook does not exist anymore. Create a new incoming webhook.",
+	"team_disabled":                     "The workspace of the webhook is disabled.",
+	"channel_is_archived":               "The channel of the webhook is archived. Unarchive it or create a new incoming webhook.",
+	"action_prohibited":                 "A workspace admin restricted posting to the channel.",
+	"posting_to_general_channel_denied": "Only admins can post to the general channel. Use another channel.",
+	"msg_too_long":                      "The message text is too long. Shorten the Text or Message input.",
+	"ratelimited":                       "The app is rate limited by Slack. Send fewer messages or retry later.",
+}
+
+// printErrorHint prints the remediation hint of the error, if there is one.
+func printErrorHint(err error) {
+	if hint := errorHint(err); hint != "" {
+		log.Warnf("Hint: %s", hint)
+	}
+}
+
+// errorHint returns the remediation hint of the Slack error code found in the
+// error, or an empty string if there is none.
+func errorHint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var codes []string
+	for code := range errorHints {
+		codes = append(codes, code)
+	}
+	// Longer codes first, so invalid_blocks_format is not reported as invalid_blocks.
+	sort.Slice(codes, func(i, j int) bool {
+		if len(codes[i]) != len(codes[j]) {
+			return len(codes[i]) > len(codes[j])
+		}
+		return codes[i] < codes[j]
+	})
+
+	msg := err.Error()
+	for _, code := range codes {
+		if strings.Contains(msg, code) {
+			return errorHints[code]
+		}
+	}
+	return ""
+}
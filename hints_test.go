@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_errorHint(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "API error", err: &apiError{Method: "chat.postMessage", Code: "channel_not_found"}, want: errorHints["channel_not_found"]},
+		{name: "Webhook response", err: fmt.Errorf("all delivery attempts failed: server error: 400 Bad Request, response: invalid_blocks_format"), want: errorHints["invalid_blocks_format"]},
+		{name: "Unknown error", err: fmt.Errorf("failed to send the request: timeout"), want: ""},
+		{name: "No error", err: nil, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorHint(tt.err); got != tt.want {
+				t.Errorf("errorHint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
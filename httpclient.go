@@ -0,0 +1,99 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// httpTimeout limits every request of the step, including the file uploads.
+const httpTimeout = 2 * time.Minute
+
+// tlsVersions are the supported values of the minimum TLS version input.
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// httpTransport and httpClient are shared by every request of the step, so the
+// connections (and HTTP/2 streams) are reused when sending several messages and follow-ups.
+var (
+	httpTransport = newHTTPTransport()
+	httpClient    = &http.Client{
+		Transport: timingTransport{next: httpTransport},
+		Timeout:   httpTimeout,
+	}
+)
+
+func newHTTPTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 10
+	transport.ForceAttemptHTTP2 = true
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	return transport
+}
+
+// parseTLSConfig returns the TLS config of the minimum version and the comma
+// separated cipher suite names. The cipher suites only apply to TLS 1.2, as
+// TLS 1.3 suites are not configurable; empty means Go's secure defaults.
+// The minimum version defaults to TLS 1.2.
+func parseTLSConfig(minVersion, cipherSuites string) (*tls.Config, error) {
+	if strings.TrimSpace(minVersion) == "" {
+		minVersion = "1.2"
+	}
+	version, ok := tlsVersions[strings.TrimSpace(minVersion)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported minimum TLS version (%s), use 1.2 or 1.3", minVersion)
+	}
+	config := &tls.Config{MinVersion: version}
+
+	suites := map[string]uint16{}
+	for _, s := range tls.CipherSuites() {
+		suites[s.Name] = s.ID
+	}
+	for _, name := range splitList(cipherSuites) {
+		id, ok := suites[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure cipher suite (%s)", name)
+		}
+		config.CipherSuites = append(config.CipherSuites, id)
+	}
+	return config, nil
+}
+
+// configureTLS applies the TLS config to the shared HTTP client. It must be
+// called before the first request.
+func configureTLS(config *tls.Config) {
+	httpTransport.TLSClientConfig = config
+}
+
+// timingTransport logs the duration of the requests in debug mode.
+type timingTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t timingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		log.Debugf("%s %s failed in %s: %s", req.Method, requestName(req), elapsed, err)
+		return nil, err
+	}
+	log.Debugf("%s %s: %s in %s", req.Method, requestName(req), resp.Status, elapsed)
+	return resp, nil
+}
+
+// requestName identifies the request in the logs without the secrets of
+// webhook and upload URLs: only the path of API calls is shown.
+func requestName(req *http.Request) string {
+	if strings.HasPrefix(req.URL.Path, "/api/") || strings.HasPrefix(req.URL.Path, "/v0.1/") {
+		return req.URL.Host + req.URL.Path
+	}
+	return req.URL.Host
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"crypto/tls"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func Test_requestName(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://slack.com/api/chat.postMessage", want: "slack.com/api/chat.postMessage"},
+		{url: "https://api.bitrise.io/v0.1/apps/app-slug/builds/build-slug", want: "api.bitrise.io/v0.1/apps/app-slug/builds/build-slug"},
+		{url: "https://hooks.slack.com/services/T000/B000/secret", want: "hooks.slack.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			req, err := http.NewRequest("POST", tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := requestName(req); got != tt.want {
+				t.Errorf("requestName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseTLSConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		minVersion   string
+		cipherSuites string
+		want         *tls.Config
+		wantErr      bool
+	}{
+		{name: "Defaults", want: &tls.Config{MinVersion: tls.VersionTLS12}},
+		{name: "TLS 1.3", minVersion: "1.3", want: &tls.Config{MinVersion: tls.VersionTLS13}},
+		{
+			name:         "Cipher suites",
+			minVersion:   "1.2",
+			cipherSuites: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+			want: &tls.Config{MinVersion: tls.VersionTLS12, CipherSuites: []uint16{
+				tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+				tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			}},
+		},
+		{name: "Insecure cipher suite", minVersion: "1.2", cipherSuites: "TLS_RSA_WITH_RC4_128_SHA", wantErr: true},
+		{name: "Unsupported version", minVersion: "1.1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTLSConfig(tt.minVersion, tt.cipherSuites)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTLSConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTLSConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//go:embed translations/*.yml
+var translationFS embed.FS
+
+// defaultLanguage is the language of the built-in layouts, which needs no translations.
+const defaultLanguage = "en"
+
+// parseTranslations parses a YAML mapping of the English strings of the layouts
+// to their translations, eg.
+//
+//	"View Build": "Build anzeigen"
+//	"Deployed %s": "%s bereitgestellt"
+//
+// The formatting verbs of the English string must be kept, and can be reordered
+// with explicit argument indexes, eg. %[2]s.
+func parseTranslations(s string) (map[string]string, error) {
+	v, err := parseYAML(s)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return map[string]string{}, nil
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("must be a mapping of English strings to translations")
+	}
+
+	translations := map[string]string{}
+	for key, value := range m {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("the translation of %s must be a string", key)
+		}
+		translations[key] = s
+	}
+	return translations, nil
+}
+
+// loadTranslations returns the built-in translations of the language, overridden
+// by the translations file if it is set.
+func loadTranslations(language, pth string) (map[string]string, error) {
+	translations := map[string]string{}
+	if language = strings.TrimSpace(language); language != "" && language != defaultLanguage {
+		b, err := translationFS.ReadFile("translations/" + language + ".yml")
+		if err != nil {
+			return nil, fmt.Errorf("no built-in translations for language %s", language)
+		}
+		if translations, err = parseTranslations(string(b)); err != nil {
+			return nil, fmt.Errorf("invalid built-in translations for language %s: %s", language, err)
+		}
+	}
+
+	if pth = strings.TrimSpace(pth); pth != "" {
+		b, err := os.ReadFile(pth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read translations file: %s", err)
+		}
+		custom, err := parseTranslations(string(b))
+		if err != nil {
+			return nil, fmt.Errorf("invalid translations file: %s", err)
+		}
+		for k, v := range custom {
+			translations[k] = v
+		}
+	}
+	return translations, nil
+}
+
+// translate returns the template function translating a string of the layouts,
+// or returning it as is if it has no translation: {{t "View Build"}}.
+func translate(translations map[string]string) func(string) string {
+	return func(s string) string {
+		if t, ok := translations[s]; ok && t != "" {
+			return t
+		}
+		return s
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// layoutStrings returns the strings translated in the built-in layouts.
+func layoutStrings(t *testing.T) []string {
+	// The statuses are translated with (t (lower .Status)) and (t (printf "Build %s" .Status)).
+	keys := []string{"succeeded", "failed", "aborted", "Build Succeeded", "Build Failed", "Build Aborted"}
+	// The titles of the fields and sections added by the step.
+	keys = append(keys, "What's new", "Failed steps", "Coverage", "Artifacts")
+	re := regexp.MustCompile(`\(t ("(?:[^"\\]|\\.)*")\)`)
+	for _, name := range layoutNames() {
+		tmpl, err := layoutTemplate(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range re.FindAllStringSubmatch(tmpl, -1) {
+			key, err := strconv.Unquote(m[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func Test_builtinTranslations(t *testing.T) {
+	keys := layoutStrings(t)
+	entries, err := translationFS.ReadDir("translations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		language := strings.TrimSuffix(e.Name(), ".yml")
+		t.Run(language, func(t *testing.T) {
+			translations, err := loadTranslations(language, "")
+			if err != nil {
+				t.Fatalf("loadTranslations() error = %v", err)
+			}
+			for _, key := range keys {
+				translation, ok := translations[key]
+				if !ok {
+					t.Errorf("missing translation of %q", key)
+					continue
+				}
+				args := make([]interface{}, strings.Count(key, "%"))
+				for i := range args {
+					args[i] = 1
+					if strings.Contains(key, "%s") {
+						args[i] = "x"
+					}
+				}
+				if got := fmt.Sprintf(translation, args...); strings.Contains(got, "%!") {
+					t.Errorf("translation of %q has invalid formatting verbs: %s", key, got)
+				}
+			}
+		})
+	}
+}
+
+func Test_loadTranslations(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "translations.yml")
+	content := "\"View Build\": \"Visa bygget\"\n\"Branch\": \"Gren\"\n"
+	if err := os.WriteFile(pth, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		language string
+		pth      string
+		keys     []string
+		want     []string
+		wantErr  bool
+	}{
+		{name: "English", language: "en", keys: []string{"View Build"}, want: []string{"View Build"}},
+		{name: "Built-in language", language: "de", keys: []string{"View Build", "Branch"}, want: []string{"Build anzeigen", "Branch"}},
+		{name: "Translations file over a built-in language", language: "de", pth: pth, keys: []string{"View Build", "Author"}, want: []string{"Visa bygget", "Autor"}},
+		{name: "Translations file only", language: "en", pth: pth, keys: []string{"Branch", "Author"}, want: []string{"Gren", "Author"}},
+		{name: "Unknown language", language: "xx", wantErr: true},
+		{name: "Missing translations file", language: "en", pth: filepath.Join(t.TempDir(), "missing.yml"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			translations, err := loadTranslations(tt.language, tt.pth)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadTranslations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var got []string
+			for _, key := range tt.keys {
+				got = append(got, translate(translations)(key))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadTranslations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_renderBlocks_translated(t *testing.T) {
+	translations, err := loadTranslations("ja", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := layoutTemplate("compact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := layoutData{Status: "Failed", StatusEmoji: ":x:", AppTitle: "App", BuildURL: "https://app.bitrise.io/build/1", BuildNumber: "12", Branch: "main"}
+
+	blocks, err := renderBlocks(tmpl, data, templateOptions{Translations: translations})
+	if err != nil {
+		t.Fatalf("renderBlocks() error = %v", err)
+	}
+	want := ":x: *App* のビルド <https://app.bitrise.io/build/1|#12> が `main` で失敗しました"
+	if got := blocks[0]["text"].(map[string]interface{})["text"]; got != want {
+		t.Errorf("renderBlocks() = %v, want %v", got, want)
+	}
+}
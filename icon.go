@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// iconCheckTimeout limits the HEAD request of the icon URL check.
+const iconCheckTimeout = 10 * time.Second
+
+// checkImageURL sends a HEAD request to the URL and returns why Slack would
+// not show it as an image, or an empty string if the URL serves an image.
+func checkImageURL(client *http.Client, rawURL string) string {
+	resp, err := client.Head(rawURL)
+	if err != nil {
+		return fmt.Sprintf("unreachable: %s", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Warnf("Failed to close response body: %s", err)
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Sprintf("responded with %s", resp.Status)
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "image/") {
+		return fmt.Sprintf("not an image (Content-Type: %s)", resp.Header.Get("Content-Type"))
+	}
+	return ""
+}
+
+// checkIconURLs warns about the icon URLs which would silently fall back to the default icon.
+func checkIconURLs(conf config) {
+	client := &http.Client{Transport: httpClient.Transport, Timeout: iconCheckTimeout}
+	for _, icon := range []struct{ name, url string }{
+		{name: "Icon URL", url: conf.IconURL},
+		{name: "Footer icon", url: conf.FooterIcon},
+	} {
+		if strings.TrimSpace(icon.url) == "" {
+			continue
+		}
+		if warning := checkImageURL(client, strings.TrimSpace(icon.url)); warning != "" {
+			log.Warnf("%s %s is %s, the message is sent with the default icon", icon.name, icon.url, warning)
+		}
+	}
+}
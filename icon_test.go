@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_checkImageURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/icon.png":
+			w.Header().Set("Content-Type", "image/png")
+		case "/page":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "Image", path: "/icon.png", want: ""},
+		{name: "Not an image", path: "/page", want: "not an image (Content-Type: text/html; charset=utf-8)"},
+		{name: "Not found", path: "/missing.png", want: "responded with 404 Not Found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkImageURL(server.Client(), server.URL+tt.path); got != tt.want {
+				t.Errorf("checkImageURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	server.Close()
+	if got := checkImageURL(server.Client(), server.URL+"/icon.png"); !strings.HasPrefix(got, "unreachable: ") {
+		t.Errorf("checkImageURL() = %v, want unreachable", got)
+	}
+}
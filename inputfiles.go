@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// loadInputFiles reads the long inputs from their file path counterparts, as
+// environment variables are limited in size. The file is preferred if both the
+// value and the file path are provided.
+func loadInputFiles(inp *Input) error {
+	for _, f := range []struct {
+		name  string
+		file  string
+		value *string
+	}{
+		{"message", inp.MessageFilePath, &inp.Message},
+		{"blocks", inp.BlocksFilePath, &inp.Blocks},
+		{"attachments", inp.AttachmentsFilePath, &inp.Attachments},
+	} {
+		pth := strings.TrimSpace(f.file)
+		if pth == "" {
+			continue
+		}
+
+		b, err := os.ReadFile(pth)
+		if err != nil {
+			return fmt.Errorf("Failed to read the %s file: %s", f.name, err)
+		}
+		if strings.TrimSpace(*f.value) != "" {
+			log.Debugf("Using the %s file %s instead of the %s input", f.name, pth, f.name)
+		}
+		*f.value = strings.TrimRight(string(b), "\r\n")
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_loadInputFiles(t *testing.T) {
+	dir := t.TempDir()
+	messagePth := filepath.Join(dir, "changelog.md")
+	if err := os.WriteFile(messagePth, []byte("- Fix login\n- Add dark mode\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	inp := Input{Message: "Short message", MessageFilePath: messagePth, Blocks: `[{"type": "divider"}]`}
+	if err := loadInputFiles(&inp); err != nil {
+		t.Fatalf("loadInputFiles() error = %v", err)
+	}
+	if want := "- Fix login\n- Add dark mode"; inp.Message != want {
+		t.Errorf("loadInputFiles() message = %q, want %q", inp.Message, want)
+	}
+	if want := `[{"type": "divider"}]`; inp.Blocks != want {
+		t.Errorf("loadInputFiles() blocks = %q, want %q", inp.Blocks, want)
+	}
+
+	inp = Input{AttachmentsFilePath: filepath.Join(dir, "missing.yml")}
+	if err := loadInputFiles(&inp); err == nil {
+		t.Errorf("loadInputFiles() error = nil, want an error for a missing file")
+	}
+}
@@ -0,0 +1,141 @@
+// Package log is a leveled logger for the step's own output. Text output is
+// printed by the go-utils logger, JSON output is printed as one JSON object
+// per line, so it can be collected by log aggregation pipelines.
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	utilslog "github.com/bitrise-io/go-utils/log"
+)
+
+// Level is the severity of a log message.
+type Level int
+
+// Log levels, from the most verbose one.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+// String implements fmt.Stringer.
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// ParseLevel parses a level name: debug, info, warn or error.
+func ParseLevel(s string) (Level, error) {
+	for l, name := range levelNames {
+		if strings.EqualFold(strings.TrimSpace(s), name) {
+			return l, nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("invalid log level (%s), use one of debug, info, warn, error", s)
+}
+
+var (
+	level                = LevelInfo
+	jsonFormat           = false
+	outWriter  io.Writer = os.Stdout
+)
+
+// SetLevel sets the minimum level of the printed messages.
+func SetLevel(l Level) {
+	level = l
+	utilslog.SetEnableDebugLog(l == LevelDebug)
+}
+
+// SetEnableDebugLog enables the debug messages.
+func SetEnableDebugLog(enable bool) {
+	if enable {
+		SetLevel(LevelDebug)
+	}
+}
+
+// SetJSONFormat switches between the text and the JSON output.
+func SetJSONFormat(enable bool) {
+	jsonFormat = enable
+}
+
+// IsJSONFormat reports whether the messages are printed as JSON.
+func IsJSONFormat() bool {
+	return jsonFormat
+}
+
+// SetOutWriter sets the writer of the messages.
+func SetOutWriter(writer io.Writer) {
+	outWriter = writer
+	utilslog.SetOutWriter(writer)
+}
+
+type jsonMessage struct {
+	Time    string `json:"time"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// printf prints the message if its level is enabled, in text format with the
+// text printer, or as a JSON line.
+func printf(l Level, text func(string, ...interface{}), format string, v ...interface{}) {
+	if l < level {
+		return
+	}
+	if !jsonFormat {
+		text(format, v...)
+		return
+	}
+
+	b, err := json.Marshal(jsonMessage{
+		Time:    time.Now().UTC().Format(time.RFC3339),
+		Level:   l.String(),
+		Message: strings.TrimSpace(fmt.Sprintf(format, v...)),
+	})
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(outWriter, string(b))
+}
+
+// Debugf prints a debug message.
+func Debugf(format string, v ...interface{}) {
+	printf(LevelDebug, utilslog.Debugf, format, v...)
+}
+
+// Infof prints an info message.
+func Infof(format string, v ...interface{}) {
+	printf(LevelInfo, utilslog.Infof, format, v...)
+}
+
+// Printf prints an info message without highlighting.
+func Printf(format string, v ...interface{}) {
+	printf(LevelInfo, utilslog.Printf, format, v...)
+}
+
+// Donef prints a success message.
+func Donef(format string, v ...interface{}) {
+	printf(LevelInfo, utilslog.Donef, format, v...)
+}
+
+// Warnf prints a warning.
+func Warnf(format string, v ...interface{}) {
+	printf(LevelWarn, utilslog.Warnf, format, v...)
+}
+
+// Errorf prints an error.
+func Errorf(format string, v ...interface{}) {
+	printf(LevelError, utilslog.Errorf, format, v...)
+}
@@ -0,0 +1,52 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONFormat(t *testing.T) {
+	var b bytes.Buffer
+	SetOutWriter(&b)
+	SetJSONFormat(true)
+	SetLevel(LevelWarn)
+	defer func() {
+		SetJSONFormat(false)
+		SetLevel(LevelInfo)
+	}()
+
+	Infof("Sending message")
+	Warnf("Failed to send: %s\n", "timeout")
+
+	var got jsonMessage
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("output is not a single JSON line: %s", b.String())
+	}
+	if got.Level != "warn" || got.Message != "Failed to send: timeout" {
+		t.Errorf("Warnf() printed %+v, want a warn level message", got)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    Level
+		wantErr bool
+	}{
+		{s: "debug", want: LevelDebug},
+		{s: " WARN ", want: LevelWarn},
+		{s: "verbose", want: LevelInfo, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseLevel(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLevel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
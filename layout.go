@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed layouts/*.json.tmpl
+var layoutFS embed.FS
+
+// layoutNames returns the names of the built-in layouts.
+func layoutNames() []string {
+	entries, err := layoutFS.ReadDir("layouts")
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".json.tmpl"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// layoutTemplate returns the Block Kit template of the built-in layout.
+func layoutTemplate(name string) (string, error) {
+	b, err := layoutFS.ReadFile(path.Join("layouts", name+".json.tmpl"))
+	if err != nil {
+		return "", fmt.Errorf("unknown layout (%s), available layouts: %s", name, strings.Join(layoutNames(), ", "))
+	}
+	return string(b), nil
+}
+
+// layoutData is available in the Block Kit templates.
+type layoutData struct {
+	// Build result
+	Success     bool
+	Status      string
+	StatusEmoji string
+
+	// Message inputs
+	Text       string
+	PreText    string
+	AuthorName string
+	Title      string
+	TitleLink  string
+	Message    string
+	ImageURL   string
+	ThumbURL   string
+	Footer     string
+	FooterIcon string
+	Fields     []Field
+	Buttons    []Button
+
+	// Build environment
+	AppTitle       string
+	AppURL         string
+	BuildNumber    string
+	BuildURL       string
+	Branch         string
+	Workflow       string
+	Pipeline       string
+	CommitHash     string
+	CommitMessage  string
+	InstallPageURL string
+
+	// Bitrise is set if the build details are fetched from the Bitrise API
+	Bitrise *bitriseBuild
+
+	// Version is set if the app version is read from the version file
+	Version *appVersion
+
+	// ReleaseNotes is the mrkdwn content of the release notes file
+	ReleaseNotes string
+
+	// Platform is ios or android, empty if the platform of the build is unknown
+	Platform string
+
+	// Symbols is set if a symbol upload provider is set
+	Symbols *symbolUpload
+
+	// Store is set if any of the store submission inputs is set
+	Store *storeSubmission
+}
+
+// newLayoutData collects the template data from the message and the build environment.
+func newLayoutData(conf config, msg Message) layoutData {
+	data := layoutData{
+		Success: conf.Success,
+		Status:  "Succeeded",
+		Text:    msg.Text,
+
+		AppTitle:       os.Getenv("BITRISE_APP_TITLE"),
+		AppURL:         os.Getenv("BITRISE_APP_URL"),
+		BuildNumber:    os.Getenv("BITRISE_BUILD_NUMBER"),
+		BuildURL:       os.Getenv("BITRISE_BUILD_URL"),
+		Branch:         os.Getenv("BITRISE_GIT_BRANCH"),
+		Workflow:       os.Getenv("BITRISE_TRIGGERED_WORKFLOW_ID"),
+		Pipeline:       os.Getenv("BITRISEIO_PIPELINE_TITLE"),
+		CommitHash:     os.Getenv("GIT_CLONE_COMMIT_HASH"),
+		CommitMessage:  os.Getenv("GIT_CLONE_COMMIT_MESSAGE_SUBJECT"),
+		InstallPageURL: os.Getenv("BITRISE_PUBLIC_INSTALL_PAGE_URL"),
+
+		Bitrise:      conf.BitriseBuild,
+		Version:      conf.AppVersion,
+		ReleaseNotes: conf.ReleaseNotes,
+		Platform:     conf.Platform,
+		Symbols:      conf.Symbols,
+		Store:        conf.Store,
+	}
+	if !conf.Success {
+		data.Status = "Failed"
+	}
+	if conf.Aborted {
+		data.Status = "Aborted"
+	}
+	data.StatusEmoji = defaultStatusEmoji[statusName(conf.Success, conf.Aborted)]
+	if emoji, ok := conf.EmojiMap[statusName(conf.Success, conf.Aborted)]; ok {
+		data.StatusEmoji = emoji
+	}
+
+	if len(msg.Attachments) > 0 {
+		a := msg.Attachments[0]
+		data.PreText = a.PreText
+		data.AuthorName = a.AuthorName
+		data.Title = a.Title
+		data.TitleLink = a.TitleLink
+		data.Message = a.Text
+		data.ImageURL = a.ImageURL
+		data.ThumbURL = a.ThumbURL
+		data.Footer = a.Footer
+		data.FooterIcon = a.FooterIcon
+		data.Fields = a.Fields
+		data.Buttons = a.Buttons
+	}
+	return data
+}
+
+// templateFuncs are the functions available in the Block Kit templates.
+var templateFuncs = template.FuncMap{
+	// json encodes the value as a JSON string literal, including the quotes.
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join":          strings.Join,
+	"lower":         strings.ToLower,
+	"upper":         strings.ToUpper,
+	"truncate":      truncateText,
+	"default":       defaultValue,
+	"now":           time.Now,
+	"durationSince": durationSince,
+	"env":           os.Getenv,
+	"jsonEscape":    jsonEscape,
+	"slackEscape":   slackEscape,
+	"ternary": func(cond bool, ifTrue, ifFalse interface{}) interface{} {
+		if cond {
+			return ifTrue
+		}
+		return ifFalse
+	},
+}
+
+// templateOptions are the settings of the Block Kit templates.
+type templateOptions struct {
+	PartialsDir string
+	// Location and DateFormat are used by the time functions, see timeFuncs.
+	Location   *time.Location
+	DateFormat string
+	// Translations of the strings of the layouts, see translate.
+	Translations map[string]string
+}
+
+// renderBlocks executes the Block Kit template, including the partials from the
+// partials dir. The result is either a list of blocks or an object with a
+// blocks key, as exported by the Block Kit Builder.
+func renderBlocks(tmpl string, data layoutData, opts templateOptions) ([]Block, error) {
+	t, err := parseTemplate("blocks", tmpl, opts, data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blocks template: %s", err)
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return nil, fmt.Errorf("failed to render blocks template: %s", err)
+	}
+
+	rendered := bytes.TrimSpace(b.Bytes())
+	if bytes.HasPrefix(rendered, []byte("{")) {
+		var obj struct {
+			Blocks []Block `json:"blocks"`
+		}
+		if err := json.Unmarshal(rendered, &obj); err != nil {
+			return nil, fmt.Errorf("rendered blocks are not valid JSON: %s", err)
+		}
+		return obj.Blocks, nil
+	}
+
+	var blocks []Block
+	if err := json.Unmarshal(rendered, &blocks); err != nil {
+		return nil, fmt.Errorf("rendered blocks are not valid JSON: %s", err)
+	}
+	return blocks, nil
+}
+
+// withBlocks replaces the attachment of msg with the blocks rendered from the
+// custom blocks template or from the selected layout.
+func withBlocks(conf config, msg Message) (Message, error) {
+	tmpl := conf.Blocks
+	if tmpl == "" {
+		var err error
+		if tmpl, err = layoutTemplate(conf.Layout); err != nil {
+			return Message{}, err
+		}
+	}
+
+	data := newLayoutData(conf, msg)
+	opts := templateOptions{
+		PartialsDir:  conf.PartialsDir,
+		Location:     conf.Location,
+		DateFormat:   conf.DateFormat,
+		Translations: conf.Translations,
+	}
+	blocks, err := renderBlocks(tmpl, data, opts)
+	if err != nil {
+		return Message{}, err
+	}
+	blocks = withHeaderAndContext(blocks, conf.HeaderText, conf.ContextItems)
+	if conf.BlockFields {
+		blocks = append(blocks, fieldSections(data.Fields)...)
+	}
+	if b := imageBlock(conf.ImageURLBlock, conf.ImageAltText); b != nil {
+		blocks = append(blocks, b)
+	}
+
+	if msg.Text == "" && len(msg.Attachments) > 0 {
+		// Used in notifications, as blocks are not shown there.
+		msg.Text = msg.Attachments[0].Fallback
+	}
+	if conf.ReleaseNotes != "" && !strings.Contains(tmpl, ".ReleaseNotes") {
+		// The release notes field is dropped with the attachment, unless the template shows it.
+		blocks = append(blocks, Block{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": "*" + translate(conf.Translations)("What's new") + "*\n" + conf.ReleaseNotes},
+		})
+	}
+	msg.Blocks = blocks
+	// The blocks replace the main attachment, the additional ones are kept.
+	if len(msg.Attachments) > 1 {
+		msg.Attachments = msg.Attachments[1:]
+	} else {
+		msg.Attachments = nil
+	}
+	return msg, nil
+}
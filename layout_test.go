@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+)
+
+func Test_renderBlocks_layouts(t *testing.T) {
+	data := []layoutData{
+		{
+			Success:     true,
+			Status:      "Succeeded",
+			StatusEmoji: ":white_check_mark:",
+			Title:       "Fix \"login\" crash",
+			Message:     "line1\nline2",
+			AuthorName:  "Jane",
+			Buttons:     []Button{{Text: "View App", URL: "https://app.bitrise.io/app/1"}},
+			AppTitle:    "My App",
+			BuildNumber: "42",
+			BuildURL:    "https://app.bitrise.io/build/1",
+			Branch:      "main",
+			Version:     &appVersion{Version: "2.14.0", BuildNumber: "1234"},
+			Symbols:     &symbolUpload{Provider: "Crashlytics", Kind: "dSYMs", UUIDs: []string{"A1B2", "C3D4"}},
+			Store:       &storeSubmission{Track: "TestFlight", ReviewStatus: "Waiting for Review", ConsoleName: "App Store Connect", ConsoleURL: "https://appstoreconnect.apple.com/apps"},
+		},
+		{
+			Status:      "Failed",
+			StatusEmoji: ":x:",
+		},
+	}
+
+	for _, name := range layoutNames() {
+		tmpl, err := layoutTemplate(name)
+		if err != nil {
+			t.Fatalf("layoutTemplate(%s) error = %v", name, err)
+		}
+		for _, d := range data {
+			blocks, err := renderBlocks(tmpl, d, templateOptions{})
+			if err != nil {
+				t.Errorf("renderBlocks(%s, %s) error = %v", name, d.Status, err)
+				continue
+			}
+			if len(blocks) == 0 {
+				t.Errorf("renderBlocks(%s, %s) returned no blocks", name, d.Status)
+			}
+			if err := checkBlocks(blocks); err != nil {
+				t.Errorf("renderBlocks(%s, %s) = invalid blocks: %v", name, d.Status, err)
+			}
+		}
+	}
+}
+
+func Test_renderBlocks(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "List of blocks",
+			tmpl: `[{"type": "section", "text": {"type": "mrkdwn", "text": {{json .Message}}}}]`,
+			want: 1,
+		},
+		{
+			name: "Block Kit Builder export",
+			tmpl: `{"blocks": [{"type": "divider"}, {"type": "divider"}]}`,
+			want: 2,
+		},
+		{
+			name:    "Invalid JSON",
+			tmpl:    `[{"type": "section", "text": {{.Message}}}]`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderBlocks(tt.tmpl, layoutData{Message: "a \"quoted\"\nmessage"}, templateOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderBlocks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("renderBlocks() = %v, want %d blocks", got, tt.want)
+			}
+		})
+	}
+}
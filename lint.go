@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// defaultLintViolationsLimit is the number of violations listed in the thread reply.
+const defaultLintViolationsLimit = 10
+
+// lintViolation is a single finding of a lint or static analysis report.
+type lintViolation struct {
+	File     string
+	Line     int
+	Severity string
+	Message  string
+	Rule     string
+}
+
+func (v lintViolation) isError() bool {
+	return v.Severity == "error"
+}
+
+// readLintReport reads the violations from a SwiftLint JSON or a Checkstyle XML
+// (ktlint, detekt, SwiftLint) report.
+func readLintReport(pth string) ([]lintViolation, error) {
+	b, err := os.ReadFile(pth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lint report: %s", err)
+	}
+	return parseLintReport(b)
+}
+
+// parseLintReport parses a SwiftLint JSON or a Checkstyle XML report, detected from the content.
+func parseLintReport(b []byte) ([]lintViolation, error) {
+	b = bytes.TrimSpace(b)
+	if bytes.HasPrefix(b, []byte("[")) {
+		return parseSwiftLintReport(b)
+	}
+	return parseCheckstyleReport(b)
+}
+
+func parseSwiftLintReport(b []byte) ([]lintViolation, error) {
+	var report []struct {
+		File     string `json:"file"`
+		Line     int    `json:"line"`
+		Severity string `json:"severity"`
+		Reason   string `json:"reason"`
+		RuleID   string `json:"rule_id"`
+	}
+	if err := json.Unmarshal(b, &report); err != nil {
+		return nil, fmt.Errorf("invalid SwiftLint report: %s", err)
+	}
+
+	var violations []lintViolation
+	for _, v := range report {
+		violations = append(violations, lintViolation{
+			File:     v.File,
+			Line:     v.Line,
+			Severity: strings.ToLower(v.Severity),
+			Message:  v.Reason,
+			Rule:     v.RuleID,
+		})
+	}
+	return violations, nil
+}
+
+func parseCheckstyleReport(b []byte) ([]lintViolation, error) {
+	var report struct {
+		Files []struct {
+			Name   string `xml:"name,attr"`
+			Errors []struct {
+				Line     int    `xml:"line,attr"`
+				Severity string `xml:"severity,attr"`
+				Message  string `xml:"message,attr"`
+				Source   string `xml:"source,attr"`
+			} `xml:"error"`
+		} `xml:"file"`
+	}
+	if err := xml.Unmarshal(b, &report); err != nil {
+		return nil, fmt.Errorf("invalid Checkstyle report: %s", err)
+	}
+
+	var violations []lintViolation
+	for _, f := range report.Files {
+		for _, e := range f.Errors {
+			violations = append(violations, lintViolation{
+				File:     f.Name,
+				Line:     e.Line,
+				Severity: strings.ToLower(e.Severity),
+				Message:  e.Message,
+				Rule:     e.Source,
+			})
+		}
+	}
+	return violations, nil
+}
+
+// lintField shows the number of errors and warnings in a single field.
+func lintField(violations []lintViolation) Field {
+	errors := 0
+	for _, v := range violations {
+		if v.isError() {
+			errors++
+		}
+	}
+	short := true
+	return Field{
+		Title: "Lint",
+		Value: fmt.Sprintf("%d errors, %d warnings", errors, len(violations)-errors),
+		Short: &short,
+	}
+}
+
+// withLint returns a copy of msg with the lint summary below the other fields.
+func withLint(msg Message, violations []lintViolation) Message {
+	if len(msg.Attachments) == 0 {
+		return msg
+	}
+
+	attachments := append([]Attachment{}, msg.Attachments...)
+	attachments[0].Fields = append(append([]Field{}, attachments[0].Fields...), lintField(violations))
+	msg.Attachments = attachments
+	return msg
+}
+
+// lintReplyText lists the top violations, errors first. Paths are shown relative to dir.
+func lintReplyText(violations []lintViolation, limit int, dir string) string {
+	sorted := append([]lintViolation{}, violations...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].isError() && !sorted[j].isError()
+	})
+	if limit > 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+
+	var lines []string
+	for _, v := range sorted {
+		file := v.File
+		if rel, err := filepath.Rel(dir, file); err == nil && filepath.IsAbs(file) && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+
+		emoji := ":warning:"
+		if v.isError() {
+			emoji = ":x:"
+		}
+		line := fmt.Sprintf("%s `%s:%d` %s", emoji, file, v.Line, v.Message)
+		if v.Rule != "" {
+			line += " (" + v.Rule + ")"
+		}
+		lines = append(lines, line)
+	}
+	if more := len(violations) - len(sorted); more > 0 {
+		lines = append(lines, fmt.Sprintf("…and %d more", more))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// postLintReply replies to the message with the top violations. The attachment
+// text collapses in Slack if it is longer than a few lines.
+func postLintReply(conf config, channel, threadTs string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	text := lintReplyText(conf.LintViolations, conf.LintViolationsLimit, dir)
+	attachments, err := json.Marshal([]Attachment{{Fallback: text, Text: text, Color: conf.Color}})
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("channel", channel)
+	params.Set("thread_ts", threadTs)
+	params.Set("text", "Top lint violations")
+	params.Set("attachments", string(attachments))
+	return callAPI(string(conf.APIToken), "chat.postMessage", params, nil)
+}
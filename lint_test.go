@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseLintReport(t *testing.T) {
+	tests := []struct {
+		name   string
+		report string
+		want   []lintViolation
+	}{
+		{
+			name:   "SwiftLint JSON",
+			report: `[{"file": "/src/App/View.swift", "line": 12, "severity": "Warning", "reason": "Line should be 120 characters or less", "rule_id": "line_length"}]`,
+			want: []lintViolation{
+				{File: "/src/App/View.swift", Line: 12, Severity: "warning", Message: "Line should be 120 characters or less", Rule: "line_length"},
+			},
+		},
+		{
+			name: "Checkstyle XML",
+			report: `<?xml version="1.0" encoding="utf-8"?>
+<checkstyle version="4.3">
+  <file name="app/src/Main.kt">
+    <error line="3" column="1" severity="error" message="Unused import" source="ktlint:no-unused-imports" />
+    <error line="9" column="5" severity="warning" message="Magic number" source="detekt.MagicNumber" />
+  </file>
+</checkstyle>`,
+			want: []lintViolation{
+				{File: "app/src/Main.kt", Line: 3, Severity: "error", Message: "Unused import", Rule: "ktlint:no-unused-imports"},
+				{File: "app/src/Main.kt", Line: 9, Severity: "warning", Message: "Magic number", Rule: "detekt.MagicNumber"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLintReport([]byte(tt.report))
+			if err != nil {
+				t.Fatalf("parseLintReport() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLintReport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_lintReplyText(t *testing.T) {
+	violations := []lintViolation{
+		{File: "/src/App/View.swift", Line: 12, Severity: "warning", Message: "Line too long", Rule: "line_length"},
+		{File: "/src/App/Model.swift", Line: 3, Severity: "error", Message: "Force cast", Rule: "force_cast"},
+		{File: "/src/App/Model.swift", Line: 8, Severity: "warning", Message: "Trailing whitespace"},
+	}
+
+	want := ":x: `App/Model.swift:3` Force cast (force_cast)\n" +
+		":warning: `App/View.swift:12` Line too long (line_length)\n" +
+		"…and 1 more"
+	if got := lintReplyText(violations, 2, "/src"); got != want {
+		t.Errorf("lintReplyText() = %v, want %v", got, want)
+	}
+}
@@ -1,109 +1,540 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
+	"regexp"
 	"strings"
+	"text/template"
 	"time"
 
-	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+	"github.com/bitrise-steplib/steps-slack-message/pkg/slackmsg"
 	"github.com/bitrise-tools/go-steputils/stepconf"
 )
 
 // Input ...
 type Input struct {
-	Debug bool `env:"is_debug_mode,opt[yes,no]"`
+	Debug     bool   `env:"is_debug_mode,opt[yes,no]"`
+	LogLevel  string `env:"log_level,opt[debug,info,warn,error]"`
+	LogFormat string `env:"log_format,opt[text,json]"`
+	Provider  string `env:"provider"`
+	DryRun    bool   `env:"dry_run,opt[yes,no]"`
+
+	// ProviderWebhookURLs are the webhook URLs of the providers when several are selected.
+	ProviderWebhookURLs stepconf.Secret `env:"provider_webhook_urls"`
+
+	// Config file
+	ConfigFile string `env:"config_file"`
+
+	// Theme
+	Theme     string `env:"theme,opt[none,minimal,traffic-light,monochrome]"`
+	ThemeFile string `env:"theme_file"`
 
 	// Message
 	WebhookURL            stepconf.Secret `env:"webhook_url"`
+	WebhookURLOnSuccess   stepconf.Secret `env:"webhook_url_on_success"`
 	WebhookURLOnError     stepconf.Secret `env:"webhook_url_on_error"`
+	WebhookURLFile        string          `env:"webhook_url_file"`
+	WebhookURLs           stepconf.Secret `env:"webhook_urls"`
+	Environment           string          `env:"environment"`
 	APIToken              stepconf.Secret `env:"api_token"`
+	APITokenFile          string          `env:"api_token_file"`
+	SigningSecret         stepconf.Secret `env:"signing_secret"`
+	SignatureHeader       string          `env:"signature_header"`
+	TLSMinVersion         string          `env:"tls_min_version,opt[1.2,1.3]"`
+	TLSCipherSuites       string          `env:"tls_cipher_suites"`
+	AllowInsecure         bool            `env:"allow_insecure,opt[yes,no]"`
 	Channel               string          `env:"channel"`
+	ChannelOnSuccess      string          `env:"channel_on_success"`
 	ChannelOnError        string          `env:"channel_on_error"`
 	Text                  string          `env:"text"`
+	TextOnSuccess         string          `env:"text_on_success"`
 	TextOnError           string          `env:"text_on_error"`
 	IconEmoji             string          `env:"emoji"`
+	IconEmojiOnSuccess    string          `env:"emoji_on_success"`
 	IconEmojiOnError      string          `env:"emoji_on_error"`
+	EmojiMap              string          `env:"emoji_map"`
 	IconURL               string          `env:"icon_url"`
+	IconURLOnSuccess      string          `env:"icon_url_on_success"`
 	IconURLOnError        string          `env:"icon_url_on_error"`
+	CheckIconURL          bool            `env:"check_icon_url,opt[yes,no]"`
 	LinkNames             bool            `env:"link_names,opt[yes,no]"`
 	Username              string          `env:"from_username"`
+	UsernameOnSuccess     string          `env:"from_username_on_success"`
 	UsernameOnError       string          `env:"from_username_on_error"`
 	ThreadTs              string          `env:"thread_ts"`
+	ThreadTsOnSuccess     string          `env:"thread_ts_on_success"`
 	ThreadTsOnError       string          `env:"thread_ts_on_error"`
 	Ts                    string          `env:"ts"`
+	TsOnSuccess           string          `env:"ts_on_success"`
 	TsOnError             string          `env:"ts_on_error"`
 	ReplyBroadcast        bool            `env:"reply_broadcast,opt[yes,no]"`
 	ReplyBroadcastOnError bool            `env:"reply_broadcast_on_error,opt[yes,no]"`
+	ThreadManager         bool            `env:"thread_manager,opt[yes,no]"`
+	EphemeralUser         string          `env:"ephemeral_user"`
+	ScheduleAt            string          `env:"schedule_at"`
+	PinMessage            bool            `env:"pin_message,opt[yes,no]"`
+	Canvas                string          `env:"canvas"`
+	BookmarkTitle         string          `env:"bookmark_title"`
+	BookmarkURL           string          `env:"bookmark_url"`
+	ReminderHours         int             `env:"reminder_hours"`
+	ReminderText          string          `env:"reminder_text"`
+	ReminderKey           string          `env:"reminder_key"`
+	ReleaseReminderDate   string          `env:"release_reminder_date"`
+	ReleaseReminderDays   string          `env:"release_reminder_days_before"`
+	ReleaseReminderText   string          `env:"release_reminder_text"`
+	ReleaseReminderUser   string          `env:"release_reminder_user"`
+	ChannelTopic          string          `env:"channel_topic"`
+	ChannelTopicOnSuccess string          `env:"channel_topic_on_success"`
+	ChannelTopicOnError   string          `env:"channel_topic_on_error"`
+	UserGroupsOnError     string          `env:"user_groups_on_error"`
+	MentionEmailsOnError  string          `env:"mention_emails_on_error"`
+	MentionMapPath        string          `env:"mention_map_path"`
+	MentionReviewers      bool            `env:"mention_reviewers_on_error,opt[yes,no]"`
+	JoinChannel           bool            `env:"join_channel,opt[yes,no]"`
+	CreateChannel         bool            `env:"create_channel,opt[yes,no]"`
+	ValidateChannel       bool            `env:"validate_channel,opt[yes,no]"`
+	MetadataEventType     string          `env:"metadata_event_type"`
+	MetadataEventPayload  string          `env:"metadata_event_payload"`
+	MaskPatterns          string          `env:"mask_patterns"`
+
+	// Reaction
+	ReactionTs        string `env:"reaction_ts"`
+	Reaction          string `env:"reaction"`
+	ReactionOnSuccess string `env:"reaction_on_success"`
+	ReactionOnError   string `env:"reaction_on_error"`
+	RemoveReaction    string `env:"remove_reaction"`
+
+	// Delete
+	DeleteTs          string `env:"delete_ts"`
+	DeleteTsOnSuccess string `env:"delete_ts_on_success"`
+	DeleteTsOnError   string `env:"delete_ts_on_error"`
 
 	// Attachment
-	Color             string `env:"color,required"`
-	ColorOnError      string `env:"color_on_error"`
-	PreText           string `env:"pretext"`
-	PreTextOnError    string `env:"pretext_on_error"`
-	AuthorName        string `env:"author_name"`
-	Title             string `env:"title"`
-	TitleOnError      string `env:"title_on_error"`
-	TitleLink         string `env:"title_link"`
-	Message           string `env:"message"`
-	MessageOnError    string `env:"message_on_error"`
-	ImageURL          string `env:"image_url"`
-	ImageURLOnError   string `env:"image_url_on_error"`
-	ThumbURL          string `env:"thumb_url"`
-	ThumbURLOnError   string `env:"thumb_url_on_error"`
-	Footer            string `env:"footer"`
-	FooterOnError     string `env:"footer_on_error"`
-	FooterIcon        string `env:"footer_icon"`
-	FooterIconOnError string `env:"footer_icon_on_error"`
-	TimeStamp         bool   `env:"timestamp,opt[yes,no]"`
-	Fields            string `env:"fields"`
-	Buttons           string `env:"buttons"`
+	Color               string `env:"color"`
+	ColorOnSuccess      string `env:"color_on_success"`
+	ColorOnError        string `env:"color_on_error"`
+	PreText             string `env:"pretext"`
+	PreTextOnSuccess    string `env:"pretext_on_success"`
+	PreTextOnError      string `env:"pretext_on_error"`
+	AuthorName          string `env:"author_name"`
+	Title               string `env:"title"`
+	TitleOnSuccess      string `env:"title_on_success"`
+	TitleOnError        string `env:"title_on_error"`
+	TitleLink           string `env:"title_link"`
+	Message             string `env:"message"`
+	MessageFilePath     string `env:"message_file_path"`
+	MessageFromStdin    bool   `env:"message_from_stdin,opt[yes,no]"`
+	MessageOnSuccess    string `env:"message_on_success"`
+	MessageOnError      string `env:"message_on_error"`
+	MrkdwnIn            string `env:"mrkdwn_in"`
+	Attachments         string `env:"attachments"`
+	AttachmentsFilePath string `env:"attachments_file_path"`
+	Fallback            string `env:"fallback"`
+	FallbackOnSuccess   string `env:"fallback_on_success"`
+	FallbackOnError     string `env:"fallback_on_error"`
+	ImageURL            string `env:"image_url"`
+	ImageURLOnSuccess   string `env:"image_url_on_success"`
+	ImageURLOnError     string `env:"image_url_on_error"`
+	ThumbURL            string `env:"thumb_url"`
+	ThumbURLOnSuccess   string `env:"thumb_url_on_success"`
+	ThumbURLOnError     string `env:"thumb_url_on_error"`
+	Footer              string `env:"footer"`
+	FooterOnSuccess     string `env:"footer_on_success"`
+	FooterOnError       string `env:"footer_on_error"`
+	FooterIcon          string `env:"footer_icon"`
+	FooterIconOnSuccess string `env:"footer_icon_on_success"`
+	FooterIconOnError   string `env:"footer_icon_on_error"`
+	TimeStamp           bool   `env:"timestamp,opt[yes,no]"`
+	Fields              string `env:"fields"`
+	Buttons             string `env:"buttons"`
+
+	// Blocks
+	Layout           string `env:"layout"`
+	Blocks           string `env:"blocks"`
+	BlocksFilePath   string `env:"blocks_file_path"`
+	PartialsDir      string `env:"partials_dir"`
+	Timezone         string `env:"timezone"`
+	DateFormat       string `env:"date_format"`
+	Language         string `env:"language,opt[en,de,es,fr,ja,pt]"`
+	TranslationsFile string `env:"translations_file"`
+	HeaderText       string `env:"header_text"`
+	BlockFields      bool   `env:"block_fields,opt[yes,no]"`
+	ConvertToBlocks  bool   `env:"convert_to_blocks,opt[yes,no]"`
+	ImageURLBlock    string `env:"image_url_block"`
+	ImageAltText     string `env:"image_alt_text"`
+	ContextItems     string `env:"context_items"`
+
+	// Screenshots
+	ScreenshotsDir   string `env:"screenshots_dir"`
+	ScreenshotsLimit int    `env:"screenshots_limit"`
+
+	// Coverage
+	Coverage           string `env:"coverage"`
+	CoverageReportPath string `env:"coverage_report_path"`
+	CoveragePrevious   string `env:"coverage_previous"`
+	CoverageKey        string `env:"coverage_key"`
+
+	// Tickets
+	TicketBaseURL string `env:"ticket_base_url"`
+	TicketPattern string `env:"ticket_pattern"`
+	TicketSources string `env:"ticket_sources"`
+
+	// Compare link
+	CompareLink           bool   `env:"compare_link,opt[yes,no]"`
+	RepositoryURL         string `env:"repository_url"`
+	CommitHash            string `env:"commit_hash"`
+	ComparePreviousCommit string `env:"compare_previous_commit"`
+	CompareKey            string `env:"compare_key"`
+
+	// App version
+	VersionFilePath string `env:"version_file_path"`
+
+	// Release notes
+	ReleaseNotesPath      string `env:"release_notes_path"`
+	ReleaseNotesMaxLength int    `env:"release_notes_max_length"`
+
+	// Device tests
+	DeviceTestResultsPath string `env:"device_test_results_path"`
+
+	// Lint
+	LintReportPath      string `env:"lint_report_path"`
+	LintViolationsLimit int    `env:"lint_violations_limit"`
+
+	// Symbol upload
+	SymbolsProvider string `env:"symbols_provider"`
+	SymbolsUUIDs    string `env:"symbols_uuids"`
+
+	// Store submission
+	StoreTrack        string `env:"store_track"`
+	StorePhase        string `env:"store_phase"`
+	StoreReviewStatus string `env:"store_review_status"`
+	StoreConsoleURL   string `env:"store_console_url"`
+
+	// Platform
+	Platform       string `env:"platform,opt[auto,ios,android,none]"`
+	MessageIOS     string `env:"message_ios"`
+	MessageAndroid string `env:"message_android"`
+	IconURLIOS     string `env:"icon_url_ios"`
+	IconURLAndroid string `env:"icon_url_android"`
+
+	// Workflow webhook
+	WorkflowVariables string `env:"workflow_variables"`
+
+	// Raw payload
+	PayloadJSON     string `env:"payload_json"`
+	PayloadFilePath string `env:"payload_file_path"`
+	TransformScript string `env:"transform_script"`
+
+	// Approval
+	ApprovalStatusURL    string `env:"approval_status_url"`
+	ApprovalApproveURL   string `env:"approval_approve_url"`
+	ApprovalRejectURL    string `env:"approval_reject_url"`
+	ApprovalReactions    bool   `env:"approval_reactions,opt[yes,no]"`
+	ApprovalUsers        string `env:"approval_users"`
+	ApprovalTimeout      int    `env:"approval_timeout"`
+	ApprovalPollInterval int    `env:"approval_poll_interval"`
+
+	// Fallback
+	FallbackWebhookURL stepconf.Secret `env:"fallback_webhook_url"`
+	SMTPHost           string          `env:"smtp_host"`
+	SMTPPort           int             `env:"smtp_port"`
+	SMTPUsername       string          `env:"smtp_username"`
+	SMTPPassword       stepconf.Secret `env:"smtp_password"`
+	SMTPFrom           string          `env:"smtp_from"`
+	SMTPTo             string          `env:"smtp_to"`
+
+	// Telegram
+	TelegramBotToken stepconf.Secret `env:"telegram_bot_token"`
+	TelegramChatID   string          `env:"telegram_chat_id"`
+
+	// Webex
+	WebexBotToken stepconf.Secret `env:"webex_bot_token"`
+	WebexRoomID   string          `env:"webex_room_id"`
+
+	// Matrix
+	MatrixHomeserverURL string          `env:"matrix_homeserver_url"`
+	MatrixAccessToken   stepconf.Secret `env:"matrix_access_token"`
+	MatrixRoomID        string          `env:"matrix_room_id"`
+
+	// Zulip
+	ZulipSiteURL        string          `env:"zulip_site_url"`
+	ZulipBotEmail       string          `env:"zulip_bot_email"`
+	ZulipAPIKey         stepconf.Secret `env:"zulip_api_key"`
+	ZulipStream         string          `env:"zulip_stream"`
+	ZulipTopic          string          `env:"zulip_topic"`
+	ZulipTopicOnSuccess string          `env:"zulip_topic_on_success"`
+	ZulipTopicOnError   string          `env:"zulip_topic_on_error"`
+
+	// SNS
+	SNSTopicARN string `env:"sns_topic_arn"`
+	SNSRegion   string `env:"sns_region"`
+
+	// Push notifications
+	NtfyTopicURL     string          `env:"ntfy_topic_url"`
+	NtfyAccessToken  stepconf.Secret `env:"ntfy_access_token"`
+	PushoverAppToken stepconf.Secret `env:"pushover_app_token"`
+	PushoverUserKey  stepconf.Secret `env:"pushover_user_key"`
+	QueueFilePath    string          `env:"queue_file_path"`
+	FlushQueue       bool            `env:"flush_queue,opt[yes,no]"`
+
+	// Escalation
+	EscalationThreshold int             `env:"escalation_threshold"`
+	EscalationKey       string          `env:"escalation_key"`
+	PagerDutyRoutingKey stepconf.Secret `env:"pagerduty_routing_key"`
+	OpsgenieAPIKey      stepconf.Secret `env:"opsgenie_api_key"`
+	OpsgenieAPIURL      string          `env:"opsgenie_api_url"`
+
+	// GitHub
+	GitHubMirror        string          `env:"github_mirror,opt[off,status,comment,both]"`
+	GitHubToken         stepconf.Secret `env:"github_token"`
+	GitHubAPIURL        string          `env:"github_api_url"`
+	GitHubRepository    string          `env:"github_repository"`
+	GitHubPullRequest   string          `env:"github_pull_request"`
+	GitHubStatusContext string          `env:"github_status_context"`
+
+	// GitLab
+	GitLabToken        stepconf.Secret `env:"gitlab_token"`
+	GitLabAPIURL       string          `env:"gitlab_api_url"`
+	GitLabProject      string          `env:"gitlab_project"`
+	GitLabMergeRequest string          `env:"gitlab_merge_request"`
+
+	// Batch
+	BatchFilePath         string `env:"batch_file_path"`
+	BatchInterval         int    `env:"batch_interval"`
+	BatchConcurrency      int    `env:"batch_concurrency"`
+	BatchMaxFailedPercent int    `env:"batch_max_failed_percent"`
+
+	// Digest
+	DigestMode      string `env:"digest_mode,opt[off,append,send]"`
+	DigestFilePath  string `env:"digest_file_path"`
+	DigestEntryName string `env:"digest_entry_name"`
+	BuildURL        string `env:"build_url"`
+
+	// Pipeline
+	PipelineMode string `env:"pipeline_mode,opt[off,root,stage,rollup]"`
+	PipelineID   string `env:"pipeline_id"`
 
 	// Status
 	BuildStatus         string `env:"build_status"`
+	BuildStatusEnv      string `env:"build_status_env"`
+	SuccessValues       string `env:"success_values"`
+	BuildStatusOverride string `env:"build_status_override,opt[auto,success,failed,aborted]"`
+	ListFailedSteps     bool   `env:"list_failed_steps,opt[yes,no]"`
+	ListArtifacts       bool   `env:"list_artifacts,opt[yes,no]"`
+	ArtifactsPattern    string `env:"artifacts_pattern"`
+	StepsSummaryPath    string `env:"steps_summary_path"`
 	PipelineBuildStatus string `env:"pipeline_build_status"`
 
 	// Step Outputs
 	ThreadTsOutputVariableName string `env:"output_thread_ts"`
+	DeployDir                  string `env:"deploy_dir"`
+
+	// Bitrise API
+	BitriseAPIToken    stepconf.Secret `env:"bitrise_api_token"`
+	BitriseAppSlug     string          `env:"bitrise_app_slug"`
+	BitriseBuildFields bool            `env:"bitrise_build_fields,opt[yes,no]"`
+
+	// Phase
+	Phase             string `env:"phase,opt[off,start,end]"`
+	PreTextInProgress string `env:"pretext_in_progress"`
+	ColorInProgress   string `env:"color_in_progress"`
+
+	// State
+	BuildSlug string `env:"build_slug"`
+	StateDir  string `env:"state_dir"`
+	Dedupe    bool   `env:"dedupe,opt[yes,no]"`
+	DedupeKey string `env:"dedupe_key"`
 }
 
 type config struct {
-	Debug bool `env:"is_debug_mode,opt[yes,no]"`
+	Debug    bool `env:"is_debug_mode,opt[yes,no]"`
+	Provider string
+	// Providers are the selected providers, if several are selected the message is sent with each.
+	Providers           []string
+	ProviderWebhookURLs map[string]string
 
 	// Message
-	APIToken       stepconf.Secret `env:"api_token"`
-	WebhookURL     string
-	Channel        string
-	Text           string
-	IconEmoji      string
-	IconURL        string
-	Username       string
-	ThreadTs       string
-	Ts             string
-	ReplyBroadcast bool
-	LinkNames      bool `env:"link_names,opt[yes,no]"`
+	APIToken        stepconf.Secret `env:"api_token"`
+	WebhookURL      string
+	SigningSecret   string
+	SignatureHeader string
+	Channel         string
+	Text            string
+	IconEmoji       string
+	EmojiMap        map[string]string
+	IconURL         string
+	Username        string
+	ThreadTs        string
+	Ts              string
+	ReplyBroadcast  bool
+	ThreadManager   bool
+	EphemeralUser   string
+	PostAt          int64
+	PinMessage      bool
+	// Canvas is the ID of the canvas the build is appended to, or canvasOfChannel.
+	Canvas string
+	// BookmarkTitle is the title of the channel bookmark pointing to BookmarkURL, empty if disabled.
+	BookmarkTitle string
+	BookmarkURL   string
+	// Reminder is disabled if its Hours is 0.
+	Reminder reminderConfig
+	// ReleaseReminder is disabled if its Date is zero.
+	ReleaseReminder releaseReminderConfig
+	// ChannelTopic is set as the topic of the channel, empty if disabled.
+	ChannelTopic    string
+	JoinChannel     bool
+	CreateChannel   bool
+	ValidateChannel bool
+	Metadata        *Metadata
+	MaskPatterns    []*regexp.Regexp
+	LinkNames       bool `env:"link_names,opt[yes,no]"`
+
+	// Reaction
+	ReactionTs     string
+	Reaction       string
+	RemoveReaction string
+
+	// Delete
+	DeleteTs string
 
 	// Attachment
-	Color      string
-	PreText    string
-	Title      string
-	Message    string
-	ImageURL   string
-	ThumbURL   string
-	AuthorName string `env:"author_name"`
-	TitleLink  string `env:"title_link"`
-	Footer     string `env:"footer"`
-	FooterIcon string `env:"footer_icon"`
-	TimeStamp  bool   `env:"timestamp,opt[yes,no]"`
-	Fields     string `env:"fields"`
-	Buttons    string `env:"buttons"`
+	Color       string
+	PreText     string
+	Title       string
+	Message     string
+	Fallback    string
+	MrkdwnIn    []string
+	Attachments []Attachment
+	ImageURL    string
+	ThumbURL    string
+	AuthorName  string `env:"author_name"`
+	TitleLink   string `env:"title_link"`
+	Footer      string `env:"footer"`
+	FooterIcon  string `env:"footer_icon"`
+	TimeStamp   bool   `env:"timestamp,opt[yes,no]"`
+	Fields      string `env:"fields"`
+	Buttons     string `env:"buttons"`
+
+	// Blocks
+	Layout      string
+	Blocks      string
+	PartialsDir string
+	// Location and DateFormat show the times of the templates and the canvas
+	// in the team's time zone.
+	Location   *time.Location
+	DateFormat string
+	// Translations of the strings of the layouts in the selected language.
+	Translations  map[string]string
+	HeaderText    string
+	BlockFields   bool
+	ImageURLBlock string
+	ImageAltText  string
+	ContextItems  string
+
+	// Screenshots
+	ScreenshotsDir   string
+	ScreenshotsLimit int
+
+	// Workflow webhook
+	WorkflowVariables string
+
+	// Raw payload
+	Payload         []byte
+	TransformScript string
+
+	// Approval
+	Approval approvalConfig
+
+	// Fallback
+	FallbackWebhookURL string
+	SMTP               smtpConfig
+	Telegram           telegramConfig
+	Webex              webexConfig
+	Matrix             matrixConfig
+	Zulip              zulipConfig
+	SNS                snsConfig
+	Ntfy               ntfyConfig
+	Pushover           pushoverConfig
+	QueueFilePath      string
+	FlushQueue         bool
+
+	// Escalation
+	Escalation escalationConfig
+
+	// GitHub
+	GitHub githubConfig
+
+	// GitLab
+	GitLab gitlabConfig
+
+	// Batch
+	BatchFilePath         string
+	BatchInterval         int
+	BatchConcurrency      int
+	BatchMaxFailedPercent int
+
+	// Digest
+	DigestMode      string
+	DigestFilePath  string
+	DigestEntryName string
+	BuildURL        string
+	Digest          []digestEntry
+
+	// Pipeline
+	PipelineMode string
+	PipelineID   string
+
+	// Status
+	Success  bool
+	Aborted  bool
+	Platform string
 
 	// Step Outputs
 	ThreadTsOutputVariableName string `env:"output_thread_ts"`
+	DeployDir                  string
+
+	// Bitrise API
+	BitriseBuild *bitriseBuild
+
+	// App version
+	AppVersion *appVersion
+
+	// Release notes
+	ReleaseNotes string
+
+	// Lint
+	LintViolations      []lintViolation
+	LintViolationsLimit int
+
+	// Symbol upload
+	Symbols *symbolUpload
+
+	// Store submission
+	Store *storeSubmission
+
+	// Phase is the phase of the build the message is sent in.
+	Phase string
+
+	// State
+	BuildSlug string
+	StateDir  string
+	// DedupeKey identifies the message among retries of the build, empty if deduplication is disabled.
+	DedupeKey string
+}
+
+// splitList splits a comma separated list, dropping the empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 // ensureNewlines replaces all \n substrings with newline characters.
@@ -111,12 +542,21 @@ func ensureNewlines(s string) string {
 	return strings.Replace(s, "\\n", "\n", -1)
 }
 
+// fallbackText returns the plain-text summary of the attachment, shown in the
+// notifications. It defaults to the text of the attachment.
+func fallbackText(c config) string {
+	if fallback := strings.TrimSpace(c.Fallback); fallback != "" {
+		return ensureNewlines(os.ExpandEnv(fallback))
+	}
+	return ensureNewlines(c.Message)
+}
+
 func newMessage(c config) Message {
 	msg := Message{
 		Channel: strings.TrimSpace(c.Channel),
 		Text:    c.Text,
 		Attachments: []Attachment{{
-			Fallback:   ensureNewlines(c.Message),
+			Fallback:   fallbackText(c),
 			Color:      c.Color,
 			PreText:    c.PreText,
 			AuthorName: c.AuthorName,
@@ -129,6 +569,7 @@ func newMessage(c config) Message {
 			Footer:     c.Footer,
 			FooterIcon: c.FooterIcon,
 			Buttons:    parseButtons(c.Buttons),
+			MrkdwnIn:   c.MrkdwnIn,
 		}},
 		IconEmoji:      c.IconEmoji,
 		IconURL:        c.IconURL,
@@ -137,146 +578,1213 @@ func newMessage(c config) Message {
 		ThreadTs:       c.ThreadTs,
 		Ts:             c.Ts,
 		ReplyBroadcast: c.ReplyBroadcast,
+		User:           strings.TrimSpace(c.EphemeralUser),
+		PostAt:         c.PostAt,
+		Metadata:       c.Metadata,
 	}
+	msg.Attachments = append(msg.Attachments, c.Attachments...)
 	if c.TimeStamp {
 		msg.Attachments[0].TimeStamp = int(time.Now().Unix())
 	}
 	return msg
 }
 
-// postMessage sends a message to a channel.
-func postMessage(conf config, msg Message) error {
+// slackAPIURL is the base URL of the Slack Web API methods, replaced in tests.
+var slackAPIURL = slackmsg.APIURL
+
+// payloadMethod returns the Web API method used to send the JSON payload, see slackmsg.Method.
+func payloadMethod(b []byte) string {
+	var msg Message
+	if err := json.Unmarshal(b, &msg); err != nil {
+		log.Debugf("Failed to parse the payload, sending it as a new message: %s", err)
+	}
+	return slackmsg.Method(msg)
+}
+
+// postMessage sends a message to a channel. The response is only returned
+// when the message is sent with an API token.
+func postMessage(conf config, msg Message) (*SendMessageResponse, error) {
 	b, err := json.Marshal(msg)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	return sendPayload(conf, b)
+}
+
+// sendPayload posts the JSON payload, then decodes the response and exports the
+// outputs. The response is only returned when the Web API is used.
+func sendPayload(conf config, b []byte) (*SendMessageResponse, error) {
+	if conf.TransformScript != "" {
+		var err error
+		if b, err = transformPayload(conf.TransformScript, b); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkPayloadBlocks(b); err != nil {
+		return nil, err
+	}
+
+	body, err := postPayload(conf, b)
+	if err != nil {
+		return nil, &postError{err: err, payload: b}
 	}
+
+	var response *SendMessageResponse
+	if strings.TrimSpace(conf.WebhookURL) == "" {
+		var r apiResponse
+		if err := json.Unmarshal(body, &r); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %s", err)
+		}
+		if !r.OK {
+			return nil, &postError{err: &apiError{Method: payloadMethod(b), Code: r.Error}, payload: b}
+		}
+
+		response = &SendMessageResponse{}
+		if err := json.Unmarshal(body, response); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %s", err)
+		}
+	}
+
+	if err := exportOutputs(&conf, response); err != nil {
+		return nil, fmt.Errorf("failed to export outputs: %s", err)
+	}
+
+	return response, nil
+}
+
+// postPayload sends the JSON payload to the webhook, or to the Web API method
+// if no webhook is configured, and returns the response body.
+func postPayload(conf config, b []byte) ([]byte, error) {
+	saveRenderedPayload(conf, b)
 	log.Debugf("Request to Slack: %s\n", b)
+	if link, err := previewURL(b); err == nil {
+		log.Debugf("Preview in the Block Kit Builder: %s", link)
+	}
+	logTextPreview(b)
 
 	url := strings.TrimSpace(conf.WebhookURL)
-	ts := strings.TrimSpace(conf.Ts)
-
 	if url == "" {
-		if ts == "" {
-			url = "https://slack.com/api/chat.postMessage"
-		} else {
-			url = "https://slack.com/api/chat.update"
+		url = slackAPIURL + payloadMethod(b)
+	}
+
+	client := slackmsg.Client{
+		APIToken:        string(conf.APIToken),
+		HTTPClient:      httpClient,
+		SigningSecret:   conf.SigningSecret,
+		SignatureHeader: conf.SignatureHeader,
+	}
+	return client.Post(context.Background(), url, b)
+}
+
+// validate checks the inputs and reports all the problems found at once.
+func validate(inp *Input) error {
+	var errs []string
+	addError := func(err error) {
+		errs = append(errs, err.Error())
+	}
+
+	names := splitList(inp.Provider)
+	switch provider := providerName(inp.Provider, string(inp.APIToken)); {
+	case len(names) > 1:
+		webhooks, err := parseProviderWebhooks(string(inp.ProviderWebhookURLs))
+		if err != nil {
+			addError(err)
+		}
+		for _, name := range names {
+			webhookURL, ok := webhooks[name]
+			if !ok {
+				webhookURL = string(inp.WebhookURL)
+			}
+			if err := checkProviderCredentials(*inp, providerName(name, string(inp.APIToken)), webhookURL); err != nil {
+				addError(err)
+			}
+		}
+	case inp.Provider == "" || inp.Provider == providerAuto:
+		if inp.APIToken == "" && inp.WebhookURL == "" {
+			addError(fmt.Errorf("Both API Token and WebhookURL are empty. You need to provide one of them. If you want to use incoming webhooks provide the webhook url. If you want to use a bot to send a message provide the bot API token"))
+		}
+
+		if inp.APIToken != "" && inp.WebhookURL != "" {
+			log.Warnf("Both API Token and WebhookURL are provided. Using the API Token")
+			inp.WebhookURL = ""
+		}
+	default:
+		if err := checkProviderCredentials(*inp, provider, string(inp.WebhookURL)); err != nil {
+			addError(err)
+			break
+		}
+		// Only the credentials of the provider are kept.
+		switch provider {
+		case "slack-api":
+			inp.WebhookURL = ""
+		case "webex":
+			if inp.WebexBotToken != "" {
+				inp.WebhookURL = ""
+			}
+			inp.APIToken = ""
+		case "slack-webhook", "teams", "discord", "generic":
+			inp.APIToken = ""
+		default:
+			inp.WebhookURL = ""
+			inp.APIToken = ""
 		}
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(b))
-	req.Header.Add("Content-Type", "application/json; charset=utf-8")
+	provider := providerName(inp.Provider, string(inp.APIToken))
+	type webhookCheck struct {
+		name, provider, url string
+	}
+	var providerWebhooks []webhookCheck
+	// Invalid provider webhook URLs are already reported.
+	webhooks, _ := parseProviderWebhooks(string(inp.ProviderWebhookURLs))
+	for name, url := range webhooks {
+		providerWebhooks = append(providerWebhooks, webhookCheck{fmt.Sprintf("Webhook URL of the %s provider", name), name, url})
+	}
+	for _, w := range append([]webhookCheck{
+		{"Webhook URL", provider, string(inp.WebhookURL)},
+		{"Webhook URL if the build succeeded", provider, string(inp.WebhookURLOnSuccess)},
+		{"Webhook URL if the build failed", provider, string(inp.WebhookURLOnError)},
+		{"Fallback Webhook URL", "slack-webhook", string(inp.FallbackWebhookURL)},
+		{"Matrix homeserver URL", "matrix", inp.MatrixHomeserverURL},
+		{"Zulip site URL", "zulip", inp.ZulipSiteURL},
+		{"ntfy topic URL", "ntfy", inp.NtfyTopicURL},
+		{"Opsgenie API URL", "opsgenie", inp.OpsgenieAPIURL},
+		{"GitHub API URL", "github", inp.GitHubAPIURL},
+		{"GitLab API URL", "gitlab", inp.GitLabAPIURL},
+	}, providerWebhooks...) {
+		if strings.TrimSpace(w.url) == "" {
+			continue
+		}
+		warning, err := checkWebhookURL(w.provider, w.url, inp.AllowInsecure)
+		if err != nil {
+			addError(fmt.Errorf("Invalid %s: %s", w.name, err))
+		} else if warning != "" {
+			log.Warnf("%s: %s", w.name, warning)
+		}
+	}
 
-	if string(conf.APIToken) != "" {
-		req.Header.Add("Authorization", "Bearer "+string(conf.APIToken))
+	if _, err := parseTLSConfig(inp.TLSMinVersion, inp.TLSCipherSuites); err != nil {
+		addError(fmt.Errorf("Invalid TLS settings: %s", err))
 	}
 
-	client := &http.Client{}
+	if inp.EphemeralUser != "" {
+		if inp.APIToken == "" {
+			addError(fmt.Errorf("Ephemeral messages can only be sent with an API Token"))
+		}
+		if inp.Ts != "" || inp.TsOnSuccess != "" || inp.TsOnError != "" {
+			addError(fmt.Errorf("Ephemeral messages can not be updated, remove the Message Timestamp inputs"))
+		}
+	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send the request: %s", err)
+	if inp.ScheduleAt != "" {
+		if inp.APIToken == "" {
+			addError(fmt.Errorf("Scheduled messages can only be sent with an API Token"))
+		}
+		if inp.Ts != "" || inp.TsOnSuccess != "" || inp.TsOnError != "" || inp.EphemeralUser != "" {
+			addError(fmt.Errorf("Scheduled messages can not update a message or be ephemeral"))
+		}
+		if _, err := parseScheduleAt(inp.ScheduleAt, time.Now()); err != nil {
+			addError(err)
+		}
 	}
-	defer func() {
-		if cerr := resp.Body.Close(); err == nil {
-			err = cerr
+
+	if inp.PinMessage {
+		if inp.APIToken == "" {
+			addError(fmt.Errorf("Messages can only be pinned with an API Token"))
+		}
+		if inp.EphemeralUser != "" || inp.ScheduleAt != "" {
+			addError(fmt.Errorf("Ephemeral and scheduled messages can not be pinned"))
 		}
-	}()
+	}
 
-	if resp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("server error: %s, failed to read response: %s", resp.Status, err)
+	if strings.TrimSpace(inp.Canvas) != "" && inp.APIToken == "" {
+		addError(fmt.Errorf("Canvases can only be edited with an API Token"))
+	}
+
+	if (inp.ChannelTopic != "" || inp.ChannelTopicOnSuccess != "" || inp.ChannelTopicOnError != "") && inp.APIToken == "" {
+		addError(fmt.Errorf("The channel topic can only be set with an API Token"))
+	}
+
+	if inp.UserGroupsOnError != "" && inp.APIToken == "" {
+		addError(fmt.Errorf("User groups can only be resolved with an API Token"))
+	}
+	if pth := strings.TrimSpace(inp.MentionMapPath); pth != "" {
+		if _, err := readMentionMap(pth); err != nil {
+			addError(err)
 		}
-		return fmt.Errorf("server error: %s, response: %s", resp.Status, body)
 	}
 
-	if err := exportOutputs(&conf, resp); err != nil {
-		return fmt.Errorf("failed to export outputs: %s", err)
+	if inp.ReminderHours < 0 {
+		addError(fmt.Errorf("Reminder hours must not be negative"))
+	}
+	if inp.ReminderHours > 0 {
+		if inp.APIToken == "" {
+			addError(fmt.Errorf("Reminders can only be scheduled with an API Token"))
+		}
+		if strings.TrimSpace(inp.ReminderText) == "" {
+			addError(fmt.Errorf("Reminder text is required when reminder hours is set"))
+		}
 	}
 
-	return nil
-}
+	if strings.TrimSpace(inp.ReleaseReminderDate) != "" {
+		if inp.APIToken == "" {
+			addError(fmt.Errorf("Release reminders can only be added with an API Token"))
+		}
+		if _, err := parseReleaseReminderDate(inp.ReleaseReminderDate); err != nil {
+			addError(err)
+		}
+		if _, err := parseReleaseReminderDays(inp.ReleaseReminderDays); err != nil {
+			addError(err)
+		}
+		if _, err := template.New("reminder").Parse(inp.ReleaseReminderText); err != nil {
+			addError(fmt.Errorf("Invalid release reminder text: %s", err))
+		}
+	}
 
-func validate(inp *Input) error {
-	if inp.APIToken == "" && inp.WebhookURL == "" {
-		return fmt.Errorf("Both API Token and WebhookURL are empty. You need to provide one of them. If you want to use incoming webhooks provide the webhook url. If you want to use a bot to send a message provide the bot API token")
+	if strings.TrimSpace(inp.BookmarkTitle) != "" {
+		if inp.APIToken == "" {
+			addError(fmt.Errorf("Bookmarks can only be added with an API Token"))
+		}
+		if strings.TrimSpace(inp.BookmarkURL) == "" {
+			addError(fmt.Errorf("Bookmark URL is required when a bookmark title is provided"))
+		}
+	}
+
+	// The other providers post to the channel of the webhook.
+	if hasSlackProvider(inp.Provider, string(inp.APIToken)) {
+		for _, c := range []struct{ name, channel string }{
+			{name: "Channel", channel: inp.Channel},
+			{name: "Channel if the build succeeded", channel: inp.ChannelOnSuccess},
+			{name: "Channel if the build failed", channel: inp.ChannelOnError},
+		} {
+			warning, err := checkChannel(normalizeChannel(c.channel), inp.APIToken == "")
+			if err != nil {
+				addError(fmt.Errorf("%s: %s", c.name, err))
+			} else if warning != "" {
+				log.Warnf("%s: %s", c.name, warning)
+			}
+		}
+	}
+
+	if (inp.JoinChannel || inp.CreateChannel) && inp.APIToken == "" {
+		addError(fmt.Errorf("Channels can only be joined or created with an API Token"))
+	}
+
+	if inp.ThreadManager {
+		if inp.APIToken == "" {
+			addError(fmt.Errorf("Thread manager requires an API Token"))
+		}
+		if inp.BuildSlug == "" {
+			addError(fmt.Errorf("Thread manager requires the build slug"))
+		}
+	}
+
+	if inp.ReactionTs != "" && inp.APIToken == "" {
+		addError(fmt.Errorf("Reactions can only be added with an API Token"))
+	}
+
+	if inp.Phase == phaseStart || inp.Phase == phaseEnd {
+		if inp.APIToken == "" {
+			addError(fmt.Errorf("Build phases require an API Token"))
+		}
+		if inp.BuildSlug == "" {
+			addError(fmt.Errorf("Build phases require the build slug"))
+		}
+		if inp.Phase == phaseStart {
+			if _, err := resolveColor(inp.ColorInProgress, true); err != nil {
+				addError(err)
+			}
+		}
+	}
+
+	if inp.PipelineMode != "" && inp.PipelineMode != pipelineModeOff {
+		if inp.APIToken == "" {
+			addError(fmt.Errorf("Pipeline notifications require an API Token"))
+		}
+		if strings.TrimSpace(inp.PipelineID) == "" {
+			addError(fmt.Errorf("Pipeline notifications require the pipeline ID"))
+		}
+	}
+
+	if inp.FlushQueue && inp.QueueFilePath == "" {
+		addError(fmt.Errorf("Flushing the queue requires the queue file path"))
+	}
+
+	if inp.Dedupe && inp.DedupeKey == "" && inp.BuildSlug == "" {
+		addError(fmt.Errorf("Deduplication requires a dedupe key or the build slug"))
+	}
+
+	if inp.ScreenshotsDir != "" && inp.APIToken == "" {
+		addError(fmt.Errorf("Screenshots can only be uploaded with an API Token"))
+	}
+
+	if (inp.DeleteTs != "" || inp.DeleteTsOnSuccess != "" || inp.DeleteTsOnError != "") && inp.APIToken == "" {
+		addError(fmt.Errorf("Messages can only be deleted with an API Token"))
+	}
+
+	if inp.MetadataEventPayload != "" && inp.MetadataEventType == "" {
+		addError(fmt.Errorf("Metadata event type is required when a metadata event payload is provided"))
+	}
+	if _, err := parseMetadata(inp.MetadataEventType, inp.MetadataEventPayload); err != nil {
+		addError(err)
+	}
+	if _, err := parseMaskPatterns(inp.MaskPatterns); err != nil {
+		addError(err)
+	}
+
+	if inp.WorkflowVariables != "" && inp.WebhookURL == "" {
+		addError(fmt.Errorf("Workflow variables can only be sent to a Slack Workflow webhook, provide it as the Webhook URL"))
+	}
+
+	if inp.ApprovalStatusURL != "" && (inp.ApprovalApproveURL == "" || inp.ApprovalRejectURL == "") {
+		addError(fmt.Errorf("Both approve and reject URLs are required for the approval gate"))
+	}
+	if inp.ApprovalReactions {
+		if inp.APIToken == "" {
+			addError(fmt.Errorf("Reaction approval requires an API Token"))
+		}
+		if inp.ApprovalStatusURL != "" {
+			addError(fmt.Errorf("Use either the approval status URL or the reaction approval, not both"))
+		}
+	}
+	if (inp.ApprovalStatusURL != "" || inp.ApprovalReactions) && (inp.ApprovalTimeout <= 0 || inp.ApprovalPollInterval <= 0) {
+		addError(fmt.Errorf("Approval timeout and poll interval must be positive"))
+	}
+
+	if inp.PagerDutyRoutingKey != "" || inp.OpsgenieAPIKey != "" {
+		if inp.EscalationThreshold < 1 {
+			addError(fmt.Errorf("Escalation threshold must be at least 1"))
+		}
+		// Every build runs on a new machine, the failed builds can't be counted in the temporary directory.
+		if inp.EscalationThreshold > 1 && strings.TrimSpace(inp.StateDir) == "" {
+			addError(fmt.Errorf("Escalation requires the state directory, persisted between builds (eg. with the cache steps), to count the failed builds"))
+		}
+	}
+
+	if inp.GitHubMirror != "" && inp.GitHubMirror != githubMirrorOff {
+		if inp.GitHubToken == "" {
+			addError(fmt.Errorf("GitHub mirror requires a GitHub token"))
+		}
+		if githubRepository(inp.GitHubRepository, inp.RepositoryURL) == "" {
+			addError(fmt.Errorf("GitHub mirror requires the GitHub repository"))
+		}
+	}
+
+	if inp.MentionReviewers {
+		switch {
+		case inp.GitHubToken != "":
+			if githubRepository(inp.GitHubRepository, inp.RepositoryURL) == "" {
+				addError(fmt.Errorf("Mentioning the reviewers requires the GitHub repository"))
+			}
+		case inp.GitLabToken != "":
+			if gitlabProject(inp.GitLabProject, inp.RepositoryURL) == "" {
+				addError(fmt.Errorf("Mentioning the reviewers requires the GitLab project"))
+			}
+		default:
+			addError(fmt.Errorf("Mentioning the reviewers requires a GitHub or a GitLab token"))
+		}
+	}
+
+	if inp.BatchFilePath != "" {
+		if inp.BatchConcurrency < 1 {
+			addError(fmt.Errorf("Batch concurrency must be at least 1"))
+		}
+		if inp.BatchMaxFailedPercent < 0 || inp.BatchMaxFailedPercent > 100 {
+			addError(fmt.Errorf("Batch max failed percent must be between 0 and 100"))
+		}
+	}
+
+	blocksMode := strings.TrimSpace(inp.Layout) != "" || strings.TrimSpace(inp.Blocks) != ""
+	if !blocksMode {
+		// The color and the message are only used by the attachment.
+		for _, c := range []string{inp.Color, inp.ColorOnSuccess, inp.ColorOnError} {
+			if _, err := resolveColor(c, true); err != nil {
+				addError(err)
+			}
+		}
+		rawPayload := inp.PayloadJSON != "" || inp.PayloadFilePath != ""
+		if inp.WorkflowVariables == "" && inp.BatchFilePath == "" && !inp.FlushQueue && !rawPayload && !hasContent(inp) && inp.Attachments == "" {
+			addError(fmt.Errorf("The message is empty, provide the Text, Message, Title or Pretext input, or a Block Kit layout"))
+		}
+		if strings.TrimSpace(inp.HeaderText) != "" || strings.TrimSpace(inp.ContextItems) != "" || strings.TrimSpace(inp.ImageURLBlock) != "" {
+			log.Warnf("The header text, the context items and the image block are only shown with a Block Kit layout or custom blocks")
+		}
+	}
+
+	if isStructured(inp.Fields) {
+		if _, err := parseStructuredFields(inp.Fields); err != nil {
+			addError(err)
+		}
+	}
+
+	if layout := strings.TrimSpace(inp.Layout); layout != "" {
+		if _, err := layoutTemplate(layout); err != nil {
+			addError(err)
+		}
+	}
+
+	if script := strings.TrimSpace(inp.TransformScript); script != "" {
+		if _, err := os.Stat(script); err != nil {
+			addError(fmt.Errorf("Payload transform script not found: %s", err))
+		}
+	}
+
+	if dir := strings.TrimSpace(inp.PartialsDir); dir != "" {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			addError(fmt.Errorf("Partials directory not found: %s", dir))
+		}
+	}
+
+	if _, err := parseTimezone(inp.Timezone); err != nil {
+		addError(fmt.Errorf("Invalid timezone: %s", err))
 	}
 
-	if inp.APIToken != "" && inp.WebhookURL != "" {
-		log.Warnf("Both API Token and WebhookURL are provided. Using the API Token")
-		inp.WebhookURL = ""
+	if _, err := loadTranslations(inp.Language, inp.TranslationsFile); err != nil {
+		addError(fmt.Errorf("Invalid translations: %s", err))
+	}
 
+	if inp.Attachments != "" {
+		if _, err := parseAttachments(inp.Attachments, true); err != nil {
+			addError(err)
+		}
+	}
+
+	for _, part := range splitList(inp.MrkdwnIn) {
+		if part != "text" && part != "pretext" && part != "fields" {
+			addError(fmt.Errorf("Invalid mrkdwn_in part (%s), available parts: text, pretext, fields", part))
+		}
+	}
+
+	emojiMap, err := parseEmojiMap(inp.EmojiMap)
+	if err != nil {
+		addError(fmt.Errorf("Invalid emoji map: %s", err))
+	}
+	emojis := []string{
+		inp.IconEmoji, inp.IconEmojiOnSuccess, inp.IconEmojiOnError,
+		inp.Reaction, inp.ReactionOnSuccess, inp.ReactionOnError, inp.RemoveReaction,
+	}
+	for _, emoji := range emojiMap {
+		emojis = append(emojis, emoji)
+	}
+	for _, emoji := range emojis {
+		if strings.TrimSpace(emoji) != "" && !isKnownEmoji(emoji) {
+			log.Warnf("Unknown emoji %s, the default icon is shown unless it is a custom emoji of the workspace", normalizeEmoji(emoji))
+		}
+	}
+
+	if inp.TicketPattern != "" {
+		if _, err := regexp.Compile(inp.TicketPattern); err != nil {
+			addError(fmt.Errorf("Invalid ticket pattern: %s", err))
+		}
+	}
+
+	if inp.BitriseAPIToken != "" && (inp.BitriseAppSlug == "" || inp.BuildSlug == "") {
+		addError(fmt.Errorf("The app slug and the build slug are required to fetch the build details from the Bitrise API"))
+	}
+
+	if inp.DigestMode != digestModeOff && inp.DigestFilePath == "" {
+		addError(fmt.Errorf("Digest file path is required in %s digest mode", inp.DigestMode))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("Invalid inputs:\n- %s", strings.Join(errs, "\n- "))
 	}
 	return nil
 }
 
+// hasContent reports whether the attachment mode message has any text to show.
+func hasContent(inp *Input) bool {
+	for _, s := range []string{
+		inp.Text, inp.TextOnSuccess, inp.TextOnError,
+		inp.Message, inp.MessageOnSuccess, inp.MessageOnError, inp.MessageIOS, inp.MessageAndroid,
+		inp.Title, inp.TitleOnSuccess, inp.TitleOnError,
+		inp.PreText, inp.PreTextOnSuccess, inp.PreTextOnError,
+	} {
+		if strings.TrimSpace(s) != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func parseInputIntoConfig(inp *Input) config {
 	pipelineSuccess := inp.PipelineBuildStatus == "" ||
 		inp.PipelineBuildStatus == "succeeded" ||
 		inp.PipelineBuildStatus == "succeeded_with_abort"
-	success := pipelineSuccess && inp.BuildStatus == "0"
+	success := pipelineSuccess && isSuccessStatus(inp.BuildStatus, inp.SuccessValues)
+	success = overrideSuccess(inp.BuildStatusOverride, success)
+	aborted := inp.BuildStatusOverride == buildStatusAborted
+	platform := resolvePlatform(inp.Platform)
+	// The emoji map is already validated.
+	emojiMap, _ := parseEmojiMap(inp.EmojiMap)
 
-	// selectValue chooses the right value based on the result of the build.
-	var selectValue = func(ifSuccess, ifFailed string) string {
-		if success || ifFailed == "" {
+	// selectValue chooses the right value based on the result of the build,
+	// falling back to the default value if the status specific one is empty.
+	var selectValue = func(value, ifSuccess, ifFailed string) string {
+		if success && ifSuccess != "" {
 			return ifSuccess
 		}
-		return ifFailed
+		if !success && ifFailed != "" {
+			return ifFailed
+		}
+		return value
 	}
 
 	var config = config{
-		Debug:                      inp.Debug,
-		APIToken:                   inp.APIToken,
-		WebhookURL:                 selectValue(string(inp.WebhookURL), string(inp.WebhookURLOnError)),
-		Channel:                    selectValue(inp.Channel, inp.ChannelOnError),
-		Text:                       selectValue(inp.Text, inp.TextOnError),
-		IconEmoji:                  selectValue(inp.IconEmoji, inp.IconEmojiOnError),
-		IconURL:                    selectValue(inp.IconURL, inp.IconURLOnError),
-		Username:                   selectValue(inp.Username, inp.UsernameOnError),
-		ThreadTs:                   selectValue(inp.ThreadTs, inp.ThreadTsOnError),
-		ReplyBroadcast:             (success && inp.ReplyBroadcast) || (!success && inp.ReplyBroadcastOnError),
-		LinkNames:                  inp.LinkNames,
-		Color:                      selectValue(inp.Color, inp.ColorOnError),
-		PreText:                    selectValue(inp.PreText, inp.PreTextOnError),
-		Title:                      selectValue(inp.Title, inp.TitleOnError),
-		Message:                    selectValue(inp.Message, inp.MessageOnError),
-		ImageURL:                   selectValue(inp.ImageURL, inp.ImageURLOnError),
-		ThumbURL:                   selectValue(inp.ThumbURL, inp.ThumbURLOnError),
-		AuthorName:                 inp.AuthorName,
-		TitleLink:                  inp.TitleLink,
-		Footer:                     selectValue(inp.Footer, inp.FooterOnError),
-		FooterIcon:                 selectValue(inp.FooterIcon, inp.FooterIconOnError),
-		TimeStamp:                  inp.TimeStamp,
-		Fields:                     inp.Fields,
-		Buttons:                    inp.Buttons,
+		Debug:           inp.Debug,
+		Provider:        strings.TrimSpace(inp.Provider),
+		Providers:       splitList(inp.Provider),
+		APIToken:        inp.APIToken,
+		WebhookURL:      selectValue(string(inp.WebhookURL), string(inp.WebhookURLOnSuccess), string(inp.WebhookURLOnError)),
+		SigningSecret:   string(inp.SigningSecret),
+		SignatureHeader: strings.TrimSpace(inp.SignatureHeader),
+		Channel:         normalizeChannel(selectValue(inp.Channel, inp.ChannelOnSuccess, inp.ChannelOnError)),
+		Text:            selectValue(inp.Text, inp.TextOnSuccess, inp.TextOnError),
+		IconEmoji:       normalizeEmoji(selectValue(statusEmoji(emojiMap, inp.IconEmoji, success, aborted), inp.IconEmojiOnSuccess, inp.IconEmojiOnError)),
+		IconURL:         selectPlatformValue(platform, selectValue(inp.IconURL, inp.IconURLOnSuccess, inp.IconURLOnError), inp.IconURLIOS, inp.IconURLAndroid),
+		Username:        selectValue(inp.Username, inp.UsernameOnSuccess, inp.UsernameOnError),
+		ThreadTs:        selectValue(inp.ThreadTs, inp.ThreadTsOnSuccess, inp.ThreadTsOnError),
+		ReplyBroadcast:  (success && inp.ReplyBroadcast) || (!success && inp.ReplyBroadcastOnError),
+		EphemeralUser:   inp.EphemeralUser,
+		PinMessage:      inp.PinMessage,
+		Canvas:          strings.TrimSpace(inp.Canvas),
+		BookmarkTitle:   strings.TrimSpace(inp.BookmarkTitle),
+		BookmarkURL:     strings.TrimSpace(inp.BookmarkURL),
+		Reminder: reminderConfig{
+			Hours: inp.ReminderHours,
+			Text:  inp.ReminderText,
+			Key:   strings.TrimSpace(inp.ReminderKey),
+		},
+		ChannelTopic:      strings.TrimSpace(selectValue(inp.ChannelTopic, inp.ChannelTopicOnSuccess, inp.ChannelTopicOnError)),
+		JoinChannel:       inp.JoinChannel,
+		CreateChannel:     inp.CreateChannel,
+		ValidateChannel:   inp.ValidateChannel,
+		ReactionTs:        inp.ReactionTs,
+		Reaction:          selectValue(inp.Reaction, inp.ReactionOnSuccess, inp.ReactionOnError),
+		RemoveReaction:    inp.RemoveReaction,
+		DeleteTs:          selectValue(inp.DeleteTs, inp.DeleteTsOnSuccess, inp.DeleteTsOnError),
+		LinkNames:         inp.LinkNames,
+		Color:             selectColor(inp.Color, inp.ColorOnSuccess, inp.ColorOnError, success),
+		PreText:           selectValue(inp.PreText, inp.PreTextOnSuccess, inp.PreTextOnError),
+		Title:             selectValue(inp.Title, inp.TitleOnSuccess, inp.TitleOnError),
+		Message:           selectPlatformValue(platform, selectValue(inp.Message, inp.MessageOnSuccess, inp.MessageOnError), inp.MessageIOS, inp.MessageAndroid),
+		Fallback:          selectValue(inp.Fallback, inp.FallbackOnSuccess, inp.FallbackOnError),
+		MrkdwnIn:          splitList(inp.MrkdwnIn),
+		ImageURL:          selectValue(inp.ImageURL, inp.ImageURLOnSuccess, inp.ImageURLOnError),
+		ThumbURL:          selectValue(inp.ThumbURL, inp.ThumbURLOnSuccess, inp.ThumbURLOnError),
+		AuthorName:        inp.AuthorName,
+		TitleLink:         inp.TitleLink,
+		Footer:            selectValue(inp.Footer, inp.FooterOnSuccess, inp.FooterOnError),
+		FooterIcon:        selectValue(inp.FooterIcon, inp.FooterIconOnSuccess, inp.FooterIconOnError),
+		TimeStamp:         inp.TimeStamp,
+		Fields:            inp.Fields,
+		Buttons:           inp.Buttons,
+		WorkflowVariables: inp.WorkflowVariables,
+		TransformScript:   strings.TrimSpace(inp.TransformScript),
+		ScreenshotsDir:    strings.TrimSpace(inp.ScreenshotsDir),
+		ScreenshotsLimit:  inp.ScreenshotsLimit,
+		Layout:            strings.TrimSpace(inp.Layout),
+		Blocks:            strings.TrimSpace(inp.Blocks),
+		PartialsDir:       strings.TrimSpace(inp.PartialsDir),
+		DateFormat:        strings.TrimSpace(inp.DateFormat),
+		HeaderText:        inp.HeaderText,
+		BlockFields:       inp.BlockFields,
+		ImageURLBlock:     inp.ImageURLBlock,
+		ImageAltText:      inp.ImageAltText,
+		ContextItems:      inp.ContextItems,
+		Approval: approvalConfig{
+			StatusURL:    inp.ApprovalStatusURL,
+			ApproveURL:   inp.ApprovalApproveURL,
+			RejectURL:    inp.ApprovalRejectURL,
+			Reactions:    inp.ApprovalReactions,
+			Users:        splitList(inp.ApprovalUsers),
+			Timeout:      time.Duration(inp.ApprovalTimeout) * time.Minute,
+			PollInterval: time.Duration(inp.ApprovalPollInterval) * time.Second,
+		},
 		ThreadTsOutputVariableName: inp.ThreadTsOutputVariableName,
-		Ts:                         selectValue(inp.Ts, inp.TsOnError),
+		DeployDir:                  inp.DeployDir,
+		Phase:                      inp.Phase,
+		BuildSlug:                  inp.BuildSlug,
+		StateDir:                   inp.StateDir,
+		ThreadManager:              inp.ThreadManager,
+		Ts:                         selectValue(inp.Ts, inp.TsOnSuccess, inp.TsOnError),
+		FallbackWebhookURL:         string(inp.FallbackWebhookURL),
+		QueueFilePath:              inp.QueueFilePath,
+		FlushQueue:                 inp.FlushQueue,
+		Telegram: telegramConfig{
+			BotToken: string(inp.TelegramBotToken),
+			ChatID:   strings.TrimSpace(inp.TelegramChatID),
+		},
+		Webex: webexConfig{
+			BotToken: string(inp.WebexBotToken),
+			RoomID:   strings.TrimSpace(inp.WebexRoomID),
+		},
+		Matrix: matrixConfig{
+			HomeserverURL: strings.TrimSpace(inp.MatrixHomeserverURL),
+			AccessToken:   string(inp.MatrixAccessToken),
+			RoomID:        strings.TrimSpace(inp.MatrixRoomID),
+		},
+		Zulip: zulipConfig{
+			SiteURL:  strings.TrimSpace(inp.ZulipSiteURL),
+			BotEmail: strings.TrimSpace(inp.ZulipBotEmail),
+			APIKey:   string(inp.ZulipAPIKey),
+			Stream:   strings.TrimSpace(inp.ZulipStream),
+			Topic:    strings.TrimSpace(selectValue(inp.ZulipTopic, inp.ZulipTopicOnSuccess, inp.ZulipTopicOnError)),
+		},
+		SNS: snsConfig{
+			TopicARN: strings.TrimSpace(inp.SNSTopicARN),
+			Region:   strings.TrimSpace(inp.SNSRegion),
+		},
+		Ntfy: ntfyConfig{
+			TopicURL:    strings.TrimSpace(inp.NtfyTopicURL),
+			AccessToken: string(inp.NtfyAccessToken),
+		},
+		Pushover: pushoverConfig{
+			AppToken: string(inp.PushoverAppToken),
+			UserKey:  string(inp.PushoverUserKey),
+		},
+		Escalation: escalationConfig{
+			Threshold:           inp.EscalationThreshold,
+			Key:                 strings.TrimSpace(inp.EscalationKey),
+			PagerDutyRoutingKey: string(inp.PagerDutyRoutingKey),
+			OpsgenieAPIKey:      string(inp.OpsgenieAPIKey),
+			OpsgenieAPIURL:      strings.TrimSpace(inp.OpsgenieAPIURL),
+		},
+		GitHub: githubConfig{
+			Mirror:        inp.GitHubMirror,
+			Token:         string(inp.GitHubToken),
+			APIURL:        strings.TrimSpace(inp.GitHubAPIURL),
+			Repository:    githubRepository(inp.GitHubRepository, inp.RepositoryURL),
+			Commit:        strings.TrimSpace(inp.CommitHash),
+			PullRequest:   strings.TrimSpace(inp.GitHubPullRequest),
+			StatusContext: strings.TrimSpace(inp.GitHubStatusContext),
+		},
+		GitLab: gitlabConfig{
+			Token:        string(inp.GitLabToken),
+			APIURL:       strings.TrimSpace(inp.GitLabAPIURL),
+			Project:      gitlabProject(inp.GitLabProject, inp.RepositoryURL),
+			MergeRequest: strings.TrimSpace(inp.GitLabMergeRequest),
+		},
+		SMTP: smtpConfig{
+			Host:     inp.SMTPHost,
+			Port:     inp.SMTPPort,
+			Username: inp.SMTPUsername,
+			Password: string(inp.SMTPPassword),
+			From:     inp.SMTPFrom,
+			To:       inp.SMTPTo,
+		},
+		BatchFilePath: inp.BatchFilePath,
+		BatchInterval: inp.BatchInterval,
+
+		BatchConcurrency:      inp.BatchConcurrency,
+		BatchMaxFailedPercent: inp.BatchMaxFailedPercent,
+		DigestMode:            inp.DigestMode,
+		DigestFilePath:        inp.DigestFilePath,
+		DigestEntryName:       inp.DigestEntryName,
+		BuildURL:              inp.BuildURL,
+		PipelineMode:          inp.PipelineMode,
+		PipelineID:            strings.TrimSpace(inp.PipelineID),
+		Success:               success,
+		Aborted:               aborted,
+		EmojiMap:              emojiMap,
+		Platform:              platform,
+
+		LintViolationsLimit: inp.LintViolationsLimit,
+		Symbols:             newSymbolUpload(inp.SymbolsProvider, inp.SymbolsUUIDs, platform),
+		Store:               newStoreSubmission(inp.StoreTrack, inp.StorePhase, inp.StoreReviewStatus, inp.StoreConsoleURL, platform),
+	}
+	if inp.Phase == phaseStart {
+		// The build is still running, so neither the success nor the failure values apply.
+		config.PreText = inp.PreTextInProgress
+		config.Color, _ = resolveColor(inp.ColorInProgress, true)
+	}
+	// The timezone and the translations are already validated.
+	config.Location, _ = parseTimezone(inp.Timezone)
+	config.Translations, _ = loadTranslations(inp.Language, inp.TranslationsFile)
+	if strings.TrimSpace(inp.ReleaseReminderDate) != "" {
+		// The release reminder date and days are already validated.
+		config.ReleaseReminder.Date, _ = parseReleaseReminderDate(inp.ReleaseReminderDate)
+		config.ReleaseReminder.DaysBefore, _ = parseReleaseReminderDays(inp.ReleaseReminderDays)
+		config.ReleaseReminder.Text = inp.ReleaseReminderText
+		config.ReleaseReminder.User = strings.TrimSpace(inp.ReleaseReminderUser)
+	}
+	if inp.Dedupe {
+		config.DedupeKey = dedupeKey(inp.DedupeKey, inp.BuildSlug, success, aborted)
+	}
+	// The provider webhooks, the metadata and the mask patterns are already validated.
+	config.ProviderWebhookURLs, _ = parseProviderWebhooks(string(inp.ProviderWebhookURLs))
+	config.Metadata, _ = parseMetadata(inp.MetadataEventType, inp.MetadataEventPayload)
+	config.MaskPatterns, _ = parseMaskPatterns(inp.MaskPatterns)
+	if inp.Attachments != "" {
+		// The attachments are already validated.
+		config.Attachments, _ = parseAttachments(inp.Attachments, success)
+	}
+
+	if inp.ScheduleAt != "" {
+		// The schedule time is already validated.
+		if postAt, err := parseScheduleAt(inp.ScheduleAt, time.Now()); err == nil {
+			config.PostAt = postAt.Unix()
+		}
 	}
 	return config
 
 }
 
+// selectColor chooses and resolves the attachment color based on the result of the build.
+// The colors are already validated.
+func selectColor(color, colorOnSuccess, colorOnError string, success bool) string {
+	c := color
+	if success && strings.TrimSpace(colorOnSuccess) != "" {
+		c = colorOnSuccess
+	}
+	if !success && strings.TrimSpace(colorOnError) != "" {
+		c = colorOnError
+	}
+	resolved, _ := resolveColor(c, success)
+	return resolved
+}
+
+// send delivers the message in the mode selected by the config.
+func send(conf config, msg Message) error {
+	if conf.FlushQueue {
+		remaining, err := flushQueue(conf, deliver)
+		if err != nil {
+			return err
+		}
+		if remaining > 0 {
+			return fmt.Errorf("%d queued message(s) failed to send", remaining)
+		}
+		return nil
+	}
+
+	if conf.WorkflowVariables != "" {
+		return postWorkflowVariables(conf)
+	}
+
+	if len(conf.Payload) > 0 {
+		log.Infof("Sending the raw payload")
+		_, err := deliverPayload(conf, conf.Payload)
+		return err
+	}
+
+	if conf.ReactionTs != "" {
+		return updateReactions(conf)
+	}
+
+	switch conf.DigestMode {
+	case digestModeAppend:
+		log.Infof("Adding %s to the digest", conf.DigestEntryName)
+		return appendDigest(conf.DigestFilePath, digestEntry{
+			Name:     conf.DigestEntryName,
+			Success:  conf.Success,
+			BuildURL: conf.BuildURL,
+		})
+	case digestModeSend:
+		if _, err := deliver(conf, withDigest(msg, conf.Digest)); err != nil {
+			return err
+		}
+		if err := os.Remove(conf.DigestFilePath); err != nil {
+			log.Warnf("Failed to remove the digest file: %s", err)
+		}
+		return nil
+	}
+
+	switch conf.PipelineMode {
+	case pipelineModeRoot:
+		return sendPipelineRoot(conf, msg)
+	case pipelineModeStage:
+		return sendPipelineStage(conf, msg)
+	case pipelineModeRollup:
+		return sendPipelineRollup(conf, msg)
+	}
+
+	if conf.BatchFilePath != "" {
+		entries, err := readBatchFile(conf.BatchFilePath)
+		if err != nil {
+			return err
+		}
+		return sendBatch(conf, msg, entries, deliver)
+	}
+
+	// The queue is flushed before the config is changed for this run's message.
+	if conf.QueueFilePath != "" {
+		if _, err := flushQueue(conf, deliver); err != nil {
+			log.Warnf("Failed to flush the message queue: %s", err)
+		}
+	}
+
+	if conf.Approval.StatusURL != "" {
+		msg = withApprovalButtons(msg, conf.Approval)
+	}
+
+	if conf.DedupeKey != "" && alreadySent(conf) {
+		log.Warnf("A message with the dedupe key %s was already sent, skipping", conf.DedupeKey)
+		return nil
+	}
+
+	var thread *threadState
+	if conf.ThreadManager {
+		thread = loadThread(conf)
+		msg = withThread(msg, thread)
+	}
+
+	if conf.Phase == phaseEnd {
+		if start := loadStartMessage(conf); start != nil {
+			log.Infof("Updating the build's start message")
+			// The updated start message must not be deleted as the previous message.
+			conf.Ts = start.Ts
+			msg.Ts = start.Ts
+			msg.Channel = start.Channel
+		} else {
+			log.Warnf("No start message found for the build, sending a new message")
+		}
+	}
+
+	response, metrics, err := deliverWithMetrics(conf, msg)
+	if err != nil {
+		if conf.QueueFilePath == "" {
+			exportDeliveryMetrics(metrics)
+			return err
+		}
+		if qerr := enqueueMessage(conf.QueueFilePath, msg); qerr != nil {
+			log.Warnf("Failed to queue the message: %s", qerr)
+			exportDeliveryMetrics(metrics)
+			return err
+		}
+		log.Warnf("The message is queued to %s and will be retried on the next run", conf.QueueFilePath)
+		metrics.Status = deliveryStatusQueued
+		exportDeliveryMetrics(metrics)
+		return nil
+	}
+	exportDeliveryMetrics(metrics)
+
+	if conf.ThreadManager && thread == nil {
+		saveThread(conf, response)
+	}
+	if conf.Phase == phaseStart {
+		saveStartMessage(conf, response)
+	}
+	if conf.DedupeKey != "" {
+		markSent(conf, response)
+	}
+
+	exportPermalink(conf, response)
+
+	if response != nil {
+		threadTs := msg.ThreadTs
+		if threadTs == "" {
+			threadTs = response.Timestamp
+		}
+		if conf.ScreenshotsDir != "" {
+			if err := uploadScreenshots(conf, response.Channel, threadTs); err != nil {
+				log.Warnf("Failed to upload the screenshots: %s", err)
+			}
+		}
+		if len(conf.LintViolations) > 0 {
+			if err := postLintReply(conf, response.Channel, threadTs); err != nil {
+				log.Warnf("Failed to reply with the lint violations: %s", err)
+			}
+		}
+	}
+
+	if conf.PinMessage {
+		if err := pinMessage(conf, response); err != nil {
+			return err
+		}
+	}
+
+	if conf.Canvas != "" {
+		if err := appendToCanvas(conf, msg, response); err != nil {
+			log.Warnf("Failed to append the build to the canvas: %s", err)
+		}
+	}
+
+	if conf.ChannelTopic != "" {
+		setChannelTopic(conf, response)
+	}
+
+	if conf.Reminder.Hours > 0 {
+		updateReminder(conf, response)
+	}
+
+	// Only successful builds are bookmarked, so the bookmark points to the latest working build.
+	if conf.BookmarkTitle != "" && conf.Success {
+		if err := bookmarkBuild(conf, response); err != nil {
+			log.Warnf("Failed to bookmark the build: %s", err)
+		}
+	}
+
+	if conf.DeleteTs != "" {
+		deletePreviousMessage(conf, response)
+	}
+
+	switch {
+	case conf.Approval.StatusURL != "":
+		return exportDecision(waitForDecision(conf.Approval))
+	case conf.Approval.Reactions:
+		if response == nil || response.Timestamp == "" {
+			return fmt.Errorf("reactions can only be polled on a message sent with an API token")
+		}
+		return exportDecision(waitForReactionDecision(string(conf.APIToken), response, conf.Approval))
+	}
+	return nil
+}
+
+// configureLog sets the log level and format of the step's output.
+func configureLog(inp Input) {
+	level, err := log.ParseLevel(inp.LogLevel)
+	if err != nil {
+		log.Warnf("%s", err)
+	}
+	log.SetLevel(level)
+	log.SetEnableDebugLog(inp.Debug)
+	log.SetJSONFormat(inp.LogFormat == "json")
+}
+
 func main() {
+	if err := applyConfigFile(os.Getenv("config_file")); err != nil {
+		log.Errorf("Error: %s\n", err)
+		os.Exit(1)
+	}
+
 	var input Input
 	if err := stepconf.Parse(&input); err != nil {
 		log.Errorf("Error: %s\n", err)
 		os.Exit(1)
 	}
-	stepconf.Print(input)
-	log.SetEnableDebugLog(input.Debug)
+	configureLog(input)
+	if log.IsJSONFormat() {
+		log.Debugf("Inputs: %+v", input)
+	} else {
+		stepconf.Print(input)
+	}
+
+	if err := loadSecretFiles(&input); err != nil {
+		log.Errorf("Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := loadInputFiles(&input); err != nil {
+		log.Errorf("Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := applyEnvironmentWebhook(&input); err != nil {
+		log.Errorf("Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := applyTheme(&input); err != nil {
+		log.Errorf("Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	if input.MessageFromStdin {
+		if !stdinIsPipe() {
+			log.Errorf("Error: the message can only be read from the standard input if it is piped\n")
+			os.Exit(1)
+		}
+		if err := readStdinMessage(&input, os.Stdin); err != nil {
+			log.Errorf("Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
 
 	if err := validate(&input); err != nil {
 		log.Errorf("Error: %s\n", err)
 		os.Exit(1)
 	}
+	// The TLS settings are already validated.
+	tlsConfig, _ := parseTLSConfig(input.TLSMinVersion, input.TLSCipherSuites)
+	configureTLS(tlsConfig)
+
+	loadBuildStatus(&input)
+
+	var digest []digestEntry
+	if input.DigestMode == digestModeSend {
+		var err error
+		if digest, err = readDigest(input.DigestFilePath); err != nil {
+			log.Errorf("Error: %s\n", err)
+			os.Exit(1)
+		}
+		if !digestSucceeded(digest) {
+			// The digest reports a failure if any of the collected workflows failed.
+			input.BuildStatus = "1"
+		}
+	}
 
 	config := parseInputIntoConfig(&input)
+	config.Digest = digest
+
+	if input.PayloadJSON != "" || input.PayloadFilePath != "" {
+		payload, err := readPayload(input.PayloadJSON, input.PayloadFilePath)
+		if err == nil {
+			payload, err = maskPayload(payload, config.MaskPatterns)
+		}
+		if err != nil {
+			log.Errorf("Error: %s\n", err)
+			os.Exit(1)
+		}
+		config.Payload = payload
+	}
+
+	warnIgnoredWebhookOverrides(config)
+	if input.CheckIconURL {
+		checkIconURLs(config)
+	}
+
+	if config.APIToken != "" && config.Channel != "" && !input.DryRun {
+		channel, err := resolveChannel(config)
+		if err != nil {
+			log.Errorf("Error: %s\n", err)
+			printErrorHint(err)
+			os.Exit(1)
+		}
+		config.Channel = channel
+	}
+
+	if input.BitriseAPIToken != "" {
+		build, err := fetchBitriseBuild(string(input.BitriseAPIToken), input.BitriseAppSlug, input.BuildSlug)
+		if err != nil {
+			log.Warnf("Failed to fetch the build details from the Bitrise API: %s", err)
+		}
+		config.BitriseBuild = build
+	}
+
+	if pth := strings.TrimSpace(input.VersionFilePath); pth != "" {
+		version, err := readAppVersion(pth)
+		if err != nil {
+			log.Warnf("Failed to read the app version: %s", err)
+		}
+		config.AppVersion = version
+	}
+
+	if pth := strings.TrimSpace(input.ReleaseNotesPath); pth != "" {
+		notes, err := readReleaseNotes(pth, input.ReleaseNotesMaxLength)
+		if err != nil {
+			log.Warnf("Failed to read the release notes: %s", err)
+		}
+		config.ReleaseNotes = notes
+	}
 
 	msg := newMessage(config)
-	if err := postMessage(config, msg); err != nil {
+	tr := translate(config.Translations)
+	if input.BitriseBuildFields {
+		msg = withBitriseBuild(msg, config.BitriseBuild)
+	}
+	if input.ListArtifacts {
+		msg = withArtifacts(msg, listArtifacts(input), tr)
+	}
+	if input.TicketBaseURL != "" {
+		if tickets, err := findTickets(input.TicketPattern, input.TicketSources); err != nil {
+			log.Warnf("Failed to find the tickets: %s", err)
+		} else {
+			msg = withTickets(msg, input.TicketBaseURL, tickets)
+		}
+	}
+	if input.CompareLink {
+		msg = withCompareLink(msg, input)
+		if config.Success {
+			saveSuccessfulCommit(input)
+		}
+	}
+	if input.Coverage != "" || input.CoverageReportPath != "" {
+		if current, previous, err := loadCoverage(input); err != nil {
+			log.Warnf("Failed to read the coverage: %s", err)
+		} else {
+			msg = withCoverage(msg, coverageField(current, previous, tr))
+		}
+	}
+	if pth := strings.TrimSpace(input.DeviceTestResultsPath); pth != "" {
+		if results, err := readDeviceResults(pth); err != nil {
+			log.Warnf("Failed to read the device test results: %s", err)
+		} else {
+			msg = withDeviceResults(msg, results)
+		}
+	}
+	if pth := strings.TrimSpace(input.LintReportPath); pth != "" {
+		if violations, err := readLintReport(pth); err != nil {
+			log.Warnf("Failed to read the lint report: %s", err)
+		} else {
+			config.LintViolations = violations
+			msg = withLint(msg, violations)
+		}
+	}
+	if config.ReleaseNotes != "" && config.Layout == "" && config.Blocks == "" {
+		// The blocks show the release notes in their own section.
+		msg = withReleaseNotes(msg, config.ReleaseNotes, tr)
+	}
+	if input.ListFailedSteps && !config.Success {
+		steps, err := readFailedSteps(strings.TrimSpace(input.StepsSummaryPath))
+		if err != nil {
+			log.Warnf("Failed to list the failed steps: %s", err)
+		}
+		msg = withFailedSteps(msg, steps, tr)
+	}
+	if config.Layout != "" || config.Blocks != "" {
+		var err error
+		if msg, err = withBlocks(config, msg); err != nil {
+			log.Errorf("Error: %s\n", err)
+			os.Exit(1)
+		}
+	} else if input.ConvertToBlocks {
+		msg = withConvertedBlocks(msg)
+	}
+	if !config.Success {
+		var mentions []string
+		if handles := splitList(input.UserGroupsOnError); len(handles) > 0 {
+			if ids, err := findUserGroups(string(config.APIToken)); err != nil {
+				log.Warnf("Failed to list the user groups: %s", err)
+			} else {
+				mentions = append(mentions, userGroupMentions(handles, ids)...)
+			}
+		}
+		mentionMap := map[string]string{}
+		if pth := strings.TrimSpace(input.MentionMapPath); pth != "" {
+			// The mention map is already validated.
+			mentionMap, _ = readMentionMap(pth)
+		}
+		if emails := splitList(input.MentionEmailsOnError); len(emails) > 0 {
+			mentions = append(mentions, userMentions(emails, mentionMap, string(config.APIToken))...)
+		}
+		if input.MentionReviewers {
+			if reviewers, err := fetchReviewers(config); err != nil {
+				log.Warnf("Failed to fetch the reviewers of the pull request: %s", err)
+			} else {
+				mentions = append(mentions, reviewerMentions(reviewers, mentionMap)...)
+			}
+		}
+		msg = withMentions(msg, mentions)
+	}
+	msg = maskMessage(msg, config.MaskPatterns)
+
+	if input.DryRun {
+		if err := printPreview(config, msg); err != nil {
+			log.Errorf("Error: %s", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if config.Escalation.enabled() {
+		escalate(config, msg)
+	}
+
+	if err := send(config, msg); err != nil {
 		log.Errorf("Error: %s", err)
+		printErrorHint(err)
 		os.Exit(1)
 	}
 
+	if config.GitHub.mirrorsStatus() || config.GitHub.mirrorsComment() {
+		mirrorToGitHub(config, msg)
+	}
+
+	if !config.ReleaseReminder.Date.IsZero() {
+		if err := addReleaseReminders(config); err != nil {
+			log.Warnf("Failed to add the release reminders: %s", err)
+		}
+	}
+
 	log.Donef("\nSlack message successfully sent! 🚀\n")
 }
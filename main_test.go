@@ -0,0 +1,154 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func Test_validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		inp      Input
+		wantErrs []string
+	}{
+		{
+			name: "Valid attachment",
+			inp:  Input{WebhookURL: "https://hooks.slack.com/services/x", Message: "Hello", DigestMode: digestModeOff},
+		},
+		{
+			name: "Empty message with layout",
+			inp:  Input{WebhookURL: "https://hooks.slack.com/services/x", Layout: "compact", Color: "not-a-color", DigestMode: digestModeOff},
+		},
+		{
+			name:     "Errors are aggregated",
+			inp:      Input{Color: "not-a-color", DigestMode: digestModeOff},
+			wantErrs: []string{"Both API Token and WebhookURL are empty", "invalid color", "The message is empty"},
+		},
+		{
+			name: "Several providers",
+			inp:  Input{Provider: "slack-api, teams", APIToken: "xoxb-token", ProviderWebhookURLs: `{"teams": "https://example.webhook.office.com/x"}`, Message: "Hello", DigestMode: digestModeOff},
+		},
+		{
+			name:     "Several providers without a webhook",
+			inp:      Input{Provider: "slack-api,discord", APIToken: "xoxb-token", Message: "Hello", DigestMode: digestModeOff},
+			wantErrs: []string{"The discord provider requires a Webhook URL"},
+		},
+		{
+			name:     "Bookmark without API token",
+			inp:      Input{WebhookURL: "https://hooks.slack.com/services/x", Message: "Hello", BookmarkTitle: "Latest release", DigestMode: digestModeOff},
+			wantErrs: []string{"Bookmarks can only be added with an API Token", "Bookmark URL is required"},
+		},
+		{
+			name:     "Channel topic without API token",
+			inp:      Input{WebhookURL: "https://hooks.slack.com/services/x", Message: "Hello", ChannelTopicOnError: "main: ❌", DigestMode: digestModeOff},
+			wantErrs: []string{"The channel topic can only be set with an API Token"},
+		},
+		{
+			name:     "Reminder without API token",
+			inp:      Input{WebhookURL: "https://hooks.slack.com/services/x", Message: "Hello", ReminderHours: 4, DigestMode: digestModeOff},
+			wantErrs: []string{"Reminders can only be scheduled with an API Token", "Reminder text is required"},
+		},
+		{
+			name:     "Escalation without state dir",
+			inp:      Input{WebhookURL: "https://hooks.slack.com/services/x", Message: "Hello", PagerDutyRoutingKey: "key", EscalationThreshold: 3, DigestMode: digestModeOff},
+			wantErrs: []string{"Escalation requires the state directory"},
+		},
+		{
+			name: "Escalation on the first failure without state dir",
+			inp:  Input{WebhookURL: "https://hooks.slack.com/services/x", Message: "Hello", PagerDutyRoutingKey: "key", EscalationThreshold: 1, DigestMode: digestModeOff},
+		},
+		{
+			name:     "Invalid mrkdwn_in part",
+			inp:      Input{WebhookURL: "https://hooks.slack.com/services/x", Message: "Hello", MrkdwnIn: "text,title", DigestMode: digestModeOff},
+			wantErrs: []string{"Invalid mrkdwn_in part (title)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate(&tt.inp)
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Errorf("validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("validate() error = nil, want %v", tt.wantErrs)
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("validate() error = %v, want it to contain %v", err, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_parseInputIntoConfig_statusValues(t *testing.T) {
+	tests := []struct {
+		name        string
+		buildStatus string
+		want        [2]string
+	}{
+		{name: "Success override", buildStatus: "0", want: [2]string{"Deployed", "*Build Succeeded!*"}},
+		{name: "Failure falls back to the default", buildStatus: "1", want: [2]string{"Build finished", "*Build Failed!*"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inp := Input{
+				BuildStatus:      tt.buildStatus,
+				Message:          "Build finished",
+				MessageOnSuccess: "Deployed",
+				PreTextOnSuccess: "*Build Succeeded!*",
+				PreTextOnError:   "*Build Failed!*",
+			}
+			conf := parseInputIntoConfig(&inp)
+			if got := [2]string{conf.Message, conf.PreText}; got != tt.want {
+				t.Errorf("parseInputIntoConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_fallbackText(t *testing.T) {
+	os.Setenv("BITRISE_BUILD_NUMBER", "123")
+	defer os.Unsetenv("BITRISE_BUILD_NUMBER")
+
+	tests := []struct {
+		name string
+		conf config
+		want string
+	}{
+		{name: "Defaults to the message", conf: config{Message: "Fix login crash\\nand more"}, want: "Fix login crash\nand more"},
+		{name: "Fallback", conf: config{Message: "Fix login crash", Fallback: "Build #$BITRISE_BUILD_NUMBER failed"}, want: "Build #123 failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fallbackText(tt.conf); got != tt.want {
+				t.Errorf("fallbackText() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_payloadMethod(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{name: "New message", payload: `{"channel": "C1", "text": "hi"}`, want: "chat.postMessage"},
+		{name: "Update", payload: `{"channel": "C1", "ts": "1405894322.002768"}`, want: "chat.update"},
+		{name: "Ephemeral", payload: `{"channel": "C1", "user": "U1"}`, want: "chat.postEphemeral"},
+		{name: "Scheduled", payload: `{"channel": "C1", "post_at": 1714651380}`, want: "chat.scheduleMessage"},
+		{name: "Invalid payload", payload: `not json`, want: "chat.postMessage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := payloadMethod([]byte(tt.payload)); got != tt.want {
+				t.Errorf("payloadMethod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
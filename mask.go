@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maskReplacement replaces the parts of the message matching a mask pattern.
+const maskReplacement = "[REDACTED]"
+
+// parseMaskPatterns compiles the newline separated regular expressions.
+func parseMaskPatterns(s string) ([]*regexp.Regexp, error) {
+	var patterns []*regexp.Regexp
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		re, err := regexp.Compile(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mask pattern (%s): %s", line, err)
+		}
+		patterns = append(patterns, re)
+	}
+	return patterns, nil
+}
+
+// maskText replaces the matches of the patterns in s.
+func maskText(s string, patterns []*regexp.Regexp) string {
+	for _, re := range patterns {
+		s = re.ReplaceAllLiteralString(s, maskReplacement)
+	}
+	return s
+}
+
+// maskedKeys are the keys of the user-visible texts in blocks and raw payloads.
+// Channels, timestamps, emoji and URLs are kept, masking them would break the delivery.
+var maskedKeys = map[string]bool{
+	"text": true, "pretext": true, "title": true, "value": true,
+	"fallback": true, "footer": true, "author_name": true, "alt_text": true,
+}
+
+// maskMessage replaces the matches of the patterns in the user-visible texts of
+// the message, its attachments and its blocks, and in the metadata payload.
+func maskMessage(msg Message, patterns []*regexp.Regexp) Message {
+	if len(patterns) == 0 {
+		return msg
+	}
+	mask := func(s string) string { return maskText(s, patterns) }
+
+	msg.Text = mask(msg.Text)
+	msg.Blocks = maskBlocks(msg.Blocks, mask)
+	if msg.Attachments != nil {
+		attachments := make([]Attachment, len(msg.Attachments))
+		for i, a := range msg.Attachments {
+			attachments[i] = maskAttachment(a, mask)
+		}
+		msg.Attachments = attachments
+	}
+	if msg.Metadata != nil && len(msg.Metadata.EventPayload) > 0 {
+		var payload interface{}
+		if err := json.Unmarshal(msg.Metadata.EventPayload, &payload); err == nil {
+			if b, err := json.Marshal(maskJSON(payload, mask, true)); err == nil {
+				metadata := *msg.Metadata
+				metadata.EventPayload = b
+				msg.Metadata = &metadata
+			}
+		}
+	}
+	return msg
+}
+
+func maskAttachment(a Attachment, mask func(string) string) Attachment {
+	a.Fallback = mask(a.Fallback)
+	a.PreText = mask(a.PreText)
+	a.AuthorName = mask(a.AuthorName)
+	a.Title = mask(a.Title)
+	a.Text = mask(a.Text)
+	a.Footer = mask(a.Footer)
+	if a.Fields != nil {
+		fields := make([]Field, len(a.Fields))
+		for i, f := range a.Fields {
+			fields[i] = Field{Title: mask(f.Title), Value: mask(f.Value), Short: f.Short}
+		}
+		a.Fields = fields
+	}
+	if a.Buttons != nil {
+		buttons := make([]Button, len(a.Buttons))
+		for i, b := range a.Buttons {
+			b.Text = mask(b.Text)
+			buttons[i] = b
+		}
+		a.Buttons = buttons
+	}
+	a.Blocks = maskBlocks(a.Blocks, mask)
+	return a
+}
+
+func maskBlocks(blocks []Block, mask func(string) string) []Block {
+	if blocks == nil {
+		return nil
+	}
+	masked := make([]Block, len(blocks))
+	for i, b := range blocks {
+		m, _ := maskJSON(map[string]interface{}(b), mask, false).(map[string]interface{})
+		masked[i] = Block(m)
+	}
+	return masked
+}
+
+// maskPayload replaces the matches of the patterns in the user-visible texts of
+// the JSON payload and in its metadata payload.
+func maskPayload(b []byte, patterns []*regexp.Regexp) ([]byte, error) {
+	if len(patterns) == 0 {
+		return b, nil
+	}
+	var payload interface{}
+	if err := json.Unmarshal(b, &payload); err != nil {
+		return nil, fmt.Errorf("invalid payload: %s", err)
+	}
+	return json.Marshal(maskJSON(payload, func(s string) string { return maskText(s, patterns) }, false))
+}
+
+// maskJSON returns a copy of the decoded JSON value v with mask applied to the
+// strings of the masked keys, or to every string if all is set, as in the
+// metadata payload.
+func maskJSON(v interface{}, mask func(string) string, all bool) interface{} {
+	switch v := v.(type) {
+	case string:
+		if all {
+			return mask(v)
+		}
+		return v
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, e := range v {
+			if s, ok := e.(string); ok && !all {
+				if maskedKeys[key] {
+					s = mask(s)
+				}
+				m[key] = s
+				continue
+			}
+			m[key] = maskJSON(e, mask, all || key == "event_payload")
+		}
+		return m
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, e := range v {
+			items[i] = maskJSON(e, mask, all)
+		}
+		return items
+	}
+	return v
+}
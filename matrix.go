@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+func init() {
+	registerProvider("matrix", matrixProvider{})
+}
+
+// matrixProvider sends the message as an m.room.message event to a Matrix room.
+type matrixProvider struct{}
+
+// matrixConfig is the homeserver, the user and the room of the matrix provider.
+type matrixConfig struct {
+	HomeserverURL string
+	AccessToken   string
+	RoomID        string
+}
+
+type matrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+// newMatrixMessage converts the message into a notice with a plain text body
+// and an HTML formatted body.
+func newMatrixMessage(msg Message) matrixMessage {
+	return matrixMessage{
+		MsgType:       "m.notice",
+		Body:          msg.PlainText(),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: htmlLineBreaks(messageHTML(msg)),
+	}
+}
+
+// htmlLineBreaks converts the newlines outside of the preformatted blocks to <br> tags.
+func htmlLineBreaks(s string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "<pre>")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start:], "</pre>")
+		if end < 0 {
+			break
+		}
+		end += start + len("</pre>")
+		b.WriteString(strings.Replace(s[:start], "\n", "<br>", -1))
+		b.WriteString(s[start:end])
+		s = s[end:]
+	}
+	b.WriteString(strings.Replace(s, "\n", "<br>", -1))
+	return b.String()
+}
+
+// matrixEventURL returns the URL of a new m.room.message event in the room.
+// The transaction ID makes the retries of the same request idempotent.
+func matrixEventURL(homeserverURL, roomID, txnID string) string {
+	return strings.TrimSuffix(strings.TrimSpace(homeserverURL), "/") +
+		"/_matrix/client/v3/rooms/" + url.PathEscape(roomID) + "/send/m.room.message/" + url.PathEscape(txnID)
+}
+
+// Send implements Provider.
+func (matrixProvider) Send(conf config, msg Message) (*SendMessageResponse, error) {
+	b, err := json.Marshal(newMatrixMessage(msg))
+	if err != nil {
+		return nil, err
+	}
+	saveRenderedPayload(conf, b)
+	log.Debugf("Request to Matrix: %s\n", b)
+
+	txnID := fmt.Sprintf("bitrise-%d", time.Now().UnixNano())
+	req, err := http.NewRequest("PUT", matrixEventURL(conf.Matrix.HomeserverURL, conf.Matrix.RoomID, txnID), bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Authorization", "Bearer "+conf.Matrix.AccessToken)
+
+	body, err := sendProviderRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Response from Matrix: %s\n", body)
+	return nil, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// readMentionMap reads the YAML mapping of git emails or usernames to Slack member IDs, eg.
+//
+//	jane@example.com: U012AB3CD
+//	jane-doe: U012AB3CD
+//
+// The keys are returned in lower case.
+func readMentionMap(pth string) (map[string]string, error) {
+	b, err := os.ReadFile(pth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mention map: %s", err)
+	}
+	v, err := parseYAML(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("invalid mention map: %s", err)
+	}
+	if v == nil {
+		return map[string]string{}, nil
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid mention map: must be a mapping of emails or usernames to Slack member IDs")
+	}
+
+	ids := map[string]string{}
+	for email, id := range m {
+		s, ok := id.(string)
+		if !ok || strings.TrimSpace(s) == "" {
+			return nil, fmt.Errorf("invalid mention map: the member ID of %s must be a string", email)
+		}
+		ids[strings.ToLower(strings.TrimSpace(email))] = strings.TrimSpace(s)
+	}
+	return ids, nil
+}
+
+// userMentions returns the mentions of the users with the given emails, eg. <@U012AB3CD>.
+// The emails are looked up in the mention map first, then with the API token if
+// it is set. Emails not matching a user are left as plain text, duplicates are dropped.
+func userMentions(emails []string, mentionMap map[string]string, token string) []string {
+	var mentions []string
+	seen := map[string]bool{}
+	for _, email := range emails {
+		key := strings.ToLower(email)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		id, ok := mentionMap[key]
+		if !ok && token != "" {
+			var err error
+			if id, err = lookupUserByEmail(token, email); err != nil {
+				log.Warnf("Failed to find the user %s: %s", email, err)
+			}
+		} else if !ok {
+			log.Warnf("%s is not in the mention map", email)
+		}
+		if id == "" {
+			mentions = append(mentions, email)
+			continue
+		}
+		mentions = append(mentions, "<@"+id+">")
+	}
+	return mentions
+}
+
+// reviewerMentions returns the mentions of the reviewers with the given GitHub or
+// GitLab usernames. Usernames can't be looked up with the API token, so they are
+// only resolved with the mention map. Reviewers not in the mention map are left
+// as plain text, duplicates are dropped.
+func reviewerMentions(usernames []string, mentionMap map[string]string) []string {
+	var mentions []string
+	seen := map[string]bool{}
+	for _, username := range usernames {
+		key := strings.ToLower(username)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		id, ok := mentionMap[key]
+		if !ok {
+			log.Warnf("The reviewer %s is not in the mention map", username)
+			mentions = append(mentions, username)
+			continue
+		}
+		mentions = append(mentions, "<@"+id+">")
+	}
+	return mentions
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_readMentionMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "Mapping",
+			content: "# Slack members\nJane@Example.com: U012AB3CD\n\"12345+john@users.noreply.github.com\": U045EF6GH\n",
+			want:    map[string]string{"jane@example.com": "U012AB3CD", "12345+john@users.noreply.github.com": "U045EF6GH"},
+		},
+		{name: "Empty", content: "", want: map[string]string{}},
+		{name: "Not a mapping", content: "- jane@example.com\n", wantErr: true},
+		{name: "Nested member ID", content: "jane@example.com:\n  id: U012AB3CD\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pth := filepath.Join(t.TempDir(), "mentions.yml")
+			if err := os.WriteFile(pth, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readMentionMap(pth)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readMentionMap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readMentionMap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_userMentions(t *testing.T) {
+	mentionMap := map[string]string{"jane@example.com": "U012AB3CD"}
+	want := []string{"<@U012AB3CD>", "john@example.com"}
+	if got := userMentions([]string{"Jane@example.com", "john@example.com", "jane@example.com"}, mentionMap, ""); !reflect.DeepEqual(got, want) {
+		t.Errorf("userMentions() = %v, want %v", got, want)
+	}
+}
+
+func Test_reviewerMentions(t *testing.T) {
+	mentionMap := map[string]string{"jane@example.com": "U012AB3CD", "john-doe": "U045EF6GH"}
+	want := []string{"<@U045EF6GH>", "jane-doe"}
+	if got := reviewerMentions([]string{"John-Doe", "jane-doe", "john-doe"}, mentionMap); !reflect.DeepEqual(got, want) {
+		t.Errorf("reviewerMentions() = %v, want %v", got, want)
+	}
+}
@@ -2,165 +2,119 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
+	"os"
 	"strings"
-)
-
-// Message to post to a slack channel.
-// See also: https://api.slack.com/methods/chat.postMessage
-type Message struct {
-	// Channel to send message to.
-	//
-	// Can be an encoded ID (eg. C024BE91L), or the channel's name (eg. #general).
-	Channel string `json:"channel"`
-
-	// Text of the message to send. Required, unless providing only attachments instead.
-	Text string `json:"text,omitempty"`
-
-	// Attachments is a list of structured attachments.
-	Attachments []Attachment `json:"attachments,omitempty"`
-
-	// IconEmoji is the emoji to use as the icon for the message. Overrides IconUrl.
-	IconEmoji string `json:"icon_emoji,omitempty"`
-
-	// IconURL is the URL to an image to use as the icon for the message.
-	IconURL string `json:"icon_url,omitempty"`
-
-	// LinkNames linkifies channel names and usernames.
-	LinkNames bool `json:"link_names,omitempty"`
 
-	// Username specifies the bot's username for the message.
-	Username string `json:"username,omitempty"`
-
-	// Provide another message's ts value to make this message a reply.
-	ThreadTs string `json:"thread_ts,omitempty"`
-
-	// Provide another message's ts value to make message to update
-	Ts string `json:"ts,omitempty"`
-
-	// Used in conjunction with thread_ts and indicates whether reply should be made visible to everyone in the channel or conversation.
-	ReplyBroadcast bool `json:"reply_broadcast,omitempty"`
-}
-
-// Attachment adds more context to a slack chat message.
-// See also: https://api.slack.com/docs/message-attachments
-type Attachment struct {
-	// Fallback is the plain-text summary of the attachment.
-	//
-	// This text will be used in clients that don't show formatted text (eg. IRC, mobile notifications)
-	// and should not contain any markup.
-	Fallback string `json:"fallback"`
-
-	// Color is used to color the border along the left side of the attachment.
-	//
-	// Can either be one of good, warning, danger, or any hex color code (eg. #439FE0).
-	Color string `json:"color"`
-
-	// PreText is an optional text that appears above the attachment block.
-	PreText string `json:"pretext,omitempty"`
-
-	// AuthorName is a small text used to display the author's name.
-	AuthorName string `json:"author_name,omitempty"`
-
-	// Title is displayed as larger, bold text near the top of a attachment.
-	Title string `json:"title,omitempty"`
-
-	// TitleLink is a URL that will hyperlink the Title.
-	TitleLink string `json:"title_link,omitempty"`
-
-	// Text is the main text of the attachment, and can contain standard message markup.
-	//
-	// The content will automatically collapse if it contains 700+ characters or 5+ linebreaks,
-	// and will display a "Show more..." link to expand the content.
-	Text string `json:"text,omitempty"`
-
-	// Fields is a list of fields to be displayed in a table inside the attachment.
-	Fields []Field `json:"fields,omitempty"`
-
-	// ImageURL is a URL to an image file that will be displayed inside the attachment.
-	//
-	// Supported formats: GIF, JPEG, PNG, and BMP.
-	// Large images will be resized to a maximum width of 400px or a maximum height of 500px.
-	ImageURL string `json:"image_url,omitempty"`
-
-	// ThumbURL is a URL to an image file that will be displayed as a
-	// thumbnail on the right side of a attachment.
-	//
-	// Supported formats: GIF, JPEG, PNG, and BMP.
-	// The thumbnail's longest dimension will be scaled down to 75px.
-	ThumbURL string `json:"thumb_url,omitempty"`
-
-	// Footer adds some brief text to help contextualize and identify an attachment.
-	//
-	// Limited to 300 characters.
-	Footer string `json:"footer,omitempty"`
-
-	// FooterIcon renders a small icon beside the footer text.
-	//
-	// It will be scaled down to 16px by 16px.
-	FooterIcon string `json:"footer_icon,omitempty"`
-
-	// TimeStamp is an integer value in epoch time to display and additional
-	// timestamp value as part of the attachment's footer.
-	TimeStamp int `json:"ts,omitempty"`
-
-	// Buttons is a list of buttons attached to the message as link buttons.
-	//
-	// An attachment may contain 1 to 5 buttons.
-	Buttons []Button `json:"actions,omitempty"`
-}
+	"github.com/bitrise-io/go-utils/parseutil"
+	"github.com/bitrise-steplib/steps-slack-message/pkg/slackmsg"
+)
 
-// Field will be displayed in a table inside the attachment.
-type Field struct {
-	// Title is shown as a bold heading above the value text.
-	Title string
+// Aliases of the message model, see the slackmsg package for the details.
+type (
+	Message    = slackmsg.Message
+	Metadata   = slackmsg.Metadata
+	Block      = slackmsg.Block
+	Attachment = slackmsg.Attachment
+	Field      = slackmsg.Field
+	Button     = slackmsg.Button
+)
 
-	// Value is the text value of the field.
-	Value string
+// parseMetadata builds the message metadata from the event type and the JSON payload.
+func parseMetadata(eventType, payload string) (*Metadata, error) {
+	eventType = strings.TrimSpace(eventType)
+	if eventType == "" {
+		return nil, nil
+	}
 
-	// Short is an optional flag indicating whether the value is short enough
-	// to be displayed side-by-side with other values.
-	Short bool
-}
+	payload = strings.TrimSpace(payload)
+	if payload == "" {
+		payload = "{}"
+	}
 
-// MarshalJSON implements json.Marshaler.MarshalJSON.
-func (f Field) MarshalJSON() ([]byte, error) {
-	m := make(map[string]interface{})
-	m["title"] = f.Title
-	m["value"] = f.Value
-	m["short"] = len(f.Value) < 40
-	return json.Marshal(m)
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(payload), &obj); err != nil {
+		return nil, fmt.Errorf("metadata event payload is not a JSON object: %s", err)
+	}
+	return &Metadata{EventType: eventType, EventPayload: json.RawMessage(payload)}, nil
 }
 
+// parseFields parses the fields input, either the pipe separated lines or
+// a structured YAML/JSON list. Structured fields are validated up front by
+// parseStructuredFields, invalid ones are dropped here.
 func parseFields(s string) (fs []Field) {
+	if isStructured(s) {
+		fs, _ = parseStructuredFields(s)
+		return
+	}
+
 	for _, p := range pairs(s) {
 		fs = append(fs, Field{Title: p[0], Value: ensureNewlines(p[1])})
 	}
 	return
 }
 
-// Button is just a link that looks like a button.
-type Button struct {
-	// Type is set to button to tell slack to render a button.
-	Type string
+// isStructured reports whether the input is a YAML/JSON document rather than pipe separated lines.
+func isStructured(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "[") || strings.HasPrefix(s, "{") || isSequenceItem(strings.SplitN(s, "\n", 2)[0])
+}
 
-	// Text is the label for the button.
-	Text string
+// decodeStructured decodes a JSON or YAML document into generic values.
+func decodeStructured(s string) (interface{}, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") || strings.HasPrefix(s, "{") {
+		var v interface{}
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %s", err)
+		}
+		return v, nil
+	}
+	return parseYAML(s)
+}
 
-	// URL is the fully qualified http or https url to deliver users to.
-	URL string
+// parseStructuredFields parses a YAML/JSON list of fields with title, value and
+// optional short keys. Environment variables are expanded in the titles and values.
+func parseStructuredFields(s string) ([]Field, error) {
+	v, err := decodeStructured(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fields: %s", err)
+	}
 
-	// Style is set to default so the buttons will use the UI's default text color.
-	Style string
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid fields: expected a list of fields")
+	}
+	return structuredFields(items)
 }
 
-// MarshalJSON implements json.Marshaler.MarshalJSON.
-func (b Button) MarshalJSON() ([]byte, error) {
-	m := make(map[string]string)
-	m["type"] = "button"
-	m["text"] = b.Text
-	m["url"] = b.URL
-	m["style"] = "default"
-	return json.Marshal(m)
+// structuredFields converts the decoded list of fields.
+func structuredFields(items []interface{}) ([]Field, error) {
+	var fs []Field
+	for i, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid field #%d: expected title and value keys", i+1)
+		}
+
+		f := Field{
+			Title: os.ExpandEnv(parseutil.CastToString(m["title"])),
+			Value: os.ExpandEnv(parseutil.CastToString(m["value"])),
+		}
+		if f.Title == "" {
+			return nil, fmt.Errorf("invalid field #%d: title is required", i+1)
+		}
+
+		if short, ok := m["short"]; ok {
+			b, err := parseutil.ParseBool(parseutil.CastToString(short))
+			if err != nil {
+				return nil, fmt.Errorf("invalid field #%d: short is not a boolean", i+1)
+			}
+			f.Short = &b
+		}
+		fs = append(fs, f)
+	}
+	return fs, nil
 }
 
 func parseButtons(s string) (bs []Button) {
@@ -182,3 +136,80 @@ func pairs(s string) [][2]string {
 	}
 	return ps
 }
+
+// parseAttachments parses a YAML/JSON list of additional attachments. The keys
+// match the attachment inputs of the step (color, pretext, title, text, fields,
+// buttons...), environment variables are expanded in the values.
+func parseAttachments(s string, success bool) ([]Attachment, error) {
+	v, err := decodeStructured(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attachments: %s", err)
+	}
+
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid attachments: expected a list of attachments")
+	}
+
+	var as []Attachment
+	for i, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid attachment #%d: expected a mapping", i+1)
+		}
+		value := func(key string) string {
+			if m[key] == nil {
+				return ""
+			}
+			return os.ExpandEnv(parseutil.CastToString(m[key]))
+		}
+
+		a := Attachment{
+			Fallback:   value("fallback"),
+			PreText:    value("pretext"),
+			AuthorName: value("author_name"),
+			Title:      value("title"),
+			TitleLink:  value("title_link"),
+			Text:       value("text"),
+			ImageURL:   value("image_url"),
+			ThumbURL:   value("thumb_url"),
+			Footer:     value("footer"),
+			FooterIcon: value("footer_icon"),
+		}
+		if a.Fallback == "" {
+			a.Fallback = a.Text
+		}
+		if a.Color, err = resolveColor(value("color"), success); err != nil {
+			return nil, fmt.Errorf("invalid attachment #%d: %s", i+1, err)
+		}
+
+		if fields, ok := m["fields"]; ok {
+			list, ok := fields.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid attachment #%d: expected a list of fields", i+1)
+			}
+			if a.Fields, err = structuredFields(list); err != nil {
+				return nil, fmt.Errorf("invalid attachment #%d: %s", i+1, err)
+			}
+		}
+
+		if buttons, ok := m["buttons"]; ok {
+			list, ok := buttons.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("invalid attachment #%d: expected a list of buttons", i+1)
+			}
+			for j, b := range list {
+				bm, ok := b.(map[string]interface{})
+				if !ok || bm["text"] == nil || bm["url"] == nil {
+					return nil, fmt.Errorf("invalid attachment #%d: button #%d requires text and url keys", i+1, j+1)
+				}
+				a.Buttons = append(a.Buttons, Button{
+					Text: os.ExpandEnv(parseutil.CastToString(bm["text"])),
+					URL:  os.ExpandEnv(parseutil.CastToString(bm["url"])),
+				})
+			}
+		}
+		as = append(as, a)
+	}
+	return as, nil
+}
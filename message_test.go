@@ -5,6 +5,10 @@ import (
 	"testing"
 )
 
+func boolPtr(b bool) *bool {
+	return &b
+}
+
 func Test_parseFields(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -16,7 +20,18 @@ func Test_parseFields(t *testing.T) {
 			s:      "Release notes|line1\\nline2",
 			wantFs: []Field{{Title: "Release notes", Value: "line1\nline2"}},
 		},
+		{
+			name:   "YAML fields",
+			s:      "- title: App\n  value: ${TEST_APP_TITLE}\n  short: no\n- title: Branch\n  value: main",
+			wantFs: []Field{{Title: "App", Value: "My App", Short: boolPtr(false)}, {Title: "Branch", Value: "main"}},
+		},
+		{
+			name:   "JSON fields",
+			s:      `[{"title": "App", "value": "My App", "short": true}]`,
+			wantFs: []Field{{Title: "App", Value: "My App", Short: boolPtr(true)}},
+		},
 	}
+	t.Setenv("TEST_APP_TITLE", "My App")
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if gotFs := parseFields(tt.s); !reflect.DeepEqual(gotFs, tt.wantFs) {
@@ -25,3 +40,81 @@ func Test_parseFields(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseAttachments(t *testing.T) {
+	t.Setenv("TEST_INSTALL_PAGE_URL", "https://app.bitrise.io/install/1")
+
+	tests := []struct {
+		name    string
+		s       string
+		want    []Attachment
+		wantErr bool
+	}{
+		{
+			name: "YAML",
+			s: `- color: good
+  title: Tests
+  text: 128 passed
+- title: Artifacts
+  fields:
+  - title: IPA
+    value: $TEST_INSTALL_PAGE_URL
+    short: yes
+  buttons:
+  - text: Install
+    url: $TEST_INSTALL_PAGE_URL`,
+			want: []Attachment{
+				{Fallback: "128 passed", Color: "good", Title: "Tests", Text: "128 passed"},
+				{
+					Color:   "#f0741f",
+					Title:   "Artifacts",
+					Fields:  []Field{{Title: "IPA", Value: "https://app.bitrise.io/install/1", Short: boolPtr(true)}},
+					Buttons: []Button{{Text: "Install", URL: "https://app.bitrise.io/install/1"}},
+				},
+			},
+		},
+		{
+			name: "JSON",
+			s:    `[{"title": "Coverage", "text": "81%", "color": "#439FE0"}]`,
+			want: []Attachment{{Fallback: "81%", Color: "#439FE0", Title: "Coverage", Text: "81%"}},
+		},
+		{name: "Not a list", s: `{"title": "Tests"}`, wantErr: true},
+		{name: "Invalid color", s: `[{"title": "Tests", "color": "pink"}]`, wantErr: true},
+		{name: "Button without URL", s: `[{"buttons": [{"text": "Install"}]}]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAttachments(tt.s, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAttachments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAttachments() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseInputIntoConfig_successWithBaseInputs(t *testing.T) {
+	// The status specific inputs of a successful build are empty by default,
+	// so the base inputs set in the bitrise.yml apply.
+	inp := Input{
+		BuildStatus:     "0",
+		Color:           "#439fe0",
+		ColorOnError:    "#f0741f",
+		PreText:         "*Deployed!*",
+		PreTextOnError:  "*Build Failed!*",
+		Reaction:        "rocket",
+		ReactionOnError: "x",
+	}
+	conf := parseInputIntoConfig(&inp)
+	if got, want := conf.Color, "#439fe0"; got != want {
+		t.Errorf("parseInputIntoConfig() Color = %v, want %v", got, want)
+	}
+	if got, want := conf.PreText, "*Deployed!*"; got != want {
+		t.Errorf("parseInputIntoConfig() PreText = %v, want %v", got, want)
+	}
+	if got, want := conf.Reaction, "rocket"; got != want {
+		t.Errorf("parseInputIntoConfig() Reaction = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+const (
+	sendDurationOutputKey = "SLACK_SEND_DURATION_MS"
+	sendAttemptsOutputKey = "SLACK_SEND_ATTEMPTS"
+	sendStatusOutputKey   = "SLACK_SEND_STATUS"
+)
+
+// The outcomes of a delivery, exported as SLACK_SEND_STATUS.
+const (
+	deliveryStatusSent     = "sent"
+	deliveryStatusFallback = "fallback"
+	deliveryStatusQueued   = "queued"
+	deliveryStatusFailed   = "failed"
+)
+
+// deliveryMetrics describes how the message was delivered.
+type deliveryMetrics struct {
+	Duration time.Duration
+	Attempts int
+	Status   string
+}
+
+// exportDeliveryMetrics exports the delivery metrics as step outputs.
+func exportDeliveryMetrics(metrics deliveryMetrics) {
+	outputs := []struct {
+		key, value string
+	}{
+		{sendDurationOutputKey, strconv.FormatInt(int64(metrics.Duration/time.Millisecond), 10)},
+		{sendAttemptsOutputKey, strconv.Itoa(metrics.Attempts)},
+		{sendStatusOutputKey, metrics.Status},
+	}
+	for _, o := range outputs {
+		log.Debugf("Exporting output: %s=%s\n", o.key, o.value)
+		if err := exportEnvVariable(o.key, o.value); err != nil {
+			log.Warnf("Failed to export %s: %s", o.key, err)
+		}
+	}
+}
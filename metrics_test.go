@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_deliverWithMetrics(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer failing.Close()
+
+	tests := []struct {
+		name         string
+		conf         config
+		wantAttempts int
+		wantStatus   string
+		wantErr      bool
+	}{
+		{name: "Sent", conf: config{WebhookURL: ok.URL}, wantAttempts: 1, wantStatus: deliveryStatusSent},
+		{name: "Fallback webhook", conf: config{WebhookURL: failing.URL, FallbackWebhookURL: ok.URL}, wantAttempts: 2, wantStatus: deliveryStatusFallback},
+		{name: "Failed", conf: config{WebhookURL: failing.URL, FallbackWebhookURL: failing.URL}, wantAttempts: 2, wantStatus: deliveryStatusFailed, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, metrics, err := deliverWithMetrics(tt.conf, Message{Text: "Build succeeded"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("deliverWithMetrics() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if metrics.Attempts != tt.wantAttempts || metrics.Status != tt.wantStatus {
+				t.Errorf("deliverWithMetrics() = %d attempts, %s, want %d attempts, %s", metrics.Attempts, metrics.Status, tt.wantAttempts, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -1,23 +1,25 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"os/exec"
 	"strings"
+	"sync"
 
-	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
 )
 
 // SendMessageResponse is the response from Slack POST
 type SendMessageResponse struct {
 	/// The Thread Timestamp
 	Timestamp string `json:"ts"`
+
+	/// The ID of the channel the message was sent to
+	Channel string `json:"channel"`
 }
 
 /// Export the output variables after a successful response
-func exportOutputs(conf *config, resp *http.Response) error {
+func exportOutputs(conf *config, response *SendMessageResponse) error {
 
 	if !isRequestingOutput(conf) {
 		log.Debugf("Not requesting any outputs")
@@ -31,11 +33,9 @@ func exportOutputs(conf *config, resp *http.Response) error {
 		return fmt.Errorf("For output support, do not submit a WebHook URL")
 	}
 
-	var response SendMessageResponse
-	parseError := json.NewDecoder(resp.Body).Decode(&response)
-	if parseError != nil {
+	if response == nil {
 		// here we want to fail, because the user is expecting an output
-		return fmt.Errorf("Failed to parse response: %s", parseError)
+		return fmt.Errorf("No response to export the outputs from")
 	}
 
 	if string(conf.ThreadTsOutputVariableName) != "" {
@@ -55,8 +55,14 @@ func isRequestingOutput(conf *config) bool {
 	return string(conf.ThreadTsOutputVariableName) != ""
 }
 
+// envmanMu serializes the envman calls of the batch workers, which share the env store.
+var envmanMu sync.Mutex
+
 /// Exports env using envman
 func exportEnvVariable(variable string, value string) error {
+	envmanMu.Lock()
+	defer envmanMu.Unlock()
+
 	c := exec.Command("envman", "add", "--key", variable, "--value", value)
 	err := c.Run()
 	if err != nil {
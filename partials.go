@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// maxIncludeDepth limits the nesting of partials, so include cycles fail.
+const maxIncludeDepth = 10
+
+// partialExtensions are tried in order when resolving the name of a partial.
+var partialExtensions = []string{"", ".json.tmpl", ".tmpl"}
+
+// readPartial returns the partial template with the given name, eg.
+// partials/test-summary for partials/test-summary.json.tmpl in the partials dir.
+func readPartial(dir, name string) (string, error) {
+	clean := path.Clean(strings.TrimSpace(name))
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("partial %s is outside of the partials directory", name)
+	}
+
+	for _, ext := range partialExtensions {
+		b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(clean)+ext))
+		if err == nil {
+			return string(b), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to read partial %s: %s", name, err)
+		}
+	}
+	return "", fmt.Errorf("partial %s not found", name)
+}
+
+// parseTemplate parses a Block Kit template with the template functions, the
+// time functions, t and include, which renders a partial from the partials dir with
+// the data of the template, or with the given data: {{include "partials/test-summary"}}.
+func parseTemplate(name, text string, opts templateOptions, data interface{}, depth int) (*template.Template, error) {
+	funcs := template.FuncMap{}
+	for k, v := range templateFuncs {
+		funcs[k] = v
+	}
+	for k, v := range timeFuncs(opts.Location, opts.DateFormat) {
+		funcs[k] = v
+	}
+	funcs["t"] = translate(opts.Translations)
+	funcs["include"] = func(partial string, args ...interface{}) (string, error) {
+		if depth >= maxIncludeDepth {
+			return "", fmt.Errorf("partials are nested deeper than %d levels, check for include cycles", maxIncludeDepth)
+		}
+		text, err := readPartial(opts.PartialsDir, partial)
+		if err != nil {
+			return "", err
+		}
+
+		d := data
+		if len(args) > 0 {
+			d = args[0]
+		}
+		t, err := parseTemplate(partial, text, opts, d, depth+1)
+		if err != nil {
+			return "", err
+		}
+		var b bytes.Buffer
+		if err := t.Execute(&b, d); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+	return template.New(name).Funcs(funcs).Parse(text)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_renderBlocks_include(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "partials"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"partials/title.json.tmpl": `{"type": "header", "text": {"type": "plain_text", "text": {{json .Title}}}}`,
+		"partials/status.tmpl":     `{"type": "section", "text": {"type": "mrkdwn", "text": {{json .}}}}`,
+		"partials/loop.tmpl":       `{{include "partials/loop"}}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := layoutData{Title: "Build #12", Status: "Succeeded"}
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    []Block
+		wantErr bool
+	}{
+		{
+			name: "Partials with the template data and with given data",
+			tmpl: `[{{include "partials/title"}}, {{include "partials/status" .Status}}]`,
+			want: []Block{
+				{"type": "header", "text": map[string]interface{}{"type": "plain_text", "text": "Build #12"}},
+				{"type": "section", "text": map[string]interface{}{"type": "mrkdwn", "text": "Succeeded"}},
+			},
+		},
+		{name: "Missing partial", tmpl: `[{{include "partials/missing"}}]`, wantErr: true},
+		{name: "Outside of the partials dir", tmpl: `[{{include "../secret"}}]`, wantErr: true},
+		{name: "Include cycle", tmpl: `[{{include "partials/loop"}}]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderBlocks(tt.tmpl, data, templateOptions{PartialsDir: dir})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderBlocks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("renderBlocks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
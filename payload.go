@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// maxPayloadSize is the largest raw payload accepted by the step.
+const maxPayloadSize = 1024 * 1024
+
+const (
+	renderedPayloadFileName      = "slack-message-payload.json"
+	renderedPayloadPathOutputKey = "SLACK_MESSAGE_PAYLOAD_PATH"
+)
+
+// renderedPayloadMu serializes the writes of the batch workers.
+var renderedPayloadMu sync.Mutex
+
+// readPayload returns the raw payload from the payload JSON input or from the
+// payload file, with the environment variables expanded.
+func readPayload(payloadJSON, pth string) ([]byte, error) {
+	s := payloadJSON
+	if pth != "" {
+		b, err := os.ReadFile(pth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read payload file: %s", err)
+		}
+		s = string(b)
+	}
+
+	payload := []byte(strings.TrimSpace(os.ExpandEnv(s)))
+	if len(payload) > maxPayloadSize {
+		return nil, fmt.Errorf("payload is too large (%d bytes), the limit is %d bytes", len(payload), maxPayloadSize)
+	}
+	if !bytes.HasPrefix(payload, []byte("{")) || !json.Valid(payload) {
+		return nil, fmt.Errorf("payload is not a valid JSON object")
+	}
+	return payload, nil
+}
+
+// saveRenderedPayload writes the final payload to the deploy dir (or to the
+// temporary directory) and exports its path, so formatting issues can be
+// debugged without the debug log. With several messages the last one is kept.
+func saveRenderedPayload(conf config, b []byte) {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, b, "", "  "); err == nil {
+		b = indented.Bytes()
+	}
+
+	dir := conf.DeployDir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	pth := filepath.Join(dir, renderedPayloadFileName)
+
+	renderedPayloadMu.Lock()
+	defer renderedPayloadMu.Unlock()
+	if err := os.WriteFile(pth, b, 0644); err != nil {
+		log.Warnf("Failed to write the rendered payload: %s", err)
+		return
+	}
+	log.Debugf("Exporting output: %s=%s\n", renderedPayloadPathOutputKey, pth)
+	if err := exportEnvVariable(renderedPayloadPathOutputKey, pth); err != nil {
+		log.Warnf("Failed to export %s: %s", renderedPayloadPathOutputKey, err)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_readPayload(t *testing.T) {
+	t.Setenv("TEST_CHANNEL", "#builds")
+
+	tests := []struct {
+		name        string
+		payloadJSON string
+		want        string
+		wantErr     bool
+	}{
+		{name: "Env is expanded", payloadJSON: ` {"channel": "$TEST_CHANNEL"} `, want: `{"channel": "#builds"}`},
+		{name: "Invalid JSON", payloadJSON: `{"channel": `, wantErr: true},
+		{name: "Not an object", payloadJSON: `["text"]`, wantErr: true},
+		{name: "Too large", payloadJSON: `{"text": "` + strings.Repeat("a", maxPayloadSize) + `"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readPayload(tt.payloadJSON, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readPayload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("readPayload() = %v, want %v", string(got), tt.want)
+			}
+		})
+	}
+}
+
+func Test_saveRenderedPayload(t *testing.T) {
+	dir := t.TempDir()
+	saveRenderedPayload(config{DeployDir: dir}, []byte(`{"channel":"#builds","text":"Hello"}`))
+
+	b, err := os.ReadFile(filepath.Join(dir, renderedPayloadFileName))
+	if err != nil {
+		t.Fatalf("saveRenderedPayload() did not write the payload: %v", err)
+	}
+	if want := "{\n  \"channel\": \"#builds\",\n  \"text\": \"Hello\"\n}"; string(b) != want {
+		t.Errorf("saveRenderedPayload() wrote %s, want %s", b, want)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// Build phases
+const (
+	phaseOff   = "off"
+	phaseStart = "start"
+	phaseEnd   = "end"
+)
+
+// loadStartMessage returns the message sent in the start phase of the build, or
+// nil if there is none.
+func loadStartMessage(conf config) *threadState {
+	var start threadState
+	found, err := loadState(statePath(conf.StateDir, "start", conf.BuildSlug), &start)
+	if err != nil {
+		log.Warnf("Failed to load the build's start message: %s", err)
+		return nil
+	}
+	if !found {
+		return nil
+	}
+	return &start
+}
+
+// saveStartMessage stores the sent message, so it is updated in the end phase of the build.
+func saveStartMessage(conf config, response *SendMessageResponse) {
+	if response == nil || response.Timestamp == "" {
+		log.Warnf("The start message can only be updated when it is sent with an API token")
+		return
+	}
+
+	start := threadState{Channel: response.Channel, Ts: response.Timestamp}
+	if err := saveState(statePath(conf.StateDir, "start", conf.BuildSlug), start); err != nil {
+		log.Warnf("Failed to save the build's start message: %s", err)
+		return
+	}
+	log.Debugf("Saved start message %s of build %s", start.Ts, conf.BuildSlug)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+)
+
+func Test_startMessage(t *testing.T) {
+	conf := config{StateDir: t.TempDir(), BuildSlug: "build-1"}
+	if start := loadStartMessage(conf); start != nil {
+		t.Fatalf("loadStartMessage() = %v, want nil", start)
+	}
+
+	saveStartMessage(conf, &SendMessageResponse{Channel: "C123", Timestamp: "1700000000.000100"})
+	want := threadState{Channel: "C123", Ts: "1700000000.000100"}
+	if got := loadStartMessage(conf); got == nil || *got != want {
+		t.Errorf("loadStartMessage() = %v, want %v", got, want)
+	}
+	if other := loadStartMessage(config{StateDir: conf.StateDir, BuildSlug: "build-2"}); other != nil {
+		t.Errorf("loadStartMessage() of another build = %v, want nil", other)
+	}
+}
+
+func Test_parseInputIntoConfig_startPhase(t *testing.T) {
+	inp := Input{
+		BuildStatus:       "0",
+		Phase:             phaseStart,
+		PreTextOnSuccess:  "*Build Succeeded!*",
+		ColorOnSuccess:    "good",
+		PreTextInProgress: "*Build started…*",
+		ColorInProgress:   "#a1a1a1",
+	}
+	conf := parseInputIntoConfig(&inp)
+	if got := [2]string{conf.PreText, conf.Color}; got != [2]string{"*Build started…*", "#a1a1a1"} {
+		t.Errorf("parseInputIntoConfig() = %v, want %v", got, [2]string{"*Build started…*", "#a1a1a1"})
+	}
+}
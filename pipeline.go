@@ -0,0 +1,182 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// Pipeline modes
+const (
+	pipelineModeOff    = "off"
+	pipelineModeRoot   = "root"
+	pipelineModeStage  = "stage"
+	pipelineModeRollup = "rollup"
+)
+
+// pipelineState is the root message of a pipeline, shared between the stages
+// in the state dir.
+type pipelineState struct {
+	Channel string `json:"channel"`
+	Ts      string `json:"ts"`
+}
+
+// pipelineResult is the result of a single workflow of a pipeline. Every
+// workflow writes its own file, so that the workflows of a stage running in
+// parallel don't overwrite each other's results.
+type pipelineResult struct {
+	PipelineID string      `json:"pipeline_id"`
+	Workflow   digestEntry `json:"workflow"`
+}
+
+func pipelineStatePath(conf config) string {
+	return statePath(conf.StateDir, "pipeline", conf.PipelineID)
+}
+
+func pipelineResultPath(conf config, workflow string) string {
+	return statePath(conf.StateDir, "pipeline-result", conf.PipelineID+"-"+workflow)
+}
+
+// loadPipeline returns the state of the pipeline, or nil if no stage has sent a
+// message yet.
+func loadPipeline(conf config) *pipelineState {
+	var state pipelineState
+	found, err := loadState(pipelineStatePath(conf), &state)
+	if err != nil {
+		log.Warnf("Failed to load the pipeline's root message: %s", err)
+		return nil
+	}
+	if !found {
+		return nil
+	}
+	return &state
+}
+
+// pipelineEntry returns the result of the workflow of this build.
+func pipelineEntry(conf config) digestEntry {
+	return digestEntry{Name: conf.DigestEntryName, Success: conf.Success, BuildURL: conf.BuildURL}
+}
+
+// savePipelineResult records the result of the workflow of this build.
+func savePipelineResult(conf config) {
+	result := pipelineResult{PipelineID: conf.PipelineID, Workflow: pipelineEntry(conf)}
+	if err := saveState(pipelineResultPath(conf, conf.DigestEntryName), result); err != nil {
+		log.Warnf("Failed to save the result of the workflow: %s", err)
+	}
+}
+
+// loadPipelineResults returns the results of the workflows of the pipeline
+// sorted by name, with the result of the workflow of this build replacing its
+// saved result, and the paths of the result files.
+func loadPipelineResults(conf config) ([]digestEntry, []string) {
+	byName := map[string]digestEntry{}
+
+	// The key of the state path is sanitized, so the wildcard is appended to the path of an empty workflow name.
+	pattern := strings.TrimSuffix(pipelineResultPath(conf, ""), ".json") + "*.json"
+	pths, err := filepath.Glob(pattern)
+	if err != nil {
+		log.Warnf("Failed to list the results of the pipeline: %s", err)
+	}
+	var loaded []string
+	for _, pth := range pths {
+		var result pipelineResult
+		if _, err := loadState(pth, &result); err != nil {
+			log.Warnf("Failed to load the result of a workflow: %s", err)
+			continue
+		}
+		// A pipeline ID may be the prefix of another one.
+		if result.PipelineID != conf.PipelineID {
+			continue
+		}
+		byName[result.Workflow.Name] = result.Workflow
+		loaded = append(loaded, pth)
+	}
+	byName[conf.DigestEntryName] = pipelineEntry(conf)
+
+	entries := make([]digestEntry, 0, len(byName))
+	for _, entry := range byName {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, loaded
+}
+
+// saveRootMessage records the message sent by this build as the pipeline's root message.
+func saveRootMessage(conf config, response *SendMessageResponse) {
+	if response == nil || response.Timestamp == "" {
+		log.Warnf("The pipeline's thread can only be started with a message sent with an API token")
+		return
+	}
+	state := pipelineState{Channel: response.Channel, Ts: response.Timestamp}
+	if err := saveState(pipelineStatePath(conf), state); err != nil {
+		log.Warnf("Failed to save the pipeline's state: %s", err)
+	}
+}
+
+// sendPipelineRoot sends the pipeline's root message from the workflow of a
+// dedicated first stage, and records the result of the workflow.
+func sendPipelineRoot(conf config, msg Message) error {
+	response, err := deliver(conf, msg)
+	if err != nil {
+		return err
+	}
+	saveRootMessage(conf, response)
+	savePipelineResult(conf)
+	return nil
+}
+
+// sendPipelineStage sends the message of an intermediate workflow as a reply to
+// the pipeline's root message, or as the root message if this is the first
+// message of the pipeline, and records the result of the workflow.
+func sendPipelineStage(conf config, msg Message) error {
+	state := loadPipeline(conf)
+	if state != nil && msg.ThreadTs == "" {
+		log.Infof("Sending the message to the pipeline's thread")
+		msg.Channel = state.Channel
+		msg.ThreadTs = state.Ts
+	}
+	if state == nil {
+		log.Warnf("No root message found for the pipeline, this message becomes the root message")
+		log.Warnf("If the workflows of this stage run in parallel, each of them sends a root message: send it from a dedicated first stage with the root mode")
+	}
+
+	response, err := deliver(conf, msg)
+	if err != nil {
+		return err
+	}
+
+	if state == nil {
+		saveRootMessage(conf, response)
+	}
+	savePipelineResult(conf)
+	return nil
+}
+
+// sendPipelineRollup updates the pipeline's root message with the results of
+// every workflow of the pipeline, then removes the pipeline's state. A new
+// message is sent if no stage has sent a message.
+func sendPipelineRollup(conf config, msg Message) error {
+	state := loadPipeline(conf)
+	entries, results := loadPipelineResults(conf)
+	if state == nil {
+		log.Warnf("No root message found for the pipeline, sending a new message")
+	} else {
+		log.Infof("Updating the pipeline's root message with the rollup")
+		msg.Ts = state.Ts
+		msg.Channel = state.Channel
+		results = append(results, pipelineStatePath(conf))
+	}
+	if _, err := deliver(conf, withDigest(msg, entries)); err != nil {
+		return err
+	}
+
+	for _, pth := range results {
+		if err := os.Remove(pth); err != nil {
+			log.Warnf("Failed to remove the pipeline's state: %s", err)
+		}
+	}
+	return nil
+}
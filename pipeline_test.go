@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func Test_loadPipeline(t *testing.T) {
+	conf := config{StateDir: t.TempDir(), PipelineID: "pipeline-1", DigestEntryName: "android", BuildURL: "https://app.bitrise.io/build/2"}
+	if state := loadPipeline(conf); state != nil {
+		t.Fatalf("loadPipeline() = %v, want nil", state)
+	}
+
+	want := pipelineState{Channel: "C123", Ts: "1700000000.000100"}
+	if err := saveState(pipelineStatePath(conf), want); err != nil {
+		t.Fatal(err)
+	}
+	if got := loadPipeline(conf); got == nil || !reflect.DeepEqual(*got, want) {
+		t.Errorf("loadPipeline() = %v, want %v", got, want)
+	}
+	if entry := pipelineEntry(conf); entry != (digestEntry{Name: "android", Success: false, BuildURL: "https://app.bitrise.io/build/2"}) {
+		t.Errorf("pipelineEntry() = %v", entry)
+	}
+}
+
+func Test_loadPipelineResults(t *testing.T) {
+	dir := t.TempDir()
+
+	// The workflows of a parallel stage save their results without reading each other's.
+	for _, w := range []config{
+		{StateDir: dir, PipelineID: "pipeline-1", DigestEntryName: "ios", Success: true},
+		{StateDir: dir, PipelineID: "pipeline-1", DigestEntryName: "android"},
+		{StateDir: dir, PipelineID: "pipeline-1-retry", DigestEntryName: "web", Success: true},
+	} {
+		savePipelineResult(w)
+	}
+
+	// The result of this build replaces its saved result.
+	conf := config{StateDir: dir, PipelineID: "pipeline-1", DigestEntryName: "android", Success: true}
+	entries, pths := loadPipelineResults(conf)
+	want := []digestEntry{{Name: "android", Success: true}, {Name: "ios", Success: true}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("loadPipelineResults() entries = %v, want %v", entries, want)
+	}
+	if len(pths) != 2 {
+		t.Fatalf("loadPipelineResults() paths = %v, want 2 paths", pths)
+	}
+	for _, pth := range pths {
+		if _, err := os.Stat(pth); err != nil {
+			t.Errorf("loadPipelineResults() path %s: %s", pth, err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package slackmsg
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// MessageBuilder builds a Message step by step:
+//
+//	msg := slackmsg.NewMessage("#builds").
+//		Text("Build finished").
+//		Attachment(slackmsg.Attachment{Color: "good", Title: "Build #12"}).
+//		AddField("Branch", "main").
+//		Build()
+type MessageBuilder struct {
+	msg Message
+}
+
+// NewMessage starts building a message to the channel.
+func NewMessage(channel string) *MessageBuilder {
+	return &MessageBuilder{msg: Message{Channel: channel}}
+}
+
+// Text sets the text of the message.
+func (b *MessageBuilder) Text(text string) *MessageBuilder {
+	b.msg.Text = text
+	return b
+}
+
+// Username sets the bot's username.
+func (b *MessageBuilder) Username(username string) *MessageBuilder {
+	b.msg.Username = username
+	return b
+}
+
+// IconEmoji sets the emoji used as the icon of the message.
+func (b *MessageBuilder) IconEmoji(emoji string) *MessageBuilder {
+	b.msg.IconEmoji = emoji
+	return b
+}
+
+// IconURL sets the URL of the image used as the icon of the message.
+func (b *MessageBuilder) IconURL(url string) *MessageBuilder {
+	b.msg.IconURL = url
+	return b
+}
+
+// LinkNames linkifies channel names and usernames.
+func (b *MessageBuilder) LinkNames() *MessageBuilder {
+	b.msg.LinkNames = true
+	return b
+}
+
+// InThread makes the message a reply to the message with the ts timestamp.
+// If broadcast is true, the reply is also shown in the channel.
+func (b *MessageBuilder) InThread(ts string, broadcast bool) *MessageBuilder {
+	b.msg.ThreadTs = ts
+	b.msg.ReplyBroadcast = broadcast
+	return b
+}
+
+// Update makes the message update the message with the ts timestamp.
+func (b *MessageBuilder) Update(ts string) *MessageBuilder {
+	b.msg.Ts = ts
+	return b
+}
+
+// Ephemeral makes the message only visible to the user.
+func (b *MessageBuilder) Ephemeral(user string) *MessageBuilder {
+	b.msg.User = user
+	return b
+}
+
+// ScheduleAt schedules the message to be sent at t.
+func (b *MessageBuilder) ScheduleAt(t time.Time) *MessageBuilder {
+	b.msg.PostAt = t.Unix()
+	return b
+}
+
+// Metadata attaches an event to the message.
+func (b *MessageBuilder) Metadata(eventType string, payload json.RawMessage) *MessageBuilder {
+	b.msg.Metadata = &Metadata{EventType: eventType, EventPayload: payload}
+	return b
+}
+
+// Attachment adds an attachment to the message.
+func (b *MessageBuilder) Attachment(a Attachment) *MessageBuilder {
+	b.msg.Attachments = append(b.msg.Attachments, a)
+	return b
+}
+
+// AddField adds a field to the last attachment, creating one if needed.
+func (b *MessageBuilder) AddField(title, value string) *MessageBuilder {
+	a := b.lastAttachment()
+	a.Fields = append(a.Fields, Field{Title: title, Value: value})
+	return b
+}
+
+// AddButton adds a link button to the last attachment, creating one if needed.
+func (b *MessageBuilder) AddButton(text, url string) *MessageBuilder {
+	a := b.lastAttachment()
+	a.Buttons = append(a.Buttons, Button{Text: text, URL: url})
+	return b
+}
+
+// Blocks adds Block Kit blocks to the message.
+func (b *MessageBuilder) Blocks(blocks ...Block) *MessageBuilder {
+	b.msg.Blocks = append(b.msg.Blocks, blocks...)
+	return b
+}
+
+// Build returns the message.
+func (b *MessageBuilder) Build() Message {
+	return b.msg
+}
+
+func (b *MessageBuilder) lastAttachment() *Attachment {
+	if len(b.msg.Attachments) == 0 {
+		b.msg.Attachments = append(b.msg.Attachments, Attachment{})
+	}
+	return &b.msg.Attachments[len(b.msg.Attachments)-1]
+}
@@ -0,0 +1,52 @@
+package slackmsg
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMessageBuilder(t *testing.T) {
+	got := NewMessage("#builds").
+		Text("Build finished").
+		InThread("1700000000.000100", true).
+		ScheduleAt(time.Unix(1700000000, 0)).
+		AddField("Branch", "main").
+		AddButton("View", "https://app.bitrise.io").
+		Build()
+
+	want := Message{
+		Channel:        "#builds",
+		Text:           "Build finished",
+		ThreadTs:       "1700000000.000100",
+		ReplyBroadcast: true,
+		PostAt:         1700000000,
+		Attachments: []Attachment{{
+			Fields:  []Field{{Title: "Branch", Value: "main"}},
+			Buttons: []Button{{Text: "View", URL: "https://app.bitrise.io"}},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Build() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMethod(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  Message
+		want string
+	}{
+		{name: "Post", msg: Message{}, want: "chat.postMessage"},
+		{name: "Update", msg: Message{Ts: "1700000000.000100"}, want: "chat.update"},
+		{name: "Ephemeral", msg: Message{User: "U123"}, want: "chat.postEphemeral"},
+		{name: "Scheduled", msg: Message{PostAt: 1700000000}, want: "chat.scheduleMessage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Method(tt.msg); got != tt.want {
+				t.Errorf("Method() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
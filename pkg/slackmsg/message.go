@@ -0,0 +1,258 @@
+// Package slackmsg builds and sends Slack messages. It is used by the step and
+// can be imported by other steps and tools which need to send Slack messages.
+package slackmsg
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Message to post to a slack channel.
+// See also: https://api.slack.com/methods/chat.postMessage
+type Message struct {
+	// Channel to send message to.
+	//
+	// Can be an encoded ID (eg. C024BE91L), or the channel's name (eg. #general).
+	Channel string `json:"channel"`
+
+	// Text of the message to send. Required, unless providing only attachments instead.
+	Text string `json:"text,omitempty"`
+
+	// Attachments is a list of structured attachments.
+	Attachments []Attachment `json:"attachments,omitempty"`
+
+	// Blocks is a list of Block Kit layout blocks.
+	Blocks []Block `json:"blocks,omitempty"`
+
+	// IconEmoji is the emoji to use as the icon for the message. Overrides IconUrl.
+	IconEmoji string `json:"icon_emoji,omitempty"`
+
+	// IconURL is the URL to an image to use as the icon for the message.
+	IconURL string `json:"icon_url,omitempty"`
+
+	// LinkNames linkifies channel names and usernames.
+	LinkNames bool `json:"link_names,omitempty"`
+
+	// Username specifies the bot's username for the message.
+	Username string `json:"username,omitempty"`
+
+	// Provide another message's ts value to make this message a reply.
+	ThreadTs string `json:"thread_ts,omitempty"`
+
+	// Provide another message's ts value to make message to update
+	Ts string `json:"ts,omitempty"`
+
+	// Used in conjunction with thread_ts and indicates whether reply should be made visible to everyone in the channel or conversation.
+	ReplyBroadcast bool `json:"reply_broadcast,omitempty"`
+
+	// User is the ID of the user who will receive an ephemeral message.
+	//
+	// Ephemeral messages are only visible to this user in the channel.
+	User string `json:"user,omitempty"`
+
+	// PostAt is the Unix timestamp of the time when a scheduled message is sent.
+	PostAt int64 `json:"post_at,omitempty"`
+
+	// Metadata is attached to the message for Slack apps and workflows.
+	// See also: https://api.slack.com/metadata
+	Metadata *Metadata `json:"metadata,omitempty"`
+}
+
+// Metadata describes an event which Slack apps and Workflow Builder automations can react on.
+type Metadata struct {
+	// EventType is the name of the event, eg. build_finished.
+	EventType string `json:"event_type"`
+
+	// EventPayload is a JSON object with the event's details.
+	EventPayload json.RawMessage `json:"event_payload"`
+}
+
+// PlainText renders the message and its attachments as plain text.
+func (msg Message) PlainText() string {
+	var lines []string
+	if msg.Text != "" {
+		lines = append(lines, msg.Text)
+	}
+	for _, b := range msg.Blocks {
+		lines = append(lines, blockTexts(b)...)
+	}
+	for _, a := range msg.Attachments {
+		for _, s := range []string{a.PreText, a.AuthorName, a.Title, a.TitleLink, a.Text} {
+			if s != "" {
+				lines = append(lines, s)
+			}
+		}
+		for _, f := range a.Fields {
+			lines = append(lines, f.Title+": "+f.Value)
+		}
+		for _, b := range a.Buttons {
+			lines = append(lines, b.Text+": "+b.URL)
+		}
+		if a.Footer != "" {
+			lines = append(lines, a.Footer)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Block is a Block Kit layout block. Blocks are kept as generic JSON objects,
+// so any block type can be passed through.
+// See also: https://api.slack.com/reference/block-kit/blocks
+type Block map[string]interface{}
+
+// blockTexts returns the texts of the block and its nested elements.
+func blockTexts(v interface{}) []string {
+	var texts []string
+	switch v := v.(type) {
+	case Block:
+		return blockTexts(map[string]interface{}(v))
+	case map[string]interface{}:
+		if s, ok := v["text"].(string); ok && s != "" {
+			texts = append(texts, s)
+		}
+		for _, key := range []string{"text", "fields", "elements"} {
+			if _, ok := v[key].(string); !ok {
+				texts = append(texts, blockTexts(v[key])...)
+			}
+		}
+	case []interface{}:
+		for _, e := range v {
+			texts = append(texts, blockTexts(e)...)
+		}
+	}
+	return texts
+}
+
+// Attachment adds more context to a slack chat message.
+// See also: https://api.slack.com/docs/message-attachments
+type Attachment struct {
+	// Fallback is the plain-text summary of the attachment.
+	//
+	// This text will be used in clients that don't show formatted text (eg. IRC, mobile notifications)
+	// and should not contain any markup.
+	Fallback string `json:"fallback"`
+
+	// Color is used to color the border along the left side of the attachment.
+	//
+	// Can either be one of good, warning, danger, or any hex color code (eg. #439FE0).
+	Color string `json:"color"`
+
+	// PreText is an optional text that appears above the attachment block.
+	PreText string `json:"pretext,omitempty"`
+
+	// AuthorName is a small text used to display the author's name.
+	AuthorName string `json:"author_name,omitempty"`
+
+	// Title is displayed as larger, bold text near the top of a attachment.
+	Title string `json:"title,omitempty"`
+
+	// TitleLink is a URL that will hyperlink the Title.
+	TitleLink string `json:"title_link,omitempty"`
+
+	// Text is the main text of the attachment, and can contain standard message markup.
+	//
+	// The content will automatically collapse if it contains 700+ characters or 5+ linebreaks,
+	// and will display a "Show more..." link to expand the content.
+	Text string `json:"text,omitempty"`
+
+	// Fields is a list of fields to be displayed in a table inside the attachment.
+	Fields []Field `json:"fields,omitempty"`
+
+	// ImageURL is a URL to an image file that will be displayed inside the attachment.
+	//
+	// Supported formats: GIF, JPEG, PNG, and BMP.
+	// Large images will be resized to a maximum width of 400px or a maximum height of 500px.
+	ImageURL string `json:"image_url,omitempty"`
+
+	// ThumbURL is a URL to an image file that will be displayed as a
+	// thumbnail on the right side of a attachment.
+	//
+	// Supported formats: GIF, JPEG, PNG, and BMP.
+	// The thumbnail's longest dimension will be scaled down to 75px.
+	ThumbURL string `json:"thumb_url,omitempty"`
+
+	// Footer adds some brief text to help contextualize and identify an attachment.
+	//
+	// Limited to 300 characters.
+	Footer string `json:"footer,omitempty"`
+
+	// FooterIcon renders a small icon beside the footer text.
+	//
+	// It will be scaled down to 16px by 16px.
+	FooterIcon string `json:"footer_icon,omitempty"`
+
+	// TimeStamp is an integer value in epoch time to display and additional
+	// timestamp value as part of the attachment's footer.
+	TimeStamp int `json:"ts,omitempty"`
+
+	// Buttons is a list of buttons attached to the message as link buttons.
+	//
+	// An attachment may contain 1 to 5 buttons.
+	Buttons []Button `json:"actions,omitempty"`
+
+	// Blocks is a list of Block Kit layout blocks shown instead of the other
+	// content of the attachment, next to the color bar.
+	Blocks []Block `json:"blocks,omitempty"`
+
+	// MrkdwnIn lists the parts of the attachment formatted as mrkdwn.
+	//
+	// Can contain text, pretext and fields.
+	MrkdwnIn []string `json:"mrkdwn_in,omitempty"`
+}
+
+// Field will be displayed in a table inside the attachment.
+type Field struct {
+	// Title is shown as a bold heading above the value text.
+	Title string
+
+	// Value is the text value of the field.
+	Value string
+
+	// Short is an optional flag indicating whether the value is short enough
+	// to be displayed side-by-side with other values.
+	//
+	// If not set, values shorter than 40 characters are displayed side-by-side.
+	Short *bool
+}
+
+// MarshalJSON implements json.Marshaler.MarshalJSON.
+func (f Field) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{})
+	m["title"] = f.Title
+	m["value"] = f.Value
+	m["short"] = f.IsShort()
+	return json.Marshal(m)
+}
+
+// IsShort reports whether the field is displayed side-by-side with other fields.
+func (f Field) IsShort() bool {
+	if f.Short != nil {
+		return *f.Short
+	}
+	return len(f.Value) < 40
+}
+
+// Button is just a link that looks like a button.
+type Button struct {
+	// Type is set to button to tell slack to render a button.
+	Type string
+
+	// Text is the label for the button.
+	Text string
+
+	// URL is the fully qualified http or https url to deliver users to.
+	URL string
+
+	// Style is set to default so the buttons will use the UI's default text color.
+	Style string
+}
+
+// MarshalJSON implements json.Marshaler.MarshalJSON.
+func (b Button) MarshalJSON() ([]byte, error) {
+	m := make(map[string]string)
+	m["type"] = "button"
+	m["text"] = b.Text
+	m["url"] = b.URL
+	m["style"] = "default"
+	return json.Marshal(m)
+}
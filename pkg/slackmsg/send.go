@@ -0,0 +1,140 @@
+package slackmsg
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// APIURL is the base URL of the Slack Web API methods.
+const APIURL = "https://slack.com/api/"
+
+// DefaultSignatureHeader is the request header carrying the payload signature
+// if no other header is configured.
+const DefaultSignatureHeader = "X-Signature-256"
+
+// Client sends messages to an incoming webhook, or to the Web API if no
+// webhook URL is set.
+type Client struct {
+	WebhookURL string
+	APIToken   string
+
+	// HTTPClient is used to send the requests, http.DefaultClient if nil.
+	HTTPClient *http.Client
+
+	// SigningSecret, if set, is used to sign the payloads, so relays can verify the sender.
+	// The signature is sent in the SignatureHeader, DefaultSignatureHeader if empty.
+	SigningSecret   string
+	SignatureHeader string
+}
+
+// Response is the response of the Web API message methods.
+type Response struct {
+	OK        bool   `json:"ok"`
+	Error     string `json:"error"`
+	Timestamp string `json:"ts"`
+	Channel   string `json:"channel"`
+}
+
+// Method returns the Web API method used to send the message.
+func Method(msg Message) string {
+	switch {
+	case strings.TrimSpace(msg.Ts) != "":
+		return "chat.update"
+	case strings.TrimSpace(msg.User) != "":
+		return "chat.postEphemeral"
+	case msg.PostAt != 0:
+		return "chat.scheduleMessage"
+	}
+	return "chat.postMessage"
+}
+
+// Send sends the message. The response is only returned when the Web API is used,
+// as webhooks do not report the sent message.
+func (c Client) Send(ctx context.Context, msg Message) (*Response, error) {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimSpace(c.WebhookURL)
+	if url == "" {
+		url = APIURL + Method(msg)
+	}
+
+	body, err := c.Post(ctx, url, b)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(c.WebhookURL) != "" {
+		return nil, nil
+	}
+
+	var response Response
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %s", err)
+	}
+	if !response.OK {
+		return nil, fmt.Errorf("%s failed: %s", Method(msg), response.Error)
+	}
+	return &response, nil
+}
+
+// Post sends the JSON payload to the URL and returns the response body.
+func (c Client) Post(ctx context.Context, url string, payload []byte) (body []byte, err error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/json; charset=utf-8")
+	if c.APIToken != "" {
+		req.Header.Add("Authorization", "Bearer "+c.APIToken)
+	}
+	if c.SigningSecret != "" {
+		header := c.SignatureHeader
+		if header == "" {
+			header = DefaultSignatureHeader
+		}
+		req.Header.Set(header, Signature(c.SigningSecret, payload))
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send the request: %s", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("server error: %s, failed to read response: %s", resp.Status, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("server error: %s, response: %s", resp.Status, body)
+	}
+	return body, nil
+}
+
+// Signature returns the HMAC-SHA256 signature of the payload in the
+// "sha256=<hex digest>" format.
+func Signature(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
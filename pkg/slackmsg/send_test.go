@@ -0,0 +1,69 @@
+package slackmsg
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Send(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "Delivered", status: http.StatusOK},
+		{name: "No content", status: http.StatusNoContent},
+		{name: "Server error", status: http.StatusInternalServerError, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				got = string(b)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := Client{WebhookURL: server.URL}
+			_, err := client.Send(context.Background(), NewMessage("#builds").Text("Hello").Build())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if want := `{"channel":"#builds","text":"Hello"}`; got != want {
+				t.Errorf("Send() posted %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestClient_Post_signature(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "Default header", want: DefaultSignatureHeader},
+		{name: "Custom header", header: "X-Relay-Signature", want: "X-Relay-Signature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get(tt.want)
+			}))
+			defer server.Close()
+
+			client := Client{SigningSecret: "s3cret", SignatureHeader: tt.header}
+			if _, err := client.Post(context.Background(), server.URL, []byte(`{"text":"Hello"}`)); err != nil {
+				t.Fatalf("Post() error = %v", err)
+			}
+			if want := "sha256=b879d95e389a2d34eb23241a10bd2d15b130af3bdf51f17c88870cce3625979e"; got != want {
+				t.Errorf("Post() signature = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import "os"
+
+const (
+	platformAuto    = "auto"
+	platformNone    = "none"
+	platformIOS     = "ios"
+	platformAndroid = "android"
+)
+
+// platformEnvs are the outputs of the build steps used to detect the platform of the build.
+var platformEnvs = []struct {
+	platform string
+	envs     []string
+}{
+	{platform: platformIOS, envs: []string{"BITRISE_IPA_PATH", "BITRISE_XCARCHIVE_PATH", "BITRISE_APP_DIR_PATH"}},
+	{platform: platformAndroid, envs: []string{"BITRISE_APK_PATH", "BITRISE_AAB_PATH"}},
+}
+
+// resolvePlatform returns the platform of the build, detecting it from the
+// exported artifacts in auto mode. It is empty if the platform is unknown.
+func resolvePlatform(platform string) string {
+	switch platform {
+	case platformIOS, platformAndroid:
+		return platform
+	case platformAuto:
+		for _, p := range platformEnvs {
+			for _, env := range p.envs {
+				if os.Getenv(env) != "" {
+					return p.platform
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// selectPlatformValue chooses the platform specific value if it is set,
+// falling back to the default value otherwise.
+func selectPlatformValue(platform, value, ifIOS, ifAndroid string) string {
+	if platform == platformIOS && ifIOS != "" {
+		return ifIOS
+	}
+	if platform == platformAndroid && ifAndroid != "" {
+		return ifAndroid
+	}
+	return value
+}
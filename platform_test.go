@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func Test_resolvePlatform(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform string
+		envs     map[string]string
+		want     string
+	}{
+		{name: "iOS", platform: "ios", want: "ios"},
+		{name: "None", platform: "none", envs: map[string]string{"BITRISE_IPA_PATH": "app.ipa"}, want: ""},
+		{name: "Detect iOS", platform: "auto", envs: map[string]string{"BITRISE_IPA_PATH": "app.ipa"}, want: "ios"},
+		{name: "Detect Android", platform: "auto", envs: map[string]string{"BITRISE_AAB_PATH": "app.aab"}, want: "android"},
+		{name: "Unknown", platform: "auto", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, p := range platformEnvs {
+				for _, env := range p.envs {
+					os.Unsetenv(env)
+				}
+			}
+			for k, v := range tt.envs {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+
+			if got := resolvePlatform(tt.platform); got != tt.want {
+				t.Errorf("resolvePlatform() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_selectPlatformValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform string
+		want     string
+	}{
+		{name: "iOS", platform: "ios", want: "TestFlight build"},
+		{name: "Android", platform: "android", want: "default"},
+		{name: "Unknown", platform: "", want: "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectPlatformValue(tt.platform, "default", "TestFlight build", ""); got != tt.want {
+				t.Errorf("selectPlatformValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// blockKitBuilderURL is the visual preview tool of Slack, the message is passed in the URL fragment.
+const blockKitBuilderURL = "https://app.slack.com/block-kit-builder#"
+
+// previewURL returns the Block Kit Builder link of the payload, with the blocks
+// and the attachments of the message, which are the parts the builder shows.
+func previewURL(payload []byte) (string, error) {
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return "", fmt.Errorf("failed to parse payload: %s", err)
+	}
+
+	preview := map[string]json.RawMessage{}
+	for _, key := range []string{"blocks", "attachments"} {
+		if v, ok := msg[key]; ok {
+			preview[key] = v
+		}
+	}
+	if len(preview) == 0 {
+		return "", fmt.Errorf("the payload has no blocks or attachments")
+	}
+
+	b, err := json.Marshal(preview)
+	if err != nil {
+		return "", err
+	}
+	return blockKitBuilderURL + url.PathEscape(string(b)), nil
+}
+
+// logTextPreview prints the text preview of the payload, see textPreview.
+func logTextPreview(payload []byte) {
+	preview, err := payloadTextPreview(payload)
+	if err != nil {
+		log.Debugf("No text preview: %s", err)
+		return
+	}
+	if preview != "" {
+		log.Infof("Message preview:\n%s", preview)
+	}
+}
+
+// printPreview prints the payload and its Block Kit Builder link instead of sending it.
+func printPreview(conf config, msg Message) error {
+	payload := conf.Payload
+	if len(payload) == 0 {
+		var err error
+		if payload, err = json.MarshalIndent(msg, "", "  "); err != nil {
+			return err
+		}
+	}
+	if conf.TransformScript != "" {
+		var err error
+		if payload, err = transformPayload(conf.TransformScript, payload); err != nil {
+			return err
+		}
+	}
+	if err := checkPayloadBlocks(payload); err != nil {
+		return err
+	}
+	saveRenderedPayload(conf, payload)
+
+	log.Infof("Dry run, the message is not sent:\n%s", payload)
+	logTextPreview(payload)
+	if link, err := previewURL(payload); err != nil {
+		log.Warnf("No preview link: %s", err)
+	} else {
+		log.Infof("Preview in the Block Kit Builder: %s", link)
+	}
+	return nil
+}
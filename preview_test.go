@@ -0,0 +1,42 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func Test_previewURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Blocks and attachments",
+			payload: `{"channel": "#builds", "text": "Build #42", "blocks": [{"type": "divider"}], "attachments": [{"color": "good"}]}`,
+			want:    `{"attachments":[{"color":"good"}],"blocks":[{"type":"divider"}]}`,
+		},
+		{name: "Text only", payload: `{"text": "Build #42"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := previewURL([]byte(tt.payload))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("previewURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			fragment, err := url.PathUnescape(strings.TrimPrefix(got, blockKitBuilderURL))
+			if err != nil {
+				t.Fatalf("previewURL() = %v, not escaped: %v", got, err)
+			}
+			if fragment != tt.want {
+				t.Errorf("previewURL() fragment = %v, want %v", fragment, tt.want)
+			}
+		})
+	}
+}
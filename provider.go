@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// providerAuto selects the Slack provider matching the provided credentials.
+const providerAuto = "auto"
+
+// Provider delivers a message to a chat backend.
+type Provider interface {
+	// Send delivers the message. The response is only returned by providers
+	// which can report the sent message.
+	Send(conf config, msg Message) (*SendMessageResponse, error)
+}
+
+// providers is the registry of the available providers by name.
+var providers = map[string]Provider{}
+
+// registerProvider makes the provider available under the given name.
+func registerProvider(name string, p Provider) {
+	providers[name] = p
+}
+
+// providerNames returns the names of the registered providers.
+func providerNames() []string {
+	var names []string
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// providerName resolves the auto provider to the Slack provider matching the credentials.
+func providerName(name, apiToken string) string {
+	name = strings.TrimSpace(name)
+	if name == "" || name == providerAuto {
+		if apiToken != "" {
+			return "slack-api"
+		}
+		return "slack-webhook"
+	}
+	return name
+}
+
+// lookupProvider returns the provider selected by the configuration.
+func lookupProvider(conf config) (Provider, error) {
+	name := providerName(conf.Provider, string(conf.APIToken))
+	p, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider (%s), available providers: %s", name, strings.Join(providerNames(), ", "))
+	}
+	return p, nil
+}
+
+// isSlackProvider reports whether the provider sends Slack messages.
+func isSlackProvider(name string) bool {
+	return name == "slack-api" || name == "slack-webhook"
+}
+
+// hasSlackProvider reports whether any of the comma separated providers sends Slack messages.
+func hasSlackProvider(names, apiToken string) bool {
+	if len(splitList(names)) == 0 {
+		return isSlackProvider(providerName(names, apiToken))
+	}
+	for _, name := range splitList(names) {
+		if isSlackProvider(providerName(name, apiToken)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkProviderCredentials checks that the inputs required by the provider are set.
+func checkProviderCredentials(inp Input, name, webhookURL string) error {
+	switch name {
+	case "slack-api":
+		if inp.APIToken == "" {
+			return fmt.Errorf("The slack-api provider requires an API Token")
+		}
+	case "webex":
+		if inp.WebexBotToken != "" {
+			if inp.WebexRoomID == "" {
+				return fmt.Errorf("The Webex bot requires a Webex room ID")
+			}
+		} else if webhookURL == "" {
+			return fmt.Errorf("The webex provider requires a Webhook URL or a Webex bot token")
+		}
+	case "matrix":
+		if inp.MatrixHomeserverURL == "" || inp.MatrixAccessToken == "" || inp.MatrixRoomID == "" {
+			return fmt.Errorf("The matrix provider requires a Matrix homeserver URL, access token and room ID")
+		}
+	case "zulip":
+		if inp.ZulipSiteURL == "" || inp.ZulipBotEmail == "" || inp.ZulipAPIKey == "" || inp.ZulipStream == "" {
+			return fmt.Errorf("The zulip provider requires a Zulip site URL, bot email, API key and stream")
+		}
+	case "sns":
+		if inp.SNSTopicARN == "" {
+			return fmt.Errorf("The sns provider requires an SNS topic ARN")
+		}
+		if snsRegion(inp.SNSRegion, inp.SNSTopicARN) == "" {
+			return fmt.Errorf("Invalid SNS topic ARN (%s), provide the SNS region", inp.SNSTopicARN)
+		}
+	case "ntfy":
+		if inp.NtfyTopicURL == "" {
+			return fmt.Errorf("The ntfy provider requires an ntfy topic URL")
+		}
+	case "pushover":
+		if inp.PushoverAppToken == "" || inp.PushoverUserKey == "" {
+			return fmt.Errorf("The pushover provider requires a Pushover application token and user key")
+		}
+	case "telegram":
+		if inp.TelegramBotToken == "" || inp.TelegramChatID == "" {
+			return fmt.Errorf("The telegram provider requires a Telegram bot token and chat ID")
+		}
+	default:
+		if providers[name] == nil {
+			return fmt.Errorf("Unknown provider (%s), available providers: %s", name, strings.Join(providerNames(), ", "))
+		}
+		if webhookURL == "" {
+			return fmt.Errorf("The %s provider requires a Webhook URL", name)
+		}
+	}
+	return nil
+}
+
+// parseProviderWebhooks parses the JSON object of provider names to webhook URLs,
+// used when the message is sent with several providers.
+func parseProviderWebhooks(s string) (map[string]string, error) {
+	webhooks := map[string]string{}
+	if strings.TrimSpace(s) == "" {
+		return webhooks, nil
+	}
+	if err := json.Unmarshal([]byte(s), &webhooks); err != nil {
+		return nil, fmt.Errorf("Provider webhook URLs must be a JSON object of provider names to webhook URLs: %s", err)
+	}
+	for name, url := range webhooks {
+		webhooks[name] = strings.TrimSpace(url)
+	}
+	return webhooks, nil
+}
+
+// providerConfig returns the config of one of several providers, with the
+// webhook URL of the provider and only the credentials it uses.
+func providerConfig(conf config, name string) config {
+	conf.Provider = name
+	if url, ok := conf.ProviderWebhookURLs[name]; ok {
+		conf.WebhookURL = url
+	}
+	if name == "slack-api" {
+		conf.WebhookURL = ""
+	} else {
+		conf.APIToken = ""
+	}
+	return conf
+}
+
+func init() {
+	registerProvider("slack-webhook", slackProvider{})
+	registerProvider("slack-api", slackProvider{})
+	registerProvider("generic", slackProvider{})
+}
+
+// slackProvider sends the message to a Slack webhook or to the Web API. The
+// generic provider posts the same payload to any webhook.
+type slackProvider struct{}
+
+// Send implements Provider.
+func (slackProvider) Send(conf config, msg Message) (*SendMessageResponse, error) {
+	return postMessage(conf, msg)
+}
+
+// sendProviderRequest sends the request of a provider with its own API and
+// returns the response body. Non-2xx responses are returned as an error.
+func sendProviderRequest(req *http.Request) ([]byte, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send the request: %s", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Warnf("Failed to close response body: %s", err)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("server error: %s, failed to read response: %s", resp.Status, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("server error: %s, response: %s", resp.Status, body)
+	}
+	return body, nil
+}
+
+// postJSON posts v as JSON to the API of a service and logs the response.
+func postJSON(url, authorization string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	body, err := sendProviderRequest(req)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Response from %s: %s\n", req.URL.Host, body)
+	return nil
+}
+
+// getJSON gets the resource from the API of a service and decodes it into out.
+func getJSON(url, authorization string, out interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	body, err := sendProviderRequest(req)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Response from %s: %s\n", req.URL.Host, body)
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_providerName(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		apiToken string
+		want     string
+	}{
+		{name: "Auto with API token", provider: "auto", apiToken: "xoxb-token", want: "slack-api"},
+		{name: "Auto with webhook", provider: "", want: "slack-webhook"},
+		{name: "Explicit provider", provider: " discord ", apiToken: "xoxb-token", want: "discord"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := providerName(tt.provider, tt.apiToken); got != tt.want {
+				t.Errorf("providerName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newDiscordMessage(t *testing.T) {
+	short := true
+	msg := Message{
+		Username: "Bitrise",
+		Attachments: []Attachment{{
+			Color:   "good",
+			Title:   "Build #12",
+			PreText: "*Build Succeeded!*",
+			Text:    "Commit message",
+			Fields:  []Field{{Title: "App", Value: "Example", Short: &short}},
+			Footer:  "Bitrise",
+		}},
+	}
+	want := discordMessage{
+		Username: "Bitrise",
+		Embeds: []discordEmbed{{
+			Title:       "Build #12",
+			Description: "*Build Succeeded!*\nCommit message",
+			Color:       0x2eb886,
+			Fields:      []discordField{{Name: "App", Value: "Example", Inline: true}},
+			Footer:      &discordFooter{Text: "Bitrise"},
+		}},
+	}
+	if got := newDiscordMessage(msg); !reflect.DeepEqual(got, want) {
+		t.Errorf("newDiscordMessage() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_newTeamsCard(t *testing.T) {
+	msg := Message{
+		Attachments: []Attachment{{
+			Color:   "#3bc3a3",
+			Title:   "Build #12",
+			Text:    "Commit message",
+			Buttons: []Button{{Text: "View", URL: "https://app.bitrise.io"}},
+		}},
+	}
+	want := teamsCard{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		Summary:    "Build #12",
+		ThemeColor: "3bc3a3",
+		Title:      "Build #12",
+		Sections:   []teamsSection{{Text: "Commit message"}},
+		PotentialAction: []teamsAction{{
+			Type:    "OpenUri",
+			Name:    "View",
+			Targets: []teamsTarget{{OS: "default", URI: "https://app.bitrise.io"}},
+		}},
+	}
+	if got := newTeamsCard(msg); !reflect.DeepEqual(got, want) {
+		t.Errorf("newTeamsCard() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_newTelegramMessage(t *testing.T) {
+	msg := Message{Text: "*Build Succeeded!* <https://app.bitrise.io/build/1|View build>"}
+	want := telegramMessage{
+		ChatID:                "-1001234567890",
+		Text:                  `<b>Build Succeeded!</b> <a href="https://app.bitrise.io/build/1">View build</a>`,
+		ParseMode:             "HTML",
+		DisableWebPagePreview: true,
+	}
+	if got := newTelegramMessage("-1001234567890", msg); !reflect.DeepEqual(got, want) {
+		t.Errorf("newTelegramMessage() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_newWebexMessage(t *testing.T) {
+	msg := Message{Text: "*Build Succeeded!*"}
+	want := webexMessage{RoomID: "Y2lzY29zcGFyazovL3VzL1JPT00v", Text: "*Build Succeeded!*", Markdown: "**Build Succeeded!**"}
+	if got := newWebexMessage("Y2lzY29zcGFyazovL3VzL1JPT00v", msg); !reflect.DeepEqual(got, want) {
+		t.Errorf("newWebexMessage() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_newMatrixMessage(t *testing.T) {
+	msg := Message{Text: "*Build Succeeded!*\nLog:\n```\nline 1\nline 2\n```"}
+	want := matrixMessage{
+		MsgType:       "m.notice",
+		Body:          msg.Text,
+		Format:        "org.matrix.custom.html",
+		FormattedBody: "<b>Build Succeeded!</b><br>Log:<br><pre>line 1\nline 2</pre>",
+	}
+	if got := newMatrixMessage(msg); !reflect.DeepEqual(got, want) {
+		t.Errorf("newMatrixMessage() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_matrixEventURL(t *testing.T) {
+	got := matrixEventURL("https://matrix.example.org/", "!abc:example.org", "bitrise-1")
+	if want := "https://matrix.example.org/_matrix/client/v3/rooms/%21abc:example.org/send/m.room.message/bitrise-1"; got != want {
+		t.Errorf("matrixEventURL() = %v, want %v", got, want)
+	}
+}
+
+func Test_newZulipMessage(t *testing.T) {
+	msg := Message{IconEmoji: ":white_check_mark:", Text: "*Build Succeeded!* <https://app.bitrise.io/build/1|View build>"}
+	want := zulipMessage{
+		Type:    "stream",
+		To:      "mobile",
+		Topic:   "Builds",
+		Content: ":white_check_mark: **Build Succeeded!** [View build](https://app.bitrise.io/build/1)",
+	}
+	if got := newZulipMessage("mobile", "Builds", msg); !reflect.DeepEqual(got, want) {
+		t.Errorf("newZulipMessage() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_providerConfig(t *testing.T) {
+	conf := config{
+		APIToken:            "xoxb-token",
+		WebhookURL:          "https://hooks.slack.com/services/x",
+		ProviderWebhookURLs: map[string]string{"teams": "https://example.webhook.office.com/x"},
+	}
+	tests := []struct {
+		name           string
+		provider       string
+		wantAPIToken   string
+		wantWebhookURL string
+	}{
+		{name: "Slack API", provider: "slack-api", wantAPIToken: "xoxb-token"},
+		{name: "Provider webhook", provider: "teams", wantWebhookURL: "https://example.webhook.office.com/x"},
+		{name: "Default webhook", provider: "discord", wantWebhookURL: "https://hooks.slack.com/services/x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := providerConfig(conf, tt.provider)
+			if got.Provider != tt.provider || string(got.APIToken) != tt.wantAPIToken || got.WebhookURL != tt.wantWebhookURL {
+				t.Errorf("providerConfig() = %v, %v, %v, want %v, %v, %v", got.Provider, got.APIToken, got.WebhookURL, tt.provider, tt.wantAPIToken, tt.wantWebhookURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// pushoverAPIURL is the message endpoint of the Pushover API.
+const pushoverAPIURL = "https://api.pushover.net/1/messages.json"
+
+// pushoverMessageMaxLength is the longest message accepted by Pushover.
+const pushoverMessageMaxLength = 1024
+
+func init() {
+	registerProvider("ntfy", ntfyProvider{})
+	registerProvider("pushover", pushoverProvider{})
+}
+
+// ntfyProvider publishes the message as a push notification to an ntfy topic.
+type ntfyProvider struct{}
+
+// ntfyConfig is the topic of the ntfy provider.
+type ntfyConfig struct {
+	TopicURL    string
+	AccessToken string
+}
+
+// pushoverProvider sends the message as a push notification with Pushover.
+type pushoverProvider struct{}
+
+// pushoverConfig is the application and the recipient of the pushover provider.
+type pushoverConfig struct {
+	AppToken string
+	UserKey  string
+}
+
+// pushLink returns the first button or the title link of the message, opened
+// when the notification is tapped.
+func pushLink(msg Message) (string, string) {
+	for _, a := range msg.Attachments {
+		if len(a.Buttons) > 0 {
+			return a.Buttons[0].URL, a.Buttons[0].Text
+		}
+		if a.TitleLink != "" {
+			return a.TitleLink, a.Title
+		}
+	}
+	return "", ""
+}
+
+// newNtfyRequest returns the publish request of the message. Failed builds are
+// sent with high priority, so they are not missed.
+func newNtfyRequest(conf ntfyConfig, msg Message, success bool) (*http.Request, error) {
+	req, err := http.NewRequest("POST", strings.TrimSpace(conf.TopicURL), strings.NewReader(msg.PlainText()))
+	if err != nil {
+		return nil, err
+	}
+	// Non-ASCII titles are encoded as RFC 2047 words, which ntfy decodes.
+	req.Header.Set("Title", mime.BEncoding.Encode("utf-8", messageTitle(msg)))
+	req.Header.Set("Priority", "default")
+	if !success {
+		req.Header.Set("Priority", "high")
+	}
+	if emoji := strings.Trim(msg.IconEmoji, ":"); emoji != "" && !isUnicodeEmoji(emoji) {
+		// ntfy shows the tags matching an emoji shortcode as emoji.
+		req.Header.Set("Tags", emoji)
+	}
+	if link, _ := pushLink(msg); link != "" {
+		req.Header.Set("Click", link)
+	}
+	if conf.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+conf.AccessToken)
+	}
+	return req, nil
+}
+
+// Send implements Provider.
+func (ntfyProvider) Send(conf config, msg Message) (*SendMessageResponse, error) {
+	req, err := newNtfyRequest(conf.Ntfy, msg, conf.Success)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Request to ntfy: %s\n", msg.PlainText())
+
+	body, err := sendProviderRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Response from ntfy: %s\n", body)
+	return nil, nil
+}
+
+// newPushoverParams returns the parameters of the message. Failed builds are
+// sent with high priority, which bypasses the quiet hours of the user.
+func newPushoverParams(conf pushoverConfig, msg Message, success bool) url.Values {
+	text := msg.PlainText()
+	if r := []rune(text); len(r) > pushoverMessageMaxLength {
+		text = string(r[:pushoverMessageMaxLength-1]) + "…"
+	}
+	params := url.Values{
+		"token":    {conf.AppToken},
+		"user":     {conf.UserKey},
+		"title":    {messageTitle(msg)},
+		"message":  {text},
+		"priority": {"0"},
+	}
+	if !success {
+		params.Set("priority", "1")
+	}
+	if link, title := pushLink(msg); link != "" {
+		params.Set("url", link)
+		params.Set("url_title", title)
+	}
+	return params
+}
+
+// Send implements Provider.
+func (pushoverProvider) Send(conf config, msg Message) (*SendMessageResponse, error) {
+	params := newPushoverParams(conf.Pushover, msg, conf.Success)
+	log.Debugf("Request to Pushover: %s\n", params.Get("message"))
+
+	req, err := http.NewRequest("POST", pushoverAPIURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := sendProviderRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Response from Pushover: %s\n", body)
+	return nil, nil
+}
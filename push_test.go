@@ -0,0 +1,49 @@
+package main
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func Test_newNtfyRequest(t *testing.T) {
+	msg := Message{
+		IconEmoji: ":x:",
+		Attachments: []Attachment{{
+			Title:   "Build #12 failed",
+			Text:    "Fix login",
+			Buttons: []Button{{Text: "View Build", URL: "https://app.bitrise.io/build/12"}},
+		}},
+	}
+	req, err := newNtfyRequest(ntfyConfig{TopicURL: "https://ntfy.sh/releases", AccessToken: "tk_123"}, msg, false)
+	if err != nil {
+		t.Fatalf("newNtfyRequest() error = %v", err)
+	}
+
+	want := map[string]string{
+		"Title":         "Build #12 failed",
+		"Priority":      "high",
+		"Tags":          "x",
+		"Click":         "https://app.bitrise.io/build/12",
+		"Authorization": "Bearer tk_123",
+	}
+	for key, value := range want {
+		if got := req.Header.Get(key); got != value {
+			t.Errorf("newNtfyRequest() %s = %v, want %v", key, got, value)
+		}
+	}
+}
+
+func Test_newPushoverParams(t *testing.T) {
+	msg := Message{Text: "Build succeeded"}
+	want := url.Values{
+		"token":    {"app"},
+		"user":     {"user"},
+		"title":    {"Build succeeded"},
+		"message":  {"Build succeeded"},
+		"priority": {"0"},
+	}
+	if got := newPushoverParams(pushoverConfig{AppToken: "app", UserKey: "user"}, msg, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("newPushoverParams() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// enqueueMessage appends the undeliverable message to the newline-delimited JSON queue file.
+func enqueueMessage(pth string, msg Message) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to serialize the message: %s", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+		return fmt.Errorf("failed to create queue directory: %s", err)
+	}
+
+	f, err := os.OpenFile(pth, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open queue file: %s", err)
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write queue file: %s", err)
+	}
+	return f.Close()
+}
+
+// readQueue returns the queued messages, or nil if the queue file does not exist.
+func readQueue(pth string) ([]Message, error) {
+	f, err := os.Open(pth)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open queue file: %s", err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Warnf("Failed to close queue file: %s", err)
+		}
+	}()
+
+	var msgs []Message
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var msg Message
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			return nil, fmt.Errorf("invalid queued message in line %d: %s", n, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read queue file: %s", err)
+	}
+	return msgs, nil
+}
+
+// flushQueue retries the queued messages in order with send and keeps the ones
+// that still failed in the queue. It returns the number of messages left in the queue.
+// The queued messages are replayed as they were queued: the Web API method is
+// chosen by the message, and the outputs of this run are not exported.
+func flushQueue(conf config, send func(config, Message) (*SendMessageResponse, error)) (int, error) {
+	msgs, err := readQueue(conf.QueueFilePath)
+	if err != nil || len(msgs) == 0 {
+		return 0, err
+	}
+	conf.Ts = ""
+	conf.ThreadTsOutputVariableName = ""
+
+	log.Infof("Retrying %d queued message(s)", len(msgs))
+	var remaining []Message
+	for i, msg := range msgs {
+		if _, err := send(conf, msg); err != nil {
+			log.Warnf("Failed to send queued message %d: %s", i+1, err)
+			remaining = append(remaining, msg)
+		}
+	}
+
+	if err := os.Remove(conf.QueueFilePath); err != nil {
+		return len(remaining), fmt.Errorf("failed to remove queue file: %s", err)
+	}
+	for _, msg := range remaining {
+		if err := enqueueMessage(conf.QueueFilePath, msg); err != nil {
+			return len(remaining), err
+		}
+	}
+	log.Infof("Sent %d of %d queued message(s)", len(msgs)-len(remaining), len(msgs))
+	return len(remaining), nil
+}
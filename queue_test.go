@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_flushQueue(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "queue.jsonl")
+	for _, text := range []string{"first", "unreachable", "third"} {
+		if err := enqueueMessage(pth, Message{Text: text}); err != nil {
+			t.Fatalf("enqueueMessage() error = %v", err)
+		}
+	}
+
+	var sent []string
+	send := func(conf config, msg Message) (*SendMessageResponse, error) {
+		if msg.Text == "unreachable" {
+			return nil, fmt.Errorf("connection refused")
+		}
+		sent = append(sent, msg.Text)
+		return nil, nil
+	}
+
+	remaining, err := flushQueue(config{QueueFilePath: pth}, send)
+	if err != nil {
+		t.Fatalf("flushQueue() error = %v", err)
+	}
+	if remaining != 1 {
+		t.Errorf("flushQueue() = %v, want 1", remaining)
+	}
+	if want := []string{"first", "third"}; !reflect.DeepEqual(sent, want) {
+		t.Errorf("flushQueue() sent %v, want %v", sent, want)
+	}
+
+	queued, err := readQueue(pth)
+	if err != nil {
+		t.Fatalf("readQueue() error = %v", err)
+	}
+	if want := []Message{{Text: "unreachable"}}; !reflect.DeepEqual(queued, want) {
+		t.Errorf("readQueue() = %v, want %v", queued, want)
+	}
+}
+
+func Test_flushQueue_replaysQueuedMessages(t *testing.T) {
+	pth := filepath.Join(t.TempDir(), "queue.jsonl")
+	if err := enqueueMessage(pth, Message{Channel: "C1", Text: "queued"}); err != nil {
+		t.Fatalf("enqueueMessage() error = %v", err)
+	}
+
+	// This run updates the start message of the build and exports its timestamp.
+	conf := config{QueueFilePath: pth, Ts: "1405894322.002768", ThreadTsOutputVariableName: "SLACK_THREAD_TS"}
+	send := func(conf config, msg Message) (*SendMessageResponse, error) {
+		if conf.Ts != "" || conf.ThreadTsOutputVariableName != "" {
+			t.Errorf("flushQueue() sent with Ts %q and output %q, want neither", conf.Ts, conf.ThreadTsOutputVariableName)
+		}
+		b, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := payloadMethod(b), "chat.postMessage"; got != want {
+			t.Errorf("flushQueue() method = %v, want %v", got, want)
+		}
+		return nil, nil
+	}
+
+	if _, err := flushQueue(conf, send); err != nil {
+		t.Fatalf("flushQueue() error = %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket per target with a burst of one message: a target
+// (webhook or channel) receives at most one message per interval, while messages
+// to different targets are not delayed. Slack allows about one message per
+// second per webhook and per channel.
+type rateLimiter struct {
+	interval time.Duration
+	now      func() time.Time
+	sleep    func(time.Duration)
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+func newRateLimiter(interval time.Duration) *rateLimiter {
+	return &rateLimiter{
+		interval: interval,
+		now:      time.Now,
+		sleep:    time.Sleep,
+		next:     map[string]time.Time{},
+	}
+}
+
+// Wait blocks until a message can be sent to the target.
+func (l *rateLimiter) Wait(target string) {
+	l.mu.Lock()
+	now := l.now()
+	at := l.next[target]
+	if at.Before(now) {
+		at = now
+	}
+	l.next[target] = at.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait := at.Sub(now); wait > 0 {
+		l.sleep(wait)
+	}
+}
+
+// rateLimitTarget returns the rate limited target of the message: the webhook,
+// or the channel when sending with the API token.
+func rateLimitTarget(conf config, msg Message) string {
+	if webhook := strings.TrimSpace(conf.WebhookURL); webhook != "" {
+		return webhook
+	}
+	return msg.Channel
+}
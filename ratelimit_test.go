@@ -0,0 +1,30 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_rateLimiter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	var waits []time.Duration
+
+	l := newRateLimiter(time.Second)
+	l.now = func() time.Time { return now }
+	l.sleep = func(d time.Duration) {
+		waits = append(waits, d)
+		now = now.Add(d)
+	}
+
+	l.Wait("#ios")
+	l.Wait("#android")
+	l.Wait("#ios")
+	now = now.Add(3 * time.Second)
+	l.Wait("#ios")
+	l.Wait("#ios")
+
+	if want := []time.Duration{time.Second, time.Second}; !reflect.DeepEqual(waits, want) {
+		t.Errorf("rateLimiter.Wait() waits = %v, want %v", waits, want)
+	}
+}
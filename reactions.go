@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// reactionName returns the emoji name without the surrounding colons.
+func reactionName(s string) string {
+	return strings.Trim(strings.TrimSpace(s), ":")
+}
+
+// updateReactions removes and adds the configured reactions on the message
+// identified by the channel and the reaction timestamp.
+func updateReactions(conf config) error {
+	token := string(conf.APIToken)
+	channel, err := reactionChannel(token, strings.TrimSpace(conf.Channel))
+	if err != nil {
+		return err
+	}
+	params := url.Values{
+		"channel":   {channel},
+		"timestamp": {strings.TrimSpace(conf.ReactionTs)},
+	}
+
+	if name := reactionName(conf.RemoveReaction); name != "" {
+		params.Set("name", name)
+		if err := callAPI(token, "reactions.remove", params, nil); err != nil && !isAPIError(err, "no_reaction") {
+			return err
+		}
+		log.Infof("Removed :%s: reaction", name)
+	}
+
+	if name := reactionName(conf.Reaction); name != "" {
+		params.Set("name", name)
+		if err := callAPI(token, "reactions.add", params, nil); err != nil && !isAPIError(err, "already_reacted") {
+			return err
+		}
+		log.Infof("Added :%s: reaction", name)
+	}
+
+	return nil
+}
+
+// reactionChannel returns the ID of the channel, as the reactions methods
+// don't accept channel names.
+func reactionChannel(token, channel string) (string, error) {
+	if !strings.HasPrefix(channel, "#") {
+		return channel, nil
+	}
+
+	c, err := findChannel(token, channel)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up channel %s: %s", channel, err)
+	}
+	if c == nil {
+		return "", fmt.Errorf("channel %s not found: check the channel name, private channels are only visible once the bot is invited", channel)
+	}
+	log.Debugf("Resolved channel %s to %s", channel, c.ID)
+	return c.ID, nil
+}
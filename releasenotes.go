@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// defaultReleaseNotesLength keeps the release notes within the 3000 characters
+// limit of a section block, leaving room for the heading.
+const defaultReleaseNotesLength = 2900
+
+var (
+	mdHeadingPattern    = regexp.MustCompile(`(?m)^#{1,6}\s+(.+?)\s*#*\s*$`)
+	mdBoldPattern       = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	mdItalicPattern     = regexp.MustCompile(`\*([^*\n]+)\*`)
+	mdStrikePattern     = regexp.MustCompile(`~~(.+?)~~`)
+	mdLinkPattern       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdListMarkerPattern = regexp.MustCompile(`(?m)^(\s*)[-*+]\s+`)
+)
+
+// markdownToMrkdwn converts the common Markdown formatting to Slack's mrkdwn.
+func markdownToMrkdwn(s string) string {
+	// Bold is marked with a placeholder first, so it is not converted to italic.
+	const bold = "\x00"
+
+	s = strings.Replace(s, "\r\n", "\n", -1)
+	s = mdHeadingPattern.ReplaceAllString(s, bold+"$1"+bold)
+	s = mdListMarkerPattern.ReplaceAllString(s, "$1• ")
+	s = mdBoldPattern.ReplaceAllString(s, bold+"$2"+bold)
+	s = mdItalicPattern.ReplaceAllString(s, "_${1}_")
+	s = mdStrikePattern.ReplaceAllString(s, "~$1~")
+	s = mdLinkPattern.ReplaceAllString(s, "<$2|$1>")
+	return strings.Replace(s, bold, "*", -1)
+}
+
+// truncateMrkdwn shortens the text to at most max characters, cutting at a line
+// or word boundary and never inside a link.
+func truncateMrkdwn(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+
+	const ellipsis = "…"
+	cut := string(runes[:max-1])
+	if i := strings.LastIndex(cut, "\n"); i > len(cut)/2 {
+		cut = cut[:i]
+	} else if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	if open := strings.LastIndex(cut, "<"); open > strings.LastIndex(cut, ">") {
+		cut = cut[:open]
+	}
+	return strings.TrimRight(cut, " \n") + ellipsis
+}
+
+// readReleaseNotes loads the Markdown or plain text release notes as mrkdwn.
+func readReleaseNotes(pth string, max int) (string, error) {
+	b, err := os.ReadFile(pth)
+	if err != nil {
+		return "", fmt.Errorf("failed to read release notes: %s", err)
+	}
+	if max <= 0 {
+		max = defaultReleaseNotesLength
+	}
+	return truncateMrkdwn(strings.TrimSpace(markdownToMrkdwn(string(b))), max), nil
+}
+
+// withReleaseNotes returns a copy of msg with the release notes below the other
+// fields, titled with tr.
+func withReleaseNotes(msg Message, notes string, tr func(string) string) Message {
+	if len(msg.Attachments) == 0 || notes == "" {
+		return msg
+	}
+
+	short := false
+	attachments := append([]Attachment{}, msg.Attachments...)
+	attachments[0].Fields = append(append([]Field{}, attachments[0].Fields...), Field{Title: tr("What's new"), Value: notes, Short: &short})
+	msg.Attachments = attachments
+	return msg
+}
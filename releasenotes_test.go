@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+func Test_markdownToMrkdwn(t *testing.T) {
+	md := `## 2.14.0
+- **New** dark mode, see [the docs](https://example.com/docs)
+* Fixed *flaky* login
+- ~~Removed~~ legacy API`
+
+	want := `*2.14.0*
+• *New* dark mode, see <https://example.com/docs|the docs>
+• Fixed _flaky_ login
+• ~Removed~ legacy API`
+	if got := markdownToMrkdwn(md); got != want {
+		t.Errorf("markdownToMrkdwn() = %q, want %q", got, want)
+	}
+}
+
+func Test_truncateMrkdwn(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		max  int
+		want string
+	}{
+		{name: "Short text", s: "• Dark mode", max: 20, want: "• Dark mode"},
+		{name: "Cut at line", s: "• Dark mode\n• Faster login\n• New icon", max: 30, want: "• Dark mode\n• Faster login…"},
+		{name: "Cut at word", s: "Dark mode and faster login", max: 15, want: "Dark mode and…"},
+		{name: "Not inside a link", s: "See <https://example.com/docs|the docs>", max: 20, want: "See…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateMrkdwn(tt.s, tt.max); got != tt.want {
+				t.Errorf("truncateMrkdwn() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_withReleaseNotes(t *testing.T) {
+	msg := Message{Attachments: []Attachment{{Fields: []Field{{Title: "Branch", Value: "main"}}}}}
+	got := withReleaseNotes(msg, "• Dark mode", translate(map[string]string{"What's new": "Neuigkeiten"}))
+
+	fields := got.Attachments[0].Fields
+	if len(fields) != 2 || fields[1].Title != "Neuigkeiten" || fields[1].Value != "• Dark mode" {
+		t.Errorf("withReleaseNotes() fields = %v, want the translated release notes field last", fields)
+	}
+	if len(msg.Attachments[0].Fields) != 1 {
+		t.Errorf("withReleaseNotes() modified the original message")
+	}
+}
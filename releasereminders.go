@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// releaseReminderHour is the hour (UTC) of the reminders of a date without a time.
+const releaseReminderHour = 9
+
+// releaseReminderConfig is the date of a release milestone, eg. the code
+// freeze, and the Slack reminders created before it.
+type releaseReminderConfig struct {
+	Date       time.Time
+	DaysBefore []int
+	Text       string
+	User       string
+}
+
+// releaseReminder is a reminder to create.
+type releaseReminder struct {
+	Time time.Time
+	Text string
+}
+
+// parseReleaseReminderDate parses an RFC3339 timestamp or a date, which is reminded at 09:00 UTC.
+func parseReleaseReminderDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid release reminder date (%s), use a date (2006-01-02) or RFC3339 (2006-01-02T15:04:05+07:00)", s)
+	}
+	return d.Add(releaseReminderHour * time.Hour), nil
+}
+
+// parseReleaseReminderDays parses the comma separated list of days before the date.
+func parseReleaseReminderDays(s string) ([]int, error) {
+	var days []int
+	for _, item := range splitList(s) {
+		d, err := strconv.Atoi(item)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid number of days before the release reminder date (%s)", item)
+		}
+		days = append(days, d)
+	}
+	return days, nil
+}
+
+// releaseReminders returns the reminders of the days before the date, rendering
+// the text template with the number of days left. Reminders in the past are skipped.
+func releaseReminders(conf releaseReminderConfig, now time.Time) ([]releaseReminder, error) {
+	tmpl, err := template.New("reminder").Parse(conf.Text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release reminder text: %s", err)
+	}
+
+	var reminders []releaseReminder
+	for _, days := range conf.DaysBefore {
+		t := conf.Date.AddDate(0, 0, -days)
+		if !t.After(now) {
+			log.Debugf("The reminder %d days before %s is in the past, skipping", days, conf.Date.Format("2006-01-02"))
+			continue
+		}
+
+		var b bytes.Buffer
+		data := struct {
+			Days int
+			Date string
+		}{Days: days, Date: conf.Date.Format("2006-01-02")}
+		if err := tmpl.Execute(&b, data); err != nil {
+			return nil, fmt.Errorf("failed to render the release reminder text: %s", err)
+		}
+		reminders = append(reminders, releaseReminder{Time: t, Text: b.String()})
+	}
+	return reminders, nil
+}
+
+// addReleaseReminders creates the Slack reminders of the release date.
+func addReleaseReminders(conf config) error {
+	reminders, err := releaseReminders(conf.ReleaseReminder, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, r := range reminders {
+		params := url.Values{
+			"text": {r.Text},
+			"time": {strconv.FormatInt(r.Time.Unix(), 10)},
+		}
+		if conf.ReleaseReminder.User != "" {
+			params.Set("user", conf.ReleaseReminder.User)
+		}
+		if err := callAPI(string(conf.APIToken), "reminders.add", params, nil); err != nil {
+			return fmt.Errorf("failed to add the reminder %s: %s", r.Text, err)
+		}
+		log.Infof("Added the reminder %s at %s", r.Text, r.Time.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_parseReleaseReminderDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "Date", s: "2024-05-02", want: time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)},
+		{name: "RFC3339", s: "2024-05-02T14:00:00Z", want: time.Date(2024, 5, 2, 14, 0, 0, 0, time.UTC)},
+		{name: "Invalid", s: "May 2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseReleaseReminderDate(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseReleaseReminderDate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseReleaseReminderDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_releaseReminders(t *testing.T) {
+	date := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	conf := releaseReminderConfig{
+		Date:       date,
+		DaysBefore: []int{7, 2, 0},
+		Text:       "{{if .Days}}Code freeze in {{.Days}} days{{else}}Code freeze today{{end}}",
+	}
+	now := time.Date(2024, 5, 5, 12, 0, 0, 0, time.UTC)
+
+	want := []releaseReminder{
+		{Time: date.AddDate(0, 0, -2), Text: "Code freeze in 2 days"},
+		{Time: date, Text: "Code freeze today"},
+	}
+	got, err := releaseReminders(conf, now)
+	if err != nil {
+		t.Fatalf("releaseReminders() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("releaseReminders() = %v, want %v", got, want)
+	}
+}
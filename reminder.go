@@ -0,0 +1,95 @@
+package main
+
+import (
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// reminderConfig is the follow-up of failed builds, sent in the thread of the
+// failure message unless a later build with the same key succeeds.
+type reminderConfig struct {
+	Hours int
+	Text  string
+	Key   string
+}
+
+// reminderState is the scheduled reminder, cached in the state dir.
+type reminderState struct {
+	Channel            string `json:"channel"`
+	ScheduledMessageID string `json:"scheduled_message_id"`
+}
+
+func reminderStatePath(conf config) string {
+	return statePath(conf.StateDir, "reminder", conf.Reminder.Key)
+}
+
+// updateReminder schedules the reminder of a failed build, replacing the
+// pending reminder of the key, and cancels the pending reminder once a build
+// succeeds. Aborted builds leave the reminder as is. Failing to do so is not
+// fatal as the message is already posted.
+func updateReminder(conf config, response *SendMessageResponse) {
+	if conf.Aborted {
+		return
+	}
+	cancelReminder(conf)
+	if conf.Success {
+		return
+	}
+
+	if response == nil || response.Timestamp == "" {
+		log.Warnf("The reminder can only be scheduled for a message sent with an API token")
+		return
+	}
+	postAt := time.Now().Add(time.Duration(conf.Reminder.Hours) * time.Hour)
+	params := url.Values{
+		"channel":   {response.Channel},
+		"thread_ts": {response.Timestamp},
+		"post_at":   {strconv.FormatInt(postAt.Unix(), 10)},
+		"text":      {os.ExpandEnv(conf.Reminder.Text)},
+	}
+	var resp struct {
+		Channel            string `json:"channel"`
+		ScheduledMessageID string `json:"scheduled_message_id"`
+	}
+	if err := callAPI(string(conf.APIToken), "chat.scheduleMessage", params, &resp); err != nil {
+		log.Warnf("Failed to schedule the reminder: %s", err)
+		return
+	}
+	log.Infof("Scheduled a reminder for %s", postAt.Format(time.RFC3339))
+
+	if err := saveState(reminderStatePath(conf), reminderState{Channel: resp.Channel, ScheduledMessageID: resp.ScheduledMessageID}); err != nil {
+		log.Warnf("Failed to save the reminder: %s", err)
+	}
+}
+
+// cancelReminder deletes the pending reminder of the key, if any.
+func cancelReminder(conf config) {
+	pth := reminderStatePath(conf)
+	var state reminderState
+	found, err := loadState(pth, &state)
+	if err != nil {
+		log.Warnf("Failed to load the pending reminder: %s", err)
+		return
+	}
+	if !found {
+		return
+	}
+
+	params := url.Values{"channel": {state.Channel}, "scheduled_message_id": {state.ScheduledMessageID}}
+	err = callAPI(string(conf.APIToken), "chat.deleteScheduledMessage", params, nil)
+	// The reminder is already sent or deleted.
+	if err != nil && !isAPIError(err, "invalid_scheduled_message_id") {
+		log.Warnf("Failed to cancel the pending reminder: %s", err)
+		return
+	}
+	if err == nil {
+		log.Infof("Cancelled the pending reminder")
+	}
+	if err := os.Remove(pth); err != nil {
+		log.Warnf("Failed to remove the pending reminder: %s", err)
+	}
+}
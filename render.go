@@ -0,0 +1,249 @@
+package main
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	mrkdwnLinkPattern   = regexp.MustCompile(`<([^<>|\s]+)(?:\|([^<>]+))?>`)
+	mrkdwnCodePattern   = regexp.MustCompile("`([^`\n]+)`")
+	mrkdwnBoldPattern   = regexp.MustCompile(`\*([^*\n]+)\*`)
+	mrkdwnStrikePattern = regexp.MustCompile(`~([^~\n]+)~`)
+	// Italic requires word boundaries, so identifiers like my_app_name are kept.
+	mrkdwnItalicPattern = regexp.MustCompile(`(^|[\s(>])_([^_\n]+)_($|[\s).,!?:;<])`)
+)
+
+// mrkdwnConverter converts Slack mrkdwn to the markup of another chat backend.
+// Code blocks, links and inline code are converted separately, so their content
+// is not formatted.
+type mrkdwnConverter struct {
+	codeBlock func(code string) string
+	link      func(target, label string) string
+	code      func(code string) string
+	text      func(s string) string
+}
+
+func (c mrkdwnConverter) convert(s string) string {
+	parts := strings.Split(s, "```")
+	for i, part := range parts {
+		if i%2 == 1 && i < len(parts)-1 {
+			parts[i] = c.codeBlock(strings.Trim(part, "\n"))
+			continue
+		}
+
+		var b strings.Builder
+		last := 0
+		for _, m := range mrkdwnLinkPattern.FindAllStringSubmatchIndex(part, -1) {
+			b.WriteString(c.inline(part[last:m[0]]))
+			target, label := part[m[2]:m[3]], ""
+			if m[4] >= 0 {
+				label = part[m[4]:m[5]]
+			}
+			b.WriteString(c.link(target, label))
+			last = m[1]
+		}
+		b.WriteString(c.inline(part[last:]))
+		parts[i] = b.String()
+	}
+	// An unclosed code block is kept as text.
+	if len(parts)%2 == 0 {
+		parts[len(parts)-2] += "```" + parts[len(parts)-1]
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, "")
+}
+
+func (c mrkdwnConverter) inline(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range mrkdwnCodePattern.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(c.text(s[last:m[0]]))
+		b.WriteString(c.code(s[m[2]:m[3]]))
+		last = m[1]
+	}
+	b.WriteString(c.text(s[last:]))
+	return b.String()
+}
+
+// isSlackReference reports whether the link target is a mention or a channel
+// reference, which only work in Slack and are kept as plain text.
+func isSlackReference(target string) bool {
+	return strings.HasPrefix(target, "!") || strings.HasPrefix(target, "@") || strings.HasPrefix(target, "#")
+}
+
+var htmlConverter = mrkdwnConverter{
+	codeBlock: func(code string) string { return "<pre>" + html.EscapeString(code) + "</pre>" },
+	link:      mrkdwnLinkToHTML,
+	code:      func(code string) string { return "<code>" + html.EscapeString(code) + "</code>" },
+	text:      formatMrkdwnToHTML,
+}
+
+// mrkdwnToHTML converts Slack mrkdwn to the HTML subset supported by chat
+// backends like Telegram and Matrix: bold, italic, strikethrough, inline code,
+// code blocks and links. Line breaks are kept as newlines.
+func mrkdwnToHTML(s string) string {
+	return htmlConverter.convert(s)
+}
+
+// mrkdwnLinkToHTML converts a <target|label> link. Mentions and channel
+// references are kept as plain text, as they only work in Slack.
+func mrkdwnLinkToHTML(target, label string) string {
+	switch {
+	case isSlackReference(target):
+		if label == "" {
+			label = target
+		}
+		return html.EscapeString(label)
+	case label == "":
+		label = target
+	}
+	return `<a href="` + html.EscapeString(target) + `">` + html.EscapeString(label) + "</a>"
+}
+
+// formatMrkdwnToHTML escapes the text and converts the inline mrkdwn formatting.
+func formatMrkdwnToHTML(s string) string {
+	s = html.EscapeString(s)
+	s = mrkdwnBoldPattern.ReplaceAllString(s, "<b>$1</b>")
+	s = mrkdwnStrikePattern.ReplaceAllString(s, "<s>$1</s>")
+	// Adjacent italic parts share the separator, so a second pass is needed.
+	for i := 0; i < 2; i++ {
+		s = mrkdwnItalicPattern.ReplaceAllString(s, "$1<i>$2</i>$3")
+	}
+	return s
+}
+
+// messageHTML renders the message text and its attachments as HTML, see mrkdwnToHTML.
+// Messages with blocks are rendered from their plain text.
+func messageHTML(msg Message) string {
+	if len(msg.Blocks) > 0 {
+		return mrkdwnToHTML(msg.PlainText())
+	}
+
+	var parts []string
+	if msg.Text != "" {
+		parts = append(parts, mrkdwnToHTML(msg.Text))
+	}
+	for _, a := range msg.Attachments {
+		if a.PreText != "" {
+			parts = append(parts, mrkdwnToHTML(a.PreText))
+		}
+		if a.AuthorName != "" {
+			parts = append(parts, html.EscapeString(a.AuthorName))
+		}
+		switch {
+		case a.Title != "" && a.TitleLink != "":
+			parts = append(parts, `<b><a href="`+html.EscapeString(a.TitleLink)+`">`+html.EscapeString(a.Title)+"</a></b>")
+		case a.Title != "":
+			parts = append(parts, "<b>"+html.EscapeString(a.Title)+"</b>")
+		}
+		if a.Text != "" {
+			parts = append(parts, mrkdwnToHTML(a.Text))
+		}
+		for _, f := range a.Fields {
+			parts = append(parts, "<b>"+html.EscapeString(f.Title)+"</b>: "+mrkdwnToHTML(f.Value))
+		}
+		var buttons []string
+		for _, b := range a.Buttons {
+			buttons = append(buttons, mrkdwnLinkToHTML(b.URL, b.Text))
+		}
+		if len(buttons) > 0 {
+			parts = append(parts, strings.Join(buttons, " | "))
+		}
+		if a.Footer != "" {
+			parts = append(parts, "<i>"+html.EscapeString(a.Footer)+"</i>")
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+var markdownConverter = mrkdwnConverter{
+	codeBlock: func(code string) string { return "```\n" + code + "\n```" },
+	link:      mrkdwnLinkToMarkdown,
+	code:      func(code string) string { return "`" + code + "`" },
+	text:      formatMrkdwnToMarkdown,
+}
+
+// mrkdwnToMarkdown converts Slack mrkdwn to Markdown, as used by Webex and Zulip.
+func mrkdwnToMarkdown(s string) string {
+	return markdownConverter.convert(s)
+}
+
+// mrkdwnLinkToMarkdown converts a <target|label> link, see mrkdwnLinkToHTML.
+func mrkdwnLinkToMarkdown(target, label string) string {
+	switch {
+	case isSlackReference(target):
+		if label == "" {
+			label = target
+		}
+		return label
+	case label == "":
+		return "<" + target + ">"
+	}
+	return "[" + label + "](" + target + ")"
+}
+
+// formatMrkdwnToMarkdown converts the inline mrkdwn formatting.
+func formatMrkdwnToMarkdown(s string) string {
+	// Bold is marked with a placeholder first, so it is not converted to italic.
+	const bold = "\x00"
+	s = mrkdwnBoldPattern.ReplaceAllString(s, bold+"$1"+bold)
+	s = mrkdwnStrikePattern.ReplaceAllString(s, "~~$1~~")
+	for i := 0; i < 2; i++ {
+		s = mrkdwnItalicPattern.ReplaceAllString(s, "$1*$2*$3")
+	}
+	return strings.Replace(s, bold, "**", -1)
+}
+
+// messageMarkdown renders the message text and its attachments as Markdown, see messageHTML.
+func messageMarkdown(msg Message) string {
+	if len(msg.Blocks) > 0 {
+		return mrkdwnToMarkdown(msg.PlainText())
+	}
+
+	var parts []string
+	if msg.Text != "" {
+		parts = append(parts, mrkdwnToMarkdown(msg.Text))
+	}
+	for _, a := range msg.Attachments {
+		if a.PreText != "" {
+			parts = append(parts, mrkdwnToMarkdown(a.PreText))
+		}
+		if a.AuthorName != "" {
+			parts = append(parts, a.AuthorName)
+		}
+		switch {
+		case a.Title != "" && a.TitleLink != "":
+			parts = append(parts, "**["+a.Title+"]("+a.TitleLink+")**")
+		case a.Title != "":
+			parts = append(parts, "**"+a.Title+"**")
+		}
+		if a.Text != "" {
+			parts = append(parts, mrkdwnToMarkdown(a.Text))
+		}
+		for _, f := range a.Fields {
+			parts = append(parts, "**"+f.Title+"**: "+mrkdwnToMarkdown(f.Value))
+		}
+		var buttons []string
+		for _, b := range a.Buttons {
+			buttons = append(buttons, mrkdwnLinkToMarkdown(b.URL, b.Text))
+		}
+		if len(buttons) > 0 {
+			parts = append(parts, strings.Join(buttons, " | "))
+		}
+		if a.Footer != "" {
+			parts = append(parts, "*"+a.Footer+"*")
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// messageTitle returns the title of the message for the backends which show it
+// separately: the attachment title or the first line of the text.
+func messageTitle(msg Message) string {
+	if len(msg.Attachments) > 0 && msg.Attachments[0].Title != "" {
+		return msg.Attachments[0].Title
+	}
+	return strings.SplitN(msg.PlainText(), "\n", 2)[0]
+}
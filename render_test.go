@@ -0,0 +1,86 @@
+package main
+
+import "testing"
+
+func Test_mrkdwnToHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "Formatting", s: "*Build Succeeded!* _on time_ ~not~ `make test`", want: "<b>Build Succeeded!</b> <i>on time</i> <s>not</s> <code>make test</code>"},
+		{name: "Identifiers are not italic", s: "my_app_name", want: "my_app_name"},
+		{name: "Escaping", s: "a < b & c", want: "a &lt; b &amp; c"},
+		{name: "Links", s: "<https://app.bitrise.io/build/1|View build> <https://example.com>", want: `<a href="https://app.bitrise.io/build/1">View build</a> <a href="https://example.com">https://example.com</a>`},
+		{name: "Mentions", s: "<!here> <@U123|alice>", want: "!here alice"},
+		{name: "Code block", s: "Log:\n```\n*raw* <b>\n```", want: "Log:\n<pre>*raw* &lt;b&gt;</pre>"},
+		{name: "Unclosed code block", s: "a ``` b", want: "a ``` b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mrkdwnToHTML(tt.s); got != tt.want {
+				t.Errorf("mrkdwnToHTML() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_messageHTML(t *testing.T) {
+	msg := Message{
+		Attachments: []Attachment{{
+			PreText:   "*Build Succeeded!*",
+			Title:     "Build #12",
+			TitleLink: "https://app.bitrise.io/build/12",
+			Text:      "Fix login",
+			Fields:    []Field{{Title: "App", Value: "Example"}},
+			Buttons:   []Button{{Text: "View App", URL: "https://app.bitrise.io/app/1"}},
+			Footer:    "Bitrise",
+		}},
+	}
+	want := "<b>Build Succeeded!</b>\n" +
+		`<b><a href="https://app.bitrise.io/build/12">Build #12</a></b>` + "\n" +
+		"Fix login\n" +
+		"<b>App</b>: Example\n" +
+		`<a href="https://app.bitrise.io/app/1">View App</a>` + "\n" +
+		"<i>Bitrise</i>"
+	if got := messageHTML(msg); got != want {
+		t.Errorf("messageHTML() = %v, want %v", got, want)
+	}
+}
+
+func Test_mrkdwnToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "Formatting", s: "*Build Succeeded!* _on time_ ~not~ `*make* test`", want: "**Build Succeeded!** *on time* ~~not~~ `*make* test`"},
+		{name: "Identifiers are not italic", s: "my_app_name", want: "my_app_name"},
+		{name: "Links", s: "<https://app.bitrise.io/build/1|View build> <https://example.com>", want: "[View build](https://app.bitrise.io/build/1) <https://example.com>"},
+		{name: "Mentions", s: "<!here> <#C123|builds>", want: "!here builds"},
+		{name: "Code block", s: "Log:\n```*raw*```", want: "Log:\n```\n*raw*\n```"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mrkdwnToMarkdown(tt.s); got != tt.want {
+				t.Errorf("mrkdwnToMarkdown() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_messageMarkdown(t *testing.T) {
+	msg := Message{
+		Attachments: []Attachment{{
+			PreText:   "*Build Succeeded!*",
+			Title:     "Build #12",
+			TitleLink: "https://app.bitrise.io/build/12",
+			Fields:    []Field{{Title: "App", Value: "Example"}},
+			Footer:    "Bitrise",
+		}},
+	}
+	want := "**Build Succeeded!**\n**[Build #12](https://app.bitrise.io/build/12)**\n**App**: Example\n*Bitrise*"
+	if got := messageMarkdown(msg); got != want {
+		t.Errorf("messageMarkdown() = %v, want %v", got, want)
+	}
+}
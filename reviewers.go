@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// defaultGitLabAPIURL is the API of gitlab.com.
+const defaultGitLabAPIURL = "https://gitlab.com/api/v4"
+
+// gitlabConfig is the merge request the reviewers are fetched from.
+type gitlabConfig struct {
+	Token        string
+	APIURL       string
+	Project      string
+	MergeRequest string
+}
+
+// gitlabProject returns the path of the project, from the input or from the
+// path of the repository URL, eg. group/subgroup/repo.
+func gitlabProject(project, repositoryURL string) string {
+	if project = strings.Trim(strings.TrimSpace(project), "/"); project != "" {
+		return project
+	}
+
+	webURL := repositoryWebURL(repositoryURL)
+	if webURL == "" {
+		return ""
+	}
+	u, err := url.Parse(webURL)
+	if err != nil {
+		return ""
+	}
+	return strings.Trim(u.Path, "/")
+}
+
+// fetchGitHubReviewers returns the usernames of the requested reviewers of the pull request.
+func fetchGitHubReviewers(conf githubConfig) ([]string, error) {
+	var resp struct {
+		Users []struct {
+			Login string `json:"login"`
+		} `json:"users"`
+	}
+	endpoint := fmt.Sprintf("/pulls/%s/requested_reviewers", url.PathEscape(conf.PullRequest))
+	if err := getJSON(githubAPIURL(conf, endpoint), "Bearer "+conf.Token, &resp); err != nil {
+		return nil, err
+	}
+
+	var usernames []string
+	for _, u := range resp.Users {
+		usernames = append(usernames, u.Login)
+	}
+	return usernames, nil
+}
+
+// fetchGitLabReviewers returns the usernames of the reviewers of the merge request.
+func fetchGitLabReviewers(conf gitlabConfig) ([]string, error) {
+	var resp struct {
+		Reviewers []struct {
+			Username string `json:"username"`
+		} `json:"reviewers"`
+	}
+	base := strings.TrimSpace(conf.APIURL)
+	if base == "" {
+		base = defaultGitLabAPIURL
+	}
+	endpoint := fmt.Sprintf("%s/projects/%s/merge_requests/%s", strings.TrimSuffix(base, "/"), url.PathEscape(conf.Project), url.PathEscape(conf.MergeRequest))
+	if err := getJSON(endpoint, "Bearer "+conf.Token, &resp); err != nil {
+		return nil, err
+	}
+
+	var usernames []string
+	for _, r := range resp.Reviewers {
+		usernames = append(usernames, r.Username)
+	}
+	return usernames, nil
+}
+
+// fetchReviewers returns the usernames of the reviewers of the pull request of
+// the build, from GitHub if its token is set, otherwise from GitLab. It returns
+// nil if the build is not a pull request build.
+func fetchReviewers(conf config) ([]string, error) {
+	if conf.GitHub.Token != "" {
+		if conf.GitHub.PullRequest == "" {
+			return nil, nil
+		}
+		return fetchGitHubReviewers(conf.GitHub)
+	}
+	if conf.GitLab.MergeRequest == "" {
+		return nil, nil
+	}
+	return fetchGitLabReviewers(conf.GitLab)
+}
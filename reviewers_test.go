@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_gitlabProject(t *testing.T) {
+	tests := []struct {
+		name          string
+		project       string
+		repositoryURL string
+		want          string
+	}{
+		{name: "Input", project: "/group/repo/", repositoryURL: "git@gitlab.com:other/repo.git", want: "group/repo"},
+		{name: "Nested group", repositoryURL: "git@gitlab.com:group/subgroup/repo.git", want: "group/subgroup/repo"},
+		{name: "Empty", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gitlabProject(tt.project, tt.repositoryURL); got != tt.want {
+				t.Errorf("gitlabProject() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_fetchReviewers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/repos/org/app/pulls/12/requested_reviewers":
+			if got := r.Header.Get("Authorization"); got != "Bearer gh-token" {
+				t.Errorf("Authorization = %v, want %v", got, "Bearer gh-token")
+			}
+			_, _ = w.Write([]byte(`{"users": [{"login": "jane-doe"}, {"login": "john"}], "teams": []}`))
+		case "/projects/group%2Fapp/merge_requests/7":
+			_, _ = w.Write([]byte(`{"iid": 7, "reviewers": [{"username": "jane.doe"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		conf config
+		want []string
+	}{
+		{
+			name: "GitHub",
+			conf: config{GitHub: githubConfig{Token: "gh-token", APIURL: server.URL, Repository: "org/app", PullRequest: "12"}},
+			want: []string{"jane-doe", "john"},
+		},
+		{
+			name: "GitLab",
+			conf: config{GitLab: gitlabConfig{Token: "gl-token", APIURL: server.URL, Project: "group/app", MergeRequest: "7"}},
+			want: []string{"jane.doe"},
+		},
+		{
+			name: "Not a pull request build",
+			conf: config{GitHub: githubConfig{Token: "gh-token", APIURL: server.URL, Repository: "org/app"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fetchReviewers(tt.conf)
+			if err != nil {
+				t.Fatalf("fetchReviewers() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fetchReviewers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
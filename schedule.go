@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// parseScheduleAt parses an RFC3339 timestamp or a duration relative to now,
+// prefixed by a plus sign (e.g. +2h30m).
+func parseScheduleAt(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "+") {
+		d, err := time.ParseDuration(s[1:])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid relative schedule time (%s): %s", s, err)
+		}
+		return now.Add(d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid schedule time (%s), use RFC3339 (2006-01-02T15:04:05+07:00) or a relative time (+2h)", s)
+	}
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("schedule time (%s) is in the past", s)
+	}
+	return t, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_parseScheduleAt(t *testing.T) {
+	now := time.Date(2023, 3, 1, 3, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		s       string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "Relative",
+			s:    "+6h",
+			want: time.Date(2023, 3, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "RFC3339",
+			s:    "2023-03-01T09:00:00+01:00",
+			want: time.Date(2023, 3, 1, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "In the past",
+			s:       "2023-02-28T09:00:00Z",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid",
+			s:       "tomorrow",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseScheduleAt(tt.s, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseScheduleAt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseScheduleAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// findScreenshots returns the first limit PNG files of the directory, by name.
+func findScreenshots(dir string, limit int) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.png"))
+	if err != nil {
+		return nil, err
+	}
+	upper, err := filepath.Glob(filepath.Join(dir, "*.PNG"))
+	if err != nil {
+		return nil, err
+	}
+	paths = append(paths, upper...)
+	sort.Strings(paths)
+
+	if limit > 0 && len(paths) > limit {
+		log.Warnf("Found %d screenshots, only the first %d are uploaded", len(paths), limit)
+		paths = paths[:limit]
+	}
+	return paths, nil
+}
+
+// uploadFile uploads the file to Slack with the external upload flow and
+// returns the ID of the file. The file is shared by completeUploads.
+func uploadFile(token, pth string) (string, error) {
+	b, err := os.ReadFile(pth)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %s", pth, err)
+	}
+
+	var upload struct {
+		UploadURL string `json:"upload_url"`
+		FileID    string `json:"file_id"`
+	}
+	params := url.Values{
+		"filename": {filepath.Base(pth)},
+		"length":   {strconv.Itoa(len(b))},
+	}
+	if err := callAPI(token, "files.getUploadURLExternal", params, &upload); err != nil {
+		return "", err
+	}
+
+	resp, err := httpClient.Post(upload.UploadURL, "application/octet-stream", bytes.NewReader(b))
+	if err != nil {
+		return "", fmt.Errorf("failed to upload %s: %s", pth, err)
+	}
+	if err := resp.Body.Close(); err != nil {
+		log.Warnf("Failed to close response body: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to upload %s: %s", pth, resp.Status)
+	}
+	return upload.FileID, nil
+}
+
+// uploadScreenshots uploads the screenshots of the directory and shares them in
+// the thread of the message.
+func uploadScreenshots(conf config, channel, threadTs string) error {
+	paths, err := findScreenshots(conf.ScreenshotsDir, conf.ScreenshotsLimit)
+	if err != nil {
+		return fmt.Errorf("failed to find screenshots: %s", err)
+	}
+	if len(paths) == 0 {
+		log.Debugf("No screenshots found in %s", conf.ScreenshotsDir)
+		return nil
+	}
+
+	type uploadedFile struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	}
+	var files []uploadedFile
+	token := string(conf.APIToken)
+	for _, pth := range paths {
+		id, err := uploadFile(token, pth)
+		if err != nil {
+			return err
+		}
+		files = append(files, uploadedFile{ID: id, Title: strings.TrimSuffix(filepath.Base(pth), filepath.Ext(pth))})
+	}
+
+	b, err := json.Marshal(files)
+	if err != nil {
+		return err
+	}
+	params := url.Values{
+		"files":      {string(b)},
+		"channel_id": {channel},
+		"thread_ts":  {threadTs},
+	}
+	if err := callAPI(token, "files.completeUploadExternal", params, nil); err != nil {
+		return err
+	}
+	log.Infof("Uploaded %d screenshots to the thread of the message", len(files))
+	return nil
+}
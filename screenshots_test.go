@@ -0,0 +1,26 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_findScreenshots(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"c_diff.png", "a_diff.png", "b_diff.PNG", "report.html"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := findScreenshots(dir, 2)
+	if err != nil {
+		t.Fatalf("findScreenshots() error = %v", err)
+	}
+	want := []string{filepath.Join(dir, "a_diff.png"), filepath.Join(dir, "b_diff.PNG")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findScreenshots() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bitrise-tools/go-steputils/stepconf"
+)
+
+// readSecretFile returns the secret stored in the file at pth, without the
+// surrounding whitespace.
+func readSecretFile(pth string) (stepconf.Secret, error) {
+	b, err := os.ReadFile(strings.TrimSpace(pth))
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %s", err)
+	}
+
+	secret := strings.TrimSpace(string(b))
+	if secret == "" {
+		return "", fmt.Errorf("secret file %s is empty", pth)
+	}
+	return stepconf.Secret(secret), nil
+}
+
+// loadSecretFiles reads the webhook URL and the API token from the secret
+// files, for runners which provide secrets as files.
+func loadSecretFiles(inp *Input) error {
+	for _, s := range []struct {
+		name  string
+		file  string
+		value *stepconf.Secret
+	}{
+		{"Webhook URL", inp.WebhookURLFile, &inp.WebhookURL},
+		{"API Token", inp.APITokenFile, &inp.APIToken},
+	} {
+		if strings.TrimSpace(s.file) == "" {
+			continue
+		}
+		if *s.value != "" {
+			return fmt.Errorf("Provide the %s either as a value or as a file, not both", s.name)
+		}
+
+		secret, err := readSecretFile(s.file)
+		if err != nil {
+			return fmt.Errorf("Failed to read the %s: %s", s.name, err)
+		}
+		*s.value = secret
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_loadSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("xoxb-1234\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, []byte("\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		inp       Input
+		wantToken string
+		wantErr   bool
+	}{
+		{name: "Token from file", inp: Input{APITokenFile: tokenFile}, wantToken: "xoxb-1234"},
+		{name: "No files", inp: Input{APIToken: "xoxb-5678"}, wantToken: "xoxb-5678"},
+		{name: "Both value and file", inp: Input{APIToken: "xoxb-5678", APITokenFile: tokenFile}, wantErr: true},
+		{name: "Empty file", inp: Input{WebhookURLFile: emptyFile}, wantErr: true},
+		{name: "Missing file", inp: Input{WebhookURLFile: filepath.Join(dir, "missing")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loadSecretFiles(&tt.inp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadSecretFiles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(tt.inp.APIToken) != tt.wantToken {
+				t.Errorf("loadSecretFiles() token = %v, want %v", tt.inp.APIToken, tt.wantToken)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// apiResponse is the common part of the Slack Web API responses.
+type apiResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// apiError is returned when a Slack Web API method responds with ok: false.
+type apiError struct {
+	Method string
+	Code   string
+}
+
+// Error implements builtin errors.Error.
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s failed: %s", e.Method, e.Code)
+}
+
+// isAPIError reports whether err is an apiError with the given error code.
+func isAPIError(err error, code string) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// callAPI calls a Slack Web API method with form encoded params and decodes the
+// response into out (if not nil).
+func callAPI(token, method string, params url.Values, out interface{}) error {
+	req, err := http.NewRequest("POST", slackAPIURL+method, strings.NewReader(params.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("Authorization", "Bearer "+token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %s", method, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Warnf("Failed to close response body: %s", err)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %s", method, err)
+	}
+	log.Debugf("Response of %s: %s\n", method, body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s failed: %s, response: %s", method, resp.Status, body)
+	}
+
+	var r apiResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return fmt.Errorf("failed to parse %s response: %s", method, err)
+	}
+	if !r.OK {
+		return &apiError{Method: method, Code: r.Error}
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("failed to parse %s response: %s", method, err)
+		}
+	}
+	return nil
+}
+
+// isEmail reports whether the channel input looks like an email address.
+func isEmail(s string) bool {
+	at := strings.Index(s, "@")
+	return at > 0 && strings.Contains(s[at+1:], ".")
+}
+
+// lookupUserByEmail returns the ID of the user with the given email address.
+func lookupUserByEmail(token, email string) (string, error) {
+	var resp struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	if err := callAPI(token, "users.lookupByEmail", url.Values{"email": {email}}, &resp); err != nil {
+		return "", err
+	}
+	return resp.User.ID, nil
+}
+
+// lookupUserByHandle returns the ID of the user whose username or display name
+// is handle, compared case insensitively.
+func lookupUserByHandle(token, handle string) (string, error) {
+	params := url.Values{"limit": {"200"}}
+	for {
+		var resp struct {
+			Members []struct {
+				ID      string `json:"id"`
+				Name    string `json:"name"`
+				Deleted bool   `json:"deleted"`
+				Profile struct {
+					DisplayName string `json:"display_name"`
+				} `json:"profile"`
+			} `json:"members"`
+			ResponseMetadata struct {
+				NextCursor string `json:"next_cursor"`
+			} `json:"response_metadata"`
+		}
+		if err := callAPI(token, "users.list", params, &resp); err != nil {
+			return "", err
+		}
+
+		for _, m := range resp.Members {
+			if !m.Deleted && (strings.EqualFold(m.Name, handle) || strings.EqualFold(m.Profile.DisplayName, handle)) {
+				return m.ID, nil
+			}
+		}
+
+		if resp.ResponseMetadata.NextCursor == "" {
+			return "", fmt.Errorf("no user found with the handle @%s", handle)
+		}
+		params.Set("cursor", resp.ResponseMetadata.NextCursor)
+	}
+}
+
+// openDirectMessage opens a direct message conversation with the user and returns its ID.
+func openDirectMessage(token, userID string) (string, error) {
+	var resp struct {
+		Channel struct {
+			ID string `json:"id"`
+		} `json:"channel"`
+	}
+	if err := callAPI(token, "conversations.open", url.Values{"users": {userID}}, &resp); err != nil {
+		return "", err
+	}
+	return resp.Channel.ID, nil
+}
+
+// resolveDirectMessageChannel returns the direct message channel ID if the channel
+// is an email address or an @handle, otherwise the channel is returned unchanged.
+func resolveDirectMessageChannel(token, channel string) (string, error) {
+	var userID string
+	var err error
+	switch {
+	case isEmail(channel):
+		userID, err = lookupUserByEmail(token, channel)
+	case strings.HasPrefix(channel, "@"):
+		userID, err = lookupUserByHandle(token, strings.TrimPrefix(channel, "@"))
+	default:
+		return channel, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find the user %s: %s", channel, err)
+	}
+
+	dm, err := openDirectMessage(token, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to open a direct message with %s: %s", channel, err)
+	}
+	log.Debugf("Sending direct message to %s in %s", channel, dm)
+	return dm, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_resolveDirectMessageChannel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm() error = %v", err)
+		}
+		switch r.URL.Path {
+		case "/users.list":
+			// The members are listed on two pages.
+			if r.Form.Get("cursor") == "" {
+				_, _ = w.Write([]byte(`{"ok": true, "members": [
+  {"id": "U001", "name": "john", "profile": {"display_name": "John"}},
+  {"id": "U002", "name": "jane.old", "deleted": true, "profile": {"display_name": "Jane Doe"}}
+], "response_metadata": {"next_cursor": "page2"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok": true, "members": [
+  {"id": "U003", "name": "jane", "profile": {"display_name": "Jane Doe"}}
+], "response_metadata": {"next_cursor": ""}}`))
+		case "/users.lookupByEmail":
+			_, _ = w.Write([]byte(`{"ok": true, "user": {"id": "U004"}}`))
+		case "/conversations.open":
+			_, _ = w.Write([]byte(`{"ok": true, "channel": {"id": "D` + r.Form.Get("users") + `"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	apiURL := slackAPIURL
+	slackAPIURL = server.URL + "/"
+	defer func() { slackAPIURL = apiURL }()
+
+	tests := []struct {
+		name    string
+		channel string
+		want    string
+		wantErr bool
+	}{
+		{name: "Channel", channel: "#builds", want: "#builds"},
+		{name: "Username on the first page", channel: "@john", want: "DU001"},
+		{name: "Display name on the next page", channel: "@jane doe", want: "DU003"},
+		{name: "Email", channel: "jane@example.com", want: "DU004"},
+		{name: "Unknown handle", channel: "@nobody", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveDirectMessageChannel("xoxb-token", tt.channel)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveDirectMessageChannel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveDirectMessageChannel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// snsSubjectMaxLength is the limit of the subject used by email subscriptions.
+const snsSubjectMaxLength = 100
+
+func init() {
+	registerProvider("sns", snsProvider{})
+}
+
+// snsProvider publishes the message as plain text to an AWS SNS topic, e.g. to
+// reach Amazon Chime or the alerting subscribed to the topic.
+type snsProvider struct{}
+
+// snsConfig is the topic of the sns provider. The credentials are read from the
+// standard AWS environment variables.
+type snsConfig struct {
+	TopicARN string
+	Region   string
+}
+
+// awsCredentials are the credentials used to sign AWS requests.
+type awsCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// awsCredentialsFromEnv reads the credentials from the AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables.
+func awsCredentialsFromEnv() (awsCredentials, error) {
+	creds := awsCredentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return creds, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required to publish to SNS")
+	}
+	return creds, nil
+}
+
+// snsRegion returns the region, defaulting to the region of the topic ARN
+// (arn:aws:sns:<region>:<account>:<topic>).
+func snsRegion(region, topicARN string) string {
+	if region = strings.TrimSpace(region); region != "" {
+		return region
+	}
+	parts := strings.Split(topicARN, ":")
+	if len(parts) < 6 || parts[0] != "arn" || parts[2] != "sns" {
+		return ""
+	}
+	return parts[3]
+}
+
+// snsSubject returns the title of the message, limited to what SNS accepts.
+func snsSubject(msg Message) string {
+	// The subject must be printable ASCII.
+	subject := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return -1
+		}
+		return r
+	}, messageTitle(msg))
+	subject = strings.TrimSpace(subject)
+	if len(subject) > snsSubjectMaxLength {
+		subject = strings.TrimSpace(subject[:snsSubjectMaxLength-3]) + "..."
+	}
+	return subject
+}
+
+// newSNSPublishParams returns the parameters of the Publish action.
+func newSNSPublishParams(topicARN string, msg Message) url.Values {
+	params := url.Values{
+		"Action":   {"Publish"},
+		"Version":  {"2010-03-31"},
+		"TopicArn": {topicARN},
+		"Message":  {msg.PlainText()},
+	}
+	if subject := snsSubject(msg); subject != "" {
+		params.Set("Subject", subject)
+	}
+	return params
+}
+
+// Send implements Provider.
+func (snsProvider) Send(conf config, msg Message) (*SendMessageResponse, error) {
+	creds, err := awsCredentialsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	region := snsRegion(conf.SNS.Region, conf.SNS.TopicARN)
+
+	body := newSNSPublishParams(conf.SNS.TopicARN, msg).Encode()
+	log.Debugf("Request to SNS: %s\n", body)
+	req, err := http.NewRequest("POST", "https://sns."+region+".amazonaws.com/", strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signAWSRequest(req, []byte(body), "sns", region, creds, time.Now())
+
+	resp, err := sendProviderRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Response from SNS: %s\n", resp)
+	return nil, nil
+}
+
+// signAWSRequest adds the AWS Signature Version 4 headers to the request.
+// See also: https://docs.aws.amazon.com/general/latest/gr/sigv4_signing.html
+func signAWSRequest(req *http.Request, body []byte, service, region string, creds awsCredentials, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for key, values := range req.Header {
+		headers[strings.ToLower(key)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		sha256Hex(body),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	for _, s := range []string{region, service, "aws4_request"} {
+		key = hmacSHA256(key, s)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, s string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(s))
+	return mac.Sum(nil)
+}
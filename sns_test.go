@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_signAWSRequest(t *testing.T) {
+	// The get-vanilla case of the AWS Signature Version 4 test suite.
+	req, err := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds := awsCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+	signAWSRequest(req, nil, "service", "us-east-1", creds, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("signAWSRequest() Authorization = %v, want %v", got, want)
+	}
+}
+
+func Test_snsRegion(t *testing.T) {
+	tests := []struct {
+		name     string
+		region   string
+		topicARN string
+		want     string
+	}{
+		{name: "From the ARN", topicARN: "arn:aws:sns:eu-west-1:123456789012:builds", want: "eu-west-1"},
+		{name: "Explicit region", region: "us-east-2", topicARN: "arn:aws:sns:eu-west-1:123456789012:builds", want: "us-east-2"},
+		{name: "Invalid ARN", topicARN: "builds", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snsRegion(tt.region, tt.topicARN); got != tt.want {
+				t.Errorf("snsRegion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_snsSubject(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  Message
+		want string
+	}{
+		{name: "Attachment title", msg: Message{Text: "Fix login", Attachments: []Attachment{{Title: "Build #12 ✅"}}}, want: "Build #12"},
+		{name: "First line", msg: Message{Text: "Build failed\nDetails"}, want: "Build failed"},
+		{name: "Long subject", msg: Message{Text: strings.Repeat("a", 120)}, want: strings.Repeat("a", 97) + "..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snsSubject(tt.msg); got != tt.want {
+				t.Errorf("snsSubject() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
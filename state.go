@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// statePath returns the path of the state file with the given name and key,
+// stored in dir or in the temporary directory if dir is empty.
+func statePath(dir, name, key string) string {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, fmt.Sprintf("slack-message-%s-%s.json", name, unsafeFileNameChars.ReplaceAllString(key, "_")))
+}
+
+// loadState decodes the state file into v. It returns false if the file does not exist.
+func loadState(pth string, v interface{}) (bool, error) {
+	b, err := os.ReadFile(pth)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read state file: %s", err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return false, fmt.Errorf("invalid state file (%s): %s", pth, err)
+	}
+	return true, nil
+}
+
+// saveState encodes v into the state file, creating its directory if needed.
+func saveState(pth string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %s", err)
+	}
+	if err := os.WriteFile(pth, b, 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %s", err)
+	}
+	return nil
+}
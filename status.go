@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// Values of the build status override input
+const (
+	buildStatusAuto    = "auto"
+	buildStatusSuccess = "success"
+	buildStatusFailed  = "failed"
+	buildStatusAborted = "aborted"
+)
+
+// defaultSuccessValues are the build status values of a successful build.
+const defaultSuccessValues = "0"
+
+// loadBuildStatus reads the build status from the configured environment
+// variable, for builds where the status is not provided by the build_status input.
+func loadBuildStatus(inp *Input) {
+	if name := strings.TrimSpace(inp.BuildStatusEnv); name != "" {
+		inp.BuildStatus = os.Getenv(name)
+	}
+}
+
+// isSuccessStatus reports whether the build status is one of the comma
+// separated success values.
+func isSuccessStatus(status, successValues string) bool {
+	if strings.TrimSpace(successValues) == "" {
+		successValues = defaultSuccessValues
+	}
+
+	status = strings.TrimSpace(status)
+	for _, v := range splitList(successValues) {
+		if strings.EqualFold(status, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// overrideSuccess applies the build status override to the detected result of
+// the build. Aborted builds are reported as failed.
+func overrideSuccess(override string, detected bool) bool {
+	switch override {
+	case buildStatusSuccess:
+		return true
+	case buildStatusFailed, buildStatusAborted:
+		return false
+	}
+	return detected
+}
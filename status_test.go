@@ -0,0 +1,56 @@
+package main
+
+import "testing"
+
+func Test_isSuccessStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        string
+		successValues string
+		want          bool
+	}{
+		{name: "Bitrise success", status: "0", want: true},
+		{name: "Bitrise failure", status: "1", want: false},
+		{name: "Custom success values", status: "Passed", successValues: "success, passed", want: true},
+		{name: "Custom failure", status: "failed", successValues: "success,passed", want: false},
+		{name: "Empty status", status: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSuccessStatus(tt.status, tt.successValues); got != tt.want {
+				t.Errorf("isSuccessStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_loadBuildStatus(t *testing.T) {
+	t.Setenv("CI_JOB_STATUS", "success")
+
+	inp := Input{BuildStatus: "", BuildStatusEnv: "CI_JOB_STATUS"}
+	loadBuildStatus(&inp)
+	if inp.BuildStatus != "success" {
+		t.Errorf("loadBuildStatus() status = %v, want %v", inp.BuildStatus, "success")
+	}
+}
+
+func Test_overrideSuccess(t *testing.T) {
+	tests := []struct {
+		override string
+		detected bool
+		want     bool
+	}{
+		{override: buildStatusAuto, detected: true, want: true},
+		{override: buildStatusAuto, detected: false, want: false},
+		{override: buildStatusSuccess, detected: false, want: true},
+		{override: buildStatusFailed, detected: true, want: false},
+		{override: buildStatusAborted, detected: true, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.override, func(t *testing.T) {
+			if got := overrideSuccess(tt.override, tt.detected); got != tt.want {
+				t.Errorf("overrideSuccess() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// readStdinMessage reads the message from r, which replaces the message inputs.
+func readStdinMessage(inp *Input, r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read the message from the standard input: %s", err)
+	}
+
+	message := strings.TrimRight(string(b), "\r\n")
+	if strings.TrimSpace(message) == "" {
+		return fmt.Errorf("no message was piped to the standard input")
+	}
+	inp.Message = message
+	inp.MessageOnSuccess, inp.MessageOnError = "", ""
+	inp.MessageIOS, inp.MessageAndroid = "", ""
+	return nil
+}
+
+// stdinIsPipe reports whether the standard input is piped, so reading it does
+// not wait for the input of a terminal.
+func stdinIsPipe() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_readStdinMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		stdin   string
+		want    string
+		wantErr bool
+	}{
+		{name: "Piped message", stdin: "*3 tests failed*\n- LoginTests\n", want: "*3 tests failed*\n- LoginTests"},
+		{name: "Empty", stdin: "\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inp := Input{Message: "Build finished", MessageOnError: "Build failed"}
+			err := readStdinMessage(&inp, strings.NewReader(tt.stdin))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readStdinMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := [2]string{inp.Message, inp.MessageOnError}; got != [2]string{tt.want, ""} {
+				t.Errorf("readStdinMessage() = %q, want %q", got, [2]string{tt.want, ""})
+			}
+		})
+	}
+}
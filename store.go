@@ -0,0 +1,41 @@
+package main
+
+import "strings"
+
+// storeConsoles are the default consoles linked by the store-submission layout.
+var storeConsoles = map[string]struct{ name, url string }{
+	platformIOS:     {name: "App Store Connect", url: "https://appstoreconnect.apple.com/apps"},
+	platformAndroid: {name: "Play Console", url: "https://play.google.com/console"},
+}
+
+// storeSubmission describes the store submission for the store-submission layout.
+type storeSubmission struct {
+	Track        string
+	Phase        string
+	ReviewStatus string
+	ConsoleName  string
+	ConsoleURL   string
+}
+
+// newStoreSubmission returns the store submission of the build, or nil if none
+// of the details are set. The console defaults to the store of the platform.
+func newStoreSubmission(track, phase, reviewStatus, consoleURL, platform string) *storeSubmission {
+	s := storeSubmission{
+		Track:        strings.TrimSpace(track),
+		Phase:        strings.TrimSpace(phase),
+		ReviewStatus: strings.TrimSpace(reviewStatus),
+		ConsoleName:  "Store console",
+		ConsoleURL:   strings.TrimSpace(consoleURL),
+	}
+	if s.Track == "" && s.Phase == "" && s.ReviewStatus == "" && s.ConsoleURL == "" {
+		return nil
+	}
+
+	if console, ok := storeConsoles[platform]; ok {
+		s.ConsoleName = console.name
+		if s.ConsoleURL == "" {
+			s.ConsoleURL = console.url
+		}
+	}
+	return &s
+}
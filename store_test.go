@@ -0,0 +1,43 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_newStoreSubmission(t *testing.T) {
+	tests := []struct {
+		name       string
+		track      string
+		consoleURL string
+		platform   string
+		want       *storeSubmission
+	}{
+		{name: "Not submitted", platform: "ios", want: nil},
+		{
+			name:     "App Store",
+			track:    "TestFlight",
+			platform: "ios",
+			want:     &storeSubmission{Track: "TestFlight", ConsoleName: "App Store Connect", ConsoleURL: "https://appstoreconnect.apple.com/apps"},
+		},
+		{
+			name:       "Play Store with console URL",
+			track:      "internal",
+			consoleURL: "https://play.google.com/console/u/0/developers/1/app/2",
+			platform:   "android",
+			want:       &storeSubmission{Track: "internal", ConsoleName: "Play Console", ConsoleURL: "https://play.google.com/console/u/0/developers/1/app/2"},
+		},
+		{
+			name:  "Unknown platform",
+			track: "beta",
+			want:  &storeSubmission{Track: "beta", ConsoleName: "Store console"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newStoreSubmission(tt.track, "", "", tt.consoleURL, tt.platform); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newStoreSubmission() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
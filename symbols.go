@@ -0,0 +1,32 @@
+package main
+
+import "strings"
+
+// symbolUpload describes the uploaded debug symbols for the symbol-upload layout.
+type symbolUpload struct {
+	Provider string
+	Kind     string
+	UUIDs    []string
+}
+
+// newSymbolUpload returns the symbol upload of the build, or nil if no provider is set.
+// The UUIDs are separated by commas or newlines.
+func newSymbolUpload(provider, uuids, platform string) *symbolUpload {
+	provider = strings.TrimSpace(provider)
+	if provider == "" {
+		return nil
+	}
+
+	kind := "Symbols"
+	switch platform {
+	case platformIOS:
+		kind = "dSYMs"
+	case platformAndroid:
+		kind = "Mapping files"
+	}
+	return &symbolUpload{
+		Provider: provider,
+		Kind:     kind,
+		UUIDs:    splitList(strings.Replace(uuids, "\n", ",", -1)),
+	}
+}
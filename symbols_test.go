@@ -0,0 +1,38 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_newSymbolUpload(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		uuids    string
+		platform string
+		want     *symbolUpload
+	}{
+		{name: "No provider", uuids: "A1B2", want: nil},
+		{
+			name:     "dSYMs",
+			provider: "Crashlytics",
+			uuids:    "A1B2\nC3D4,\nE5F6",
+			platform: "ios",
+			want:     &symbolUpload{Provider: "Crashlytics", Kind: "dSYMs", UUIDs: []string{"A1B2", "C3D4", "E5F6"}},
+		},
+		{
+			name:     "Mapping files",
+			provider: "Sentry",
+			platform: "android",
+			want:     &symbolUpload{Provider: "Sentry", Kind: "Mapping files"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newSymbolUpload(tt.provider, tt.uuids, tt.platform); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newSymbolUpload() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+func init() {
+	registerProvider("teams", teamsProvider{})
+}
+
+// teamsProvider sends the message as a Microsoft Teams message card to an incoming webhook.
+type teamsProvider struct{}
+
+type teamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type teamsSection struct {
+	ActivityTitle string      `json:"activityTitle,omitempty"`
+	Text          string      `json:"text,omitempty"`
+	Facts         []teamsFact `json:"facts,omitempty"`
+}
+
+type teamsTarget struct {
+	OS  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+type teamsAction struct {
+	Type    string        `json:"@type"`
+	Name    string        `json:"name"`
+	Targets []teamsTarget `json:"targets"`
+}
+
+type teamsCard struct {
+	Type            string         `json:"@type"`
+	Context         string         `json:"@context"`
+	Summary         string         `json:"summary"`
+	ThemeColor      string         `json:"themeColor,omitempty"`
+	Title           string         `json:"title,omitempty"`
+	Text            string         `json:"text,omitempty"`
+	Sections        []teamsSection `json:"sections,omitempty"`
+	PotentialAction []teamsAction  `json:"potentialAction,omitempty"`
+}
+
+// newTeamsCard converts the message into a message card.
+func newTeamsCard(msg Message) teamsCard {
+	card := teamsCard{
+		Type:    "MessageCard",
+		Context: "https://schema.org/extensions",
+		Text:    msg.Text,
+	}
+
+	if len(msg.Attachments) == 0 {
+		card.Text = msg.PlainText()
+	} else {
+		a := msg.Attachments[0]
+		card.ThemeColor = strings.TrimPrefix(hexColor(a.Color), "#")
+		card.Title = a.Title
+		section := teamsSection{ActivityTitle: a.PreText, Text: a.Text}
+		for _, f := range a.Fields {
+			section.Facts = append(section.Facts, teamsFact{Name: f.Title, Value: f.Value})
+		}
+		card.Sections = []teamsSection{section}
+		for _, b := range a.Buttons {
+			card.PotentialAction = append(card.PotentialAction, teamsAction{
+				Type:    "OpenUri",
+				Name:    b.Text,
+				Targets: []teamsTarget{{OS: "default", URI: b.URL}},
+			})
+		}
+	}
+
+	card.Summary = card.Title
+	if card.Summary == "" {
+		card.Summary = strings.SplitN(msg.PlainText(), "\n", 2)[0]
+	}
+	return card
+}
+
+// Send implements Provider.
+func (teamsProvider) Send(conf config, msg Message) (*SendMessageResponse, error) {
+	b, err := json.Marshal(newTeamsCard(msg))
+	if err != nil {
+		return nil, err
+	}
+	return sendPayload(conf, b)
+}
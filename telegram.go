@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+)
+
+// telegramAPIURL is the base URL of the Telegram Bot API methods.
+const telegramAPIURL = "https://api.telegram.org/"
+
+func init() {
+	registerProvider("telegram", telegramProvider{})
+}
+
+// telegramProvider sends the message with the sendMessage method of a Telegram bot.
+type telegramProvider struct{}
+
+// telegramConfig is the bot and the chat of the telegram provider.
+type telegramConfig struct {
+	BotToken string
+	ChatID   string
+}
+
+type telegramMessage struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+// newTelegramMessage converts the message into a sendMessage request with an HTML text.
+func newTelegramMessage(chatID string, msg Message) telegramMessage {
+	return telegramMessage{
+		ChatID:                chatID,
+		Text:                  messageHTML(msg),
+		ParseMode:             "HTML",
+		DisableWebPagePreview: true,
+	}
+}
+
+// Send implements Provider.
+func (telegramProvider) Send(conf config, msg Message) (*SendMessageResponse, error) {
+	b, err := json.Marshal(newTelegramMessage(conf.Telegram.ChatID, msg))
+	if err != nil {
+		return nil, err
+	}
+
+	// The bot token is part of the method URL, the response is not decoded.
+	conf.WebhookURL = telegramAPIURL + "bot" + conf.Telegram.BotToken + "/sendMessage"
+	conf.APIToken = ""
+	return sendPayload(conf, b)
+}
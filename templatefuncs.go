@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// truncateText shortens the text to at most n characters, ending with an ellipsis
+// if it was truncated, eg. {{.Message | truncate 200}}.
+func truncateText(n int, s string) string {
+	runes := []rune(s)
+	if n < 0 || len(runes) <= n {
+		return s
+	}
+	if n == 0 {
+		return ""
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// defaultValue returns the default if the value is empty (nil, zero, empty
+// string, list or map), eg. {{.Branch | default "main"}}.
+func defaultValue(def, v interface{}) interface{} {
+	if v == nil {
+		return def
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		if rv.Len() == 0 {
+			return def
+		}
+	default:
+		if rv.IsZero() {
+			return def
+		}
+	}
+	return v
+}
+
+// durationSince returns the time elapsed since the given time, rounded to
+// seconds. The time is either a time, an RFC3339 timestamp or a unix timestamp.
+func durationSince(v interface{}) (time.Duration, error) {
+	t, err := parseTemplateTime(v)
+	if err != nil {
+		return 0, err
+	}
+	return time.Since(t).Round(time.Second), nil
+}
+
+// jsonEscape escapes the text to be inserted into a JSON string, without the quotes.
+func jsonEscape(s string) (string, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return string(b[1 : len(b)-1]), nil
+}
+
+// slackEscaper escapes the control characters of the Slack message formatting.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackEscape escapes the text so links, mentions and dates are shown as plain text.
+func slackEscape(s string) string {
+	return slackEscaper.Replace(s)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func Test_templateFuncs(t *testing.T) {
+	t.Setenv("TEMPLATE_FUNCS_TEST", "from env")
+
+	tests := []struct {
+		name string
+		tmpl string
+		data interface{}
+		want string
+	}{
+		{name: "truncate", tmpl: `{{. | truncate 5}}`, data: "Hello world", want: "Hell…"},
+		{name: "truncate short text", tmpl: `{{. | truncate 20}}`, data: "Hello world", want: "Hello world"},
+		{name: "truncate multibyte", tmpl: `{{. | truncate 3}}`, data: "árvíztűrő", want: "ár…"},
+		{name: "upper", tmpl: `{{upper .}}`, data: "main", want: "MAIN"},
+		{name: "default of empty", tmpl: `{{. | default "main"}}`, data: "", want: "main"},
+		{name: "default of value", tmpl: `{{. | default "main"}}`, data: "develop", want: "develop"},
+		{name: "default of nil", tmpl: `{{. | default "none"}}`, data: nil, want: "none"},
+		{name: "default of empty list", tmpl: `{{. | default "none"}}`, data: []string{}, want: "none"},
+		{name: "now", tmpl: `{{if now.IsZero}}zero{{else}}set{{end}}`, want: "set"},
+		{name: "env", tmpl: `{{env "TEMPLATE_FUNCS_TEST"}}`, want: "from env"},
+		{name: "jsonEscape", tmpl: `{{jsonEscape .}}`, data: "Say \"hi\"\nnow", want: `Say \"hi\"\nnow`},
+		{name: "slackEscape", tmpl: `{{slackEscape .}}`, data: "<!here> & <@U123>", want: "&lt;!here&gt; &amp; &lt;@U123&gt;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := template.New(tt.name).Funcs(templateFuncs).Parse(tt.tmpl)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			var b bytes.Buffer
+			if err := tmpl.Execute(&b, tt.data); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("Execute() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_durationSince(t *testing.T) {
+	started := time.Now().Add(-90 * time.Second)
+
+	tests := []struct {
+		name    string
+		v       interface{}
+		wantErr bool
+	}{
+		{name: "Time", v: started},
+		{name: "RFC3339", v: started.Format(time.RFC3339)},
+		{name: "Unix timestamp", v: strconv.FormatInt(started.Unix(), 10)},
+		{name: "Unix timestamp number", v: started.Unix()},
+		{name: "Invalid text", v: "yesterday", wantErr: true},
+		{name: "Invalid type", v: 1.5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := durationSince(tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("durationSince() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (got < 90*time.Second || got > 92*time.Second) {
+				t.Errorf("durationSince() = %v, want about 1m30s", got)
+			}
+		})
+	}
+}
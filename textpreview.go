@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// previewDividerWidth is the width of the divider blocks in the text preview.
+const previewDividerWidth = 40
+
+// plainTextConverter flattens Slack mrkdwn for the text preview: links are
+// shown with their target, mentions with their name, the formatting is kept.
+var plainTextConverter = mrkdwnConverter{
+	codeBlock: func(code string) string { return "```\n" + html.UnescapeString(code) + "\n```" },
+	link:      mrkdwnLinkToPlainText,
+	code:      func(code string) string { return "`" + html.UnescapeString(code) + "`" },
+	text:      html.UnescapeString,
+}
+
+// mrkdwnDatePattern matches the date formatting of Slack, which is shown with its fallback text.
+var mrkdwnDatePattern = regexp.MustCompile(`<!date\^[^|>]*\|([^>]*)>`)
+
+func mrkdwnLinkToPlainText(target, label string) string {
+	switch {
+	case strings.HasPrefix(target, "!") && label != "":
+		return label
+	case strings.HasPrefix(target, "!"):
+		return "@" + target[1:]
+	case isSlackReference(target) && label != "":
+		return target[:1] + strings.TrimPrefix(label, target[:1])
+	case isSlackReference(target), label == "", label == target:
+		return target
+	}
+	return label + " (" + target + ")"
+}
+
+// previewText returns the plain text of a text object or a string.
+func previewText(v interface{}) string {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case map[string]interface{}:
+		s, _ = v["text"].(string)
+	}
+	return plainTextConverter.convert(mrkdwnDatePattern.ReplaceAllString(s, "$1"))
+}
+
+// previewElement returns the text of a block element, eg. [View Build] for a button.
+func previewElement(v interface{}) string {
+	e, ok := v.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	switch e["type"] {
+	case "button":
+		return "[" + previewText(e["text"]) + "]"
+	case "image":
+		return fmt.Sprintf("[image: %v]", e["alt_text"])
+	case "mrkdwn", "plain_text":
+		return previewText(e)
+	}
+	if placeholder := previewText(e["placeholder"]); placeholder != "" {
+		return "[" + placeholder + " v]"
+	}
+	return fmt.Sprintf("[%v]", e["type"])
+}
+
+func previewElements(v interface{}, sep string) string {
+	elements, _ := v.([]interface{})
+	var texts []string
+	for _, e := range elements {
+		if s := previewElement(e); s != "" {
+			texts = append(texts, s)
+		}
+	}
+	return strings.Join(texts, sep)
+}
+
+// blockPreview returns the lines of a block.
+func blockPreview(b Block) []string {
+	var lines []string
+	add := func(s string) {
+		if s != "" {
+			lines = append(lines, strings.Split(s, "\n")...)
+		}
+	}
+
+	switch b["type"] {
+	case "header":
+		text := previewText(b["text"])
+		add(text)
+		add(strings.Repeat("=", utf8.RuneCountInString(text)))
+	case "section":
+		add(previewText(b["text"]))
+		fields, _ := b["fields"].([]interface{})
+		for _, f := range fields {
+			add(previewText(f))
+		}
+		add(previewElement(b["accessory"]))
+	case "divider":
+		add(strings.Repeat("-", previewDividerWidth))
+	case "context":
+		add(previewElements(b["elements"], " | "))
+	case "actions":
+		add(previewElements(b["elements"], " "))
+	case "image":
+		add(previewText(b["title"]))
+		add(fmt.Sprintf("[image: %v]", b["alt_text"]))
+	default:
+		add(Message{Blocks: []Block{b}}.PlainText())
+	}
+	return lines
+}
+
+// attachmentPreview returns the lines of an attachment, marked with a bar and its color.
+func attachmentPreview(a Attachment) []string {
+	var lines []string
+	add := func(s string) {
+		if s == "" {
+			return
+		}
+		for _, line := range strings.Split(s, "\n") {
+			lines = append(lines, "| "+line)
+		}
+	}
+
+	if a.PreText != "" {
+		lines = append(lines, strings.Split(previewText(a.PreText), "\n")...)
+	}
+	lines = append(lines, strings.TrimSpace("+-- "+a.Color))
+	add(a.AuthorName)
+	switch {
+	case a.Title != "" && a.TitleLink != "":
+		add(a.Title + " (" + a.TitleLink + ")")
+	default:
+		add(a.Title)
+	}
+	add(previewText(a.Text))
+	for _, f := range a.Fields {
+		add(f.Title + ": " + previewText(f.Value))
+	}
+	if a.ImageURL != "" {
+		add("[image: " + a.ImageURL + "]")
+	}
+	for _, b := range a.Blocks {
+		add(strings.Join(blockPreview(b), "\n"))
+	}
+	var buttons []string
+	for _, b := range a.Buttons {
+		buttons = append(buttons, "["+b.Text+"]")
+	}
+	add(strings.Join(buttons, " "))
+
+	footer := a.Footer
+	if a.TimeStamp != 0 {
+		ts := time.Unix(int64(a.TimeStamp), 0).UTC().Format(defaultDateFormat)
+		footer = strings.TrimPrefix(footer+" | "+ts, " | ")
+	}
+	add(footer)
+	return lines
+}
+
+// textPreview renders an approximate plain text preview of the message, with
+// the blocks and the attachments flattened, to check the formatting in the log.
+func textPreview(msg Message) string {
+	var lines []string
+	if msg.Text != "" {
+		lines = append(lines, strings.Split(previewText(msg.Text), "\n")...)
+	}
+	for _, b := range msg.Blocks {
+		lines = append(lines, blockPreview(b)...)
+	}
+	for _, a := range msg.Attachments {
+		lines = append(lines, attachmentPreview(a)...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// payloadTextPreview returns the text preview of the JSON payload.
+func payloadTextPreview(payload []byte) (string, error) {
+	var msg Message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return "", fmt.Errorf("failed to parse payload: %s", err)
+	}
+	return textPreview(msg), nil
+}
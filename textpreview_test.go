@@ -0,0 +1,96 @@
+package main
+
+import "testing"
+
+func Test_textPreview(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  Message
+		want string
+	}{
+		{
+			name: "Blocks",
+			msg: Message{Blocks: []Block{
+				{"type": "header", "text": map[string]interface{}{"type": "plain_text", "text": "App: Build Failed"}},
+				{"type": "section", "text": map[string]interface{}{"type": "mrkdwn", "text": "*Tests* failed, cc <@U123|jane> <!here>"}},
+				{"type": "section", "fields": []interface{}{
+					map[string]interface{}{"type": "mrkdwn", "text": "*Branch*\nmain"},
+					map[string]interface{}{"type": "mrkdwn", "text": "*Build*\n<https://app.bitrise.io/build/1|#12>"},
+				}},
+				{"type": "divider"},
+				{"type": "context", "elements": []interface{}{
+					map[string]interface{}{"type": "image", "image_url": "https://example.com/icon.png", "alt_text": "icon"},
+					map[string]interface{}{"type": "mrkdwn", "text": "Workflow: primary"},
+				}},
+				{"type": "actions", "elements": []interface{}{
+					map[string]interface{}{"type": "button", "text": map[string]interface{}{"type": "plain_text", "text": "View Build"}, "url": "https://app.bitrise.io/build/1"},
+					map[string]interface{}{"type": "static_select", "placeholder": map[string]interface{}{"type": "plain_text", "text": "Rerun"}},
+				}},
+			}},
+			want: "App: Build Failed\n" +
+				"=================\n" +
+				"*Tests* failed, cc @jane @here\n" +
+				"*Branch*\nmain\n" +
+				"*Build*\n#12 (https://app.bitrise.io/build/1)\n" +
+				"----------------------------------------\n" +
+				"[image: icon] | Workflow: primary\n" +
+				"[View Build] [Rerun v]",
+		},
+		{
+			name: "Attachment",
+			msg: Message{Text: "Build finished", Attachments: []Attachment{{
+				Color:      "#f0741f",
+				PreText:    "Build failed",
+				AuthorName: "Jane",
+				Title:      "Fix login",
+				TitleLink:  "https://example.com/commit/1",
+				Text:       "Details &amp; logs",
+				Fields:     []Field{{Title: "Branch", Value: "main"}},
+				Buttons:    []Button{{Text: "View Build", URL: "https://app.bitrise.io/build/1"}},
+				Footer:     "Bitrise",
+				TimeStamp:  1714651380,
+			}}},
+			want: "Build finished\n" +
+				"Build failed\n" +
+				"+-- #f0741f\n" +
+				"| Jane\n" +
+				"| Fix login (https://example.com/commit/1)\n" +
+				"| Details & logs\n" +
+				"| Branch: main\n" +
+				"| [View Build]\n" +
+				"| Bitrise | 2024-05-02 12:03 UTC",
+		},
+		{
+			name: "Blocks in an attachment",
+			msg: Message{Attachments: []Attachment{{Blocks: []Block{
+				{"type": "context", "elements": []interface{}{
+					map[string]interface{}{"type": "mrkdwn", "text": "Bitrise | <!date^1714651380^{date_short_pretty} at {time}|Thu, 02 May 2024 12:03:00 UTC>"},
+				}},
+			}}}},
+			want: "+--\n" +
+				"| Bitrise | Thu, 02 May 2024 12:03:00 UTC",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := textPreview(tt.msg); got != tt.want {
+				t.Errorf("textPreview() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_payloadTextPreview(t *testing.T) {
+	payload := `{"channel": "#builds", "blocks": [{"type": "section", "text": {"type": "mrkdwn", "text": "Deployed <https://example.com|App>"}}]}`
+	got, err := payloadTextPreview([]byte(payload))
+	if err != nil {
+		t.Fatalf("payloadTextPreview() error = %v", err)
+	}
+	if want := "Deployed App (https://example.com)"; got != want {
+		t.Errorf("payloadTextPreview() = %q, want %q", got, want)
+	}
+
+	if _, err := payloadTextPreview([]byte("not json")); err == nil {
+		t.Errorf("payloadTextPreview() error = nil, want error")
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+//go:embed themes/*.yml
+var themeFS embed.FS
+
+// themeNone disables the built-in themes.
+const themeNone = "none"
+
+// themeInputs are the inputs a theme can set, with their default values in the
+// step.yml. A theme only replaces the inputs left at their defaults.
+var themeInputs = map[string]func(inp *Input) (value *string, def string){
+	"color":          func(inp *Input) (*string, string) { return &inp.Color, "#3bc3a3" },
+	"color_on_error": func(inp *Input) (*string, string) { return &inp.ColorOnError, "#f0741f" },
+	"emoji_map":      func(inp *Input) (*string, string) { return &inp.EmojiMap, "" },
+	"footer":         func(inp *Input) (*string, string) { return &inp.Footer, "Bitrise" },
+	"footer_icon": func(inp *Input) (*string, string) {
+		return &inp.FooterIcon, "https://github.com/bitrise-io.png?size=16"
+	},
+	"author_name": func(inp *Input) (*string, string) {
+		return &inp.AuthorName, os.Getenv("GIT_CLONE_COMMIT_AUTHOR_NAME")
+	},
+}
+
+// parseTheme parses the YAML theme into input values, eg.
+//
+//	color: "#2eb67d"
+//	emoji_map:
+//	  success: ":large_green_circle:"
+//	footer: "Acme Mobile"
+//
+// The emoji map is either a mapping of statuses to emoji or the value of the
+// emoji_map input.
+func parseTheme(s string) (map[string]string, error) {
+	v, err := parseYAML(s)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return map[string]string{}, nil
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("must be a mapping of input names to values")
+	}
+
+	values := map[string]string{}
+	for key, value := range m {
+		if _, ok := themeInputs[key]; !ok {
+			return nil, fmt.Errorf("unknown theme input: %s, the theme can set %s", key, strings.Join(themeInputNames(), ", "))
+		}
+
+		switch value := value.(type) {
+		case string:
+			values[key] = value
+		case map[string]interface{}:
+			if key != "emoji_map" {
+				return nil, fmt.Errorf("the value of %s must be a string", key)
+			}
+			var items []string
+			for status, emoji := range value {
+				s, ok := emoji.(string)
+				if !ok {
+					return nil, fmt.Errorf("the emoji of %s must be a string", status)
+				}
+				items = append(items, status+"="+s)
+			}
+			sort.Strings(items)
+			values[key] = strings.Join(items, ",")
+		default:
+			return nil, fmt.Errorf("the value of %s must be a string", key)
+		}
+	}
+	return values, nil
+}
+
+func themeInputNames() []string {
+	var names []string
+	for name := range themeInputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadTheme returns the input values of the built-in theme, overridden by the
+// theme file if it is set.
+func loadTheme(name, pth string) (map[string]string, error) {
+	values := map[string]string{}
+	if name = strings.TrimSpace(name); name != "" && name != themeNone {
+		b, err := themeFS.ReadFile("themes/" + name + ".yml")
+		if err != nil {
+			return nil, fmt.Errorf("unknown theme: %s", name)
+		}
+		if values, err = parseTheme(string(b)); err != nil {
+			return nil, fmt.Errorf("invalid built-in theme %s: %s", name, err)
+		}
+	}
+
+	if pth = strings.TrimSpace(pth); pth != "" {
+		b, err := os.ReadFile(pth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read theme file: %s", err)
+		}
+		custom, err := parseTheme(string(b))
+		if err != nil {
+			return nil, fmt.Errorf("invalid theme file (%s): %s", pth, err)
+		}
+		for k, v := range custom {
+			values[k] = v
+		}
+	}
+	return values, nil
+}
+
+// applyTheme sets the inputs of the selected theme which are left at their
+// defaults, so the inputs of the step still take precedence. The values of the
+// theme can reference environment variables, eg. $GIT_CLONE_COMMIT_AUTHOR_NAME.
+func applyTheme(inp *Input) error {
+	values, err := loadTheme(inp.Theme, inp.ThemeFile)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range themeInputNames() {
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+		field, def := themeInputs[key](inp)
+		if strings.TrimSpace(*field) != def {
+			log.Debugf("Input %s is set by the step, ignoring the theme value", key)
+			continue
+		}
+		*field = os.ExpandEnv(value)
+	}
+	return nil
+}
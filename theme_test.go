@@ -0,0 +1,147 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_builtinThemes(t *testing.T) {
+	entries, err := themeFS.ReadDir("themes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".yml")
+		t.Run(name, func(t *testing.T) {
+			values, err := loadTheme(name, "")
+			if err != nil {
+				t.Fatalf("loadTheme() error = %v", err)
+			}
+			for _, key := range []string{"color", "color_on_error"} {
+				if _, err := resolveColor(values[key], key == "color"); err != nil {
+					t.Errorf("loadTheme() %s = invalid color: %v", key, err)
+				}
+			}
+			if _, err := parseEmojiMap(values["emoji_map"]); err != nil {
+				t.Errorf("loadTheme() emoji_map = invalid emoji map: %v", err)
+			}
+		})
+	}
+}
+
+func Test_applyTheme(t *testing.T) {
+	t.Setenv("GIT_CLONE_COMMIT_AUTHOR_NAME", "Jane")
+	pth := filepath.Join(t.TempDir(), "theme.yml")
+	content := "footer: \"Acme CI\"\nauthor_name: \"$GIT_CLONE_COMMIT_AUTHOR_NAME via Acme\"\n"
+	if err := os.WriteFile(pth, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defaults := Input{
+		Color:        "#3bc3a3",
+		ColorOnError: "#f0741f",
+		Footer:       "Bitrise",
+		FooterIcon:   "https://github.com/bitrise-io.png?size=16",
+		AuthorName:   "Jane",
+	}
+
+	tests := []struct {
+		name    string
+		inp     func(Input) Input
+		want    func(Input) Input
+		wantErr bool
+	}{
+		{
+			name: "No theme",
+			inp:  func(inp Input) Input { inp.Theme = themeNone; return inp },
+			want: func(inp Input) Input { inp.Theme = themeNone; return inp },
+		},
+		{
+			name: "Built-in theme",
+			inp:  func(inp Input) Input { inp.Theme = "traffic-light"; return inp },
+			want: func(inp Input) Input {
+				inp.Theme = "traffic-light"
+				inp.Color = "#2eb67d"
+				inp.ColorOnError = "#e01e5a"
+				inp.EmojiMap = "aborted=:white_circle:,failed=:red_circle:,success=:large_green_circle:"
+				return inp
+			},
+		},
+		{
+			name: "Inputs set in the step take precedence",
+			inp: func(inp Input) Input {
+				inp.Theme = "minimal"
+				inp.ColorOnError = "#ff0000"
+				inp.Footer = "Release train"
+				return inp
+			},
+			want: func(inp Input) Input {
+				inp.Theme = "minimal"
+				inp.Color = "#9aa5b1"
+				inp.ColorOnError = "#ff0000"
+				inp.EmojiMap = "aborted=:white_small_square:,failed=:small_orange_diamond:,success=:small_blue_diamond:"
+				inp.Footer = "Release train"
+				inp.FooterIcon = ""
+				inp.AuthorName = ""
+				return inp
+			},
+		},
+		{
+			name: "Theme file over a built-in theme",
+			inp: func(inp Input) Input {
+				inp.Theme = "monochrome"
+				inp.ThemeFile = pth
+				return inp
+			},
+			want: func(inp Input) Input {
+				inp.Theme = "monochrome"
+				inp.ThemeFile = pth
+				inp.Color = "#4a4a4a"
+				inp.ColorOnError = "#000000"
+				inp.EmojiMap = "aborted=:heavy_minus_sign:,failed=:heavy_multiplication_x:,success=:heavy_check_mark:"
+				inp.Footer = "Acme CI"
+				inp.AuthorName = "Jane via Acme"
+				return inp
+			},
+		},
+		{
+			name:    "Unknown theme",
+			inp:     func(inp Input) Input { inp.Theme = "neon"; return inp },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inp := tt.inp(defaults)
+			err := applyTheme(&inp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyTheme() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(inp, tt.want(defaults)) {
+				t.Errorf("applyTheme() = %+v, want %+v", inp, tt.want(defaults))
+			}
+		})
+	}
+}
+
+func Test_parseTheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		wantErr bool
+	}{
+		{name: "Emoji map as input value", s: "emoji_map: \"success=:rocket:\"\n"},
+		{name: "Unknown input", s: "channel: \"#builds\"\n", wantErr: true},
+		{name: "Mapping of a string input", s: "footer:\n  text: Acme\n", wantErr: true},
+		{name: "Not a mapping", s: "- footer\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseTheme(tt.s); (err != nil) != tt.wantErr {
+				t.Errorf("parseTheme() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// threadState is the root message of a build's thread.
+type threadState struct {
+	Channel string `json:"channel"`
+	Ts      string `json:"ts"`
+}
+
+// loadThread returns the root message of the build's thread, or nil if this is
+// the first message of the build.
+func loadThread(conf config) *threadState {
+	var thread threadState
+	found, err := loadState(statePath(conf.StateDir, "thread", conf.BuildSlug), &thread)
+	if err != nil {
+		log.Warnf("Failed to load the build's thread, starting a new one: %s", err)
+		return nil
+	}
+	if !found {
+		return nil
+	}
+	return &thread
+}
+
+// saveThread stores the sent message as the root of the build's thread.
+func saveThread(conf config, response *SendMessageResponse) {
+	if response == nil || response.Timestamp == "" {
+		log.Warnf("The thread can only be started with a message sent with an API token")
+		return
+	}
+
+	thread := threadState{Channel: response.Channel, Ts: response.Timestamp}
+	if err := saveState(statePath(conf.StateDir, "thread", conf.BuildSlug), thread); err != nil {
+		log.Warnf("Failed to save the build's thread: %s", err)
+		return
+	}
+	log.Debugf("Started thread %s for build %s", thread.Ts, conf.BuildSlug)
+}
+
+// withThread returns a copy of msg sent as a reply in the build's thread, unless
+// there is no thread yet or msg is already a reply.
+func withThread(msg Message, thread *threadState) Message {
+	if thread == nil || msg.ThreadTs != "" {
+		return msg
+	}
+	log.Infof("Sending the message to the build's thread")
+	msg.Channel = thread.Channel
+	msg.ThreadTs = thread.Ts
+	return msg
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_saveThread(t *testing.T) {
+	conf := config{StateDir: t.TempDir(), BuildSlug: "build-1"}
+	if thread := loadThread(conf); thread != nil {
+		t.Fatalf("loadThread() = %v, want nil", thread)
+	}
+
+	// Messages sent with a webhook have no timestamp and can't start a thread.
+	saveThread(conf, &SendMessageResponse{Channel: "C123"})
+	if thread := loadThread(conf); thread != nil {
+		t.Fatalf("loadThread() = %v, want nil", thread)
+	}
+
+	saveThread(conf, &SendMessageResponse{Channel: "C123", Timestamp: "1700000000.000100"})
+	want := threadState{Channel: "C123", Ts: "1700000000.000100"}
+	if got := loadThread(conf); got == nil || *got != want {
+		t.Errorf("loadThread() = %v, want %v", got, want)
+	}
+
+	other := config{StateDir: conf.StateDir, BuildSlug: "build-2"}
+	if thread := loadThread(other); thread != nil {
+		t.Errorf("loadThread() of another build = %v, want nil", thread)
+	}
+}
+
+func Test_withThread(t *testing.T) {
+	thread := &threadState{Channel: "C123", Ts: "1700000000.000100"}
+	tests := []struct {
+		name   string
+		msg    Message
+		thread *threadState
+		want   Message
+	}{
+		{name: "First message", msg: Message{Channel: "#builds", Text: "Started"}, want: Message{Channel: "#builds", Text: "Started"}},
+		{name: "Reply", msg: Message{Channel: "#builds", Text: "Finished"}, thread: thread, want: Message{Channel: "C123", ThreadTs: "1700000000.000100", Text: "Finished"}},
+		{
+			name:   "Already a reply",
+			msg:    Message{Channel: "C999", ThreadTs: "1600000000.000100", Text: "Finished"},
+			thread: thread,
+			want:   Message{Channel: "C999", ThreadTs: "1600000000.000100", Text: "Finished"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withThread(tt.msg, tt.thread); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withThread() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// defaultTicketPattern matches JIRA style ticket keys, eg. APP-123.
+const defaultTicketPattern = `\b[A-Z][A-Z0-9]+-[0-9]+\b`
+
+// findTickets returns the unique ticket keys found in the texts, in the order
+// of their first appearance.
+func findTickets(pattern string, texts ...string) ([]string, error) {
+	if pattern == "" {
+		pattern = defaultTicketPattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ticket pattern: %s", err)
+	}
+
+	var tickets []string
+	seen := map[string]bool{}
+	for _, text := range texts {
+		for _, key := range re.FindAllString(text, -1) {
+			if !seen[key] {
+				seen[key] = true
+				tickets = append(tickets, key)
+			}
+		}
+	}
+	return tickets, nil
+}
+
+// ticketsField links the tickets to the issue tracker.
+func ticketsField(baseURL string, tickets []string) Field {
+	baseURL = strings.TrimSuffix(baseURL, "/") + "/"
+	var links []string
+	for _, key := range tickets {
+		links = append(links, fmt.Sprintf("<%s%s|%s>", baseURL, key, key))
+	}
+	return Field{Title: "Tickets", Value: strings.Join(links, ", ")}
+}
+
+// withTickets returns a copy of msg with the tickets field appended to the fields.
+func withTickets(msg Message, baseURL string, tickets []string) Message {
+	if len(msg.Attachments) == 0 || len(tickets) == 0 {
+		return msg
+	}
+
+	attachments := append([]Attachment{}, msg.Attachments...)
+	attachments[0].Fields = append(append([]Field{}, attachments[0].Fields...), ticketsField(baseURL, tickets))
+	msg.Attachments = attachments
+	return msg
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_findTickets(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		texts   []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "Commit message and branch",
+			texts: []string{"APP-12 Fix login, relates to APP-7 and APP-12", "feature/WEB-3-dark-mode"},
+			want:  []string{"APP-12", "APP-7", "WEB-3"},
+		},
+		{name: "No tickets", texts: []string{"Fix typo", "main"}},
+		{name: "Custom pattern", pattern: `#[0-9]+`, texts: []string{"Fixes #42"}, want: []string{"#42"}},
+		{name: "Invalid pattern", pattern: `(`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findTickets(tt.pattern, tt.texts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("findTickets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findTickets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ticketsField(t *testing.T) {
+	got := ticketsField("https://example.atlassian.net/browse", []string{"APP-12", "WEB-3"}).Value
+	want := "<https://example.atlassian.net/browse/APP-12|APP-12>, <https://example.atlassian.net/browse/WEB-3|WEB-3>"
+	if got != want {
+		t.Errorf("ticketsField() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	// The time zone database, for build machines without one.
+	_ "time/tzdata"
+)
+
+// defaultDateFormat is the layout of the dates in the messages.
+const defaultDateFormat = "2006-01-02 15:04 MST"
+
+// parseTimezone returns the location of an IANA time zone name, eg. Europe/Budapest,
+// or UTC if the name is empty.
+func parseTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone (%s), use an IANA time zone name, e.g. Europe/Budapest: %s", name, err)
+	}
+	return loc, nil
+}
+
+// parseTemplateTime returns the time of a template value, which is either a
+// time, an RFC3339 timestamp or a unix timestamp.
+func parseTemplateTime(v interface{}) (time.Time, error) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, nil
+	case int:
+		return time.Unix(int64(v), 0), nil
+	case int64:
+		return time.Unix(v, 0), nil
+	case string:
+		v = strings.TrimSpace(v)
+		if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.Unix(sec, 0), nil
+		}
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time (%v), use an RFC3339 or a unix timestamp", v)
+}
+
+// timeFuncs returns the template functions showing times in the given location:
+//
+//	{{localTime (env "BITRISE_BUILD_TRIGGER_TIMESTAMP")}}
+//	{{formatTime now}}, {{formatTime now "Jan 2, 15:04"}}
+func timeFuncs(loc *time.Location, dateFormat string) template.FuncMap {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if dateFormat == "" {
+		dateFormat = defaultDateFormat
+	}
+
+	localTime := func(v interface{}) (time.Time, error) {
+		t, err := parseTemplateTime(v)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return t.In(loc), nil
+	}
+	return template.FuncMap{
+		"localTime": localTime,
+		// formatTime formats the time with the date format, or with the given layout.
+		"formatTime": func(v interface{}, layout ...string) (string, error) {
+			t, err := localTime(v)
+			if err != nil {
+				return "", err
+			}
+			if len(layout) > 0 {
+				return t.Format(layout[0]), nil
+			}
+			return t.Format(dateFormat), nil
+		},
+	}
+}
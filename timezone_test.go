@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func Test_parseTimezone(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "", want: "UTC"},
+		{name: "Europe/Budapest", want: "Europe/Budapest"},
+		{name: " Asia/Tokyo ", want: "Asia/Tokyo"},
+		{name: "Mars/Olympus_Mons", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTimezone(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTimezone() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("parseTimezone() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_timeFuncs(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Budapest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := time.Date(2024, 1, 15, 17, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		loc        *time.Location
+		dateFormat string
+		tmpl       string
+		data       interface{}
+		want       string
+		wantErr    bool
+	}{
+		{name: "Default timezone and format", tmpl: `{{formatTime .}}`, data: sent, want: "2024-01-15 17:30 UTC"},
+		{name: "Timezone", loc: loc, tmpl: `{{formatTime .}}`, data: sent, want: "2024-01-15 18:30 CET"},
+		{name: "Date format", loc: loc, dateFormat: "Jan 2, 15:04", tmpl: `{{formatTime .}}`, data: sent, want: "Jan 15, 18:30"},
+		{name: "Given layout", loc: loc, tmpl: `{{formatTime . "15:04"}}`, data: sent, want: "18:30"},
+		{name: "RFC3339 timestamp", loc: loc, tmpl: `{{formatTime .}}`, data: "2024-07-01T08:00:00Z", want: "2024-07-01 10:00 CEST"},
+		{name: "Unix timestamp", loc: loc, tmpl: `{{formatTime .}}`, data: "1705339800", want: "2024-01-15 18:30 CET"},
+		{name: "localTime", loc: loc, tmpl: `{{(localTime .).Hour}}`, data: sent, want: "18"},
+		{name: "Invalid time", tmpl: `{{formatTime .}}`, data: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := template.New(tt.name).Funcs(timeFuncs(tt.loc, tt.dateFormat)).Parse(tt.tmpl)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			var b bytes.Buffer
+			err = tmpl.Execute(&b, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := b.String(); !tt.wantErr && got != tt.want {
+				t.Errorf("Execute() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
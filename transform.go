@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// transformTimeout limits the run time of the payload transform script.
+const transformTimeout = time.Minute
+
+// transformPayload runs the script with the payload on its stdin and returns
+// the JSON printed to its stdout.
+func transformPayload(script string, payload []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), transformTimeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, script)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+
+	log.Debugf("Transforming the payload with %s", script)
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("transform script failed: %s", err)
+	}
+
+	transformed := bytes.TrimSpace(stdout.Bytes())
+	if !json.Valid(transformed) {
+		return nil, fmt.Errorf("transform script did not print a valid JSON payload: %s", transformed)
+	}
+	return transformed, nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_transformPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		want    string
+		wantErr bool
+	}{
+		{name: "Rewrites the payload", script: "#!/bin/sh\nsed 's/builds/releases/'\n", want: `{"channel":"#releases"}`},
+		{name: "Invalid output", script: "#!/bin/sh\necho not json\n", wantErr: true},
+		{name: "Failing script", script: "#!/bin/sh\nexit 1\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := filepath.Join(t.TempDir(), "transform.sh")
+			if err := os.WriteFile(script, []byte(tt.script), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := transformPayload(script, []byte(`{"channel":"#builds"}`))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("transformPayload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("transformPayload() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// findUserGroups returns the IDs of the user groups of the workspace by their handle.
+func findUserGroups(token string) (map[string]string, error) {
+	var resp struct {
+		UserGroups []struct {
+			ID     string `json:"id"`
+			Handle string `json:"handle"`
+		} `json:"usergroups"`
+	}
+	if err := callAPI(token, "usergroups.list", url.Values{}, &resp); err != nil {
+		return nil, err
+	}
+
+	ids := map[string]string{}
+	for _, g := range resp.UserGroups {
+		ids[g.Handle] = g.ID
+	}
+	return ids, nil
+}
+
+// userGroupMentions returns the mentions of the user groups, eg. <!subteam^S123|@ios-oncall>.
+// Handles not matching a user group of the workspace are left as plain text.
+func userGroupMentions(handles []string, ids map[string]string) []string {
+	var mentions []string
+	for _, h := range handles {
+		h = strings.TrimPrefix(h, "@")
+		id, ok := ids[h]
+		if !ok {
+			log.Warnf("No user group found with the handle @%s", h)
+			mentions = append(mentions, "@"+h)
+			continue
+		}
+		mentions = append(mentions, fmt.Sprintf("<!subteam^%s|@%s>", id, h))
+	}
+	return mentions
+}
+
+// withMentions returns a copy of msg with the mentions in front of the text, and
+// in a section on top of the blocks, as the text is not shown with blocks.
+func withMentions(msg Message, mentions []string) Message {
+	if len(mentions) == 0 {
+		return msg
+	}
+
+	text := strings.Join(mentions, " ")
+	if len(msg.Blocks) > 0 {
+		section := Block{"type": "section", "text": map[string]interface{}{"type": "mrkdwn", "text": text}}
+		msg.Blocks = append([]Block{section}, msg.Blocks...)
+	}
+	if msg.Text != "" {
+		text += " " + msg.Text
+	}
+	msg.Text = text
+	return msg
+}
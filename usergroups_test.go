@@ -0,0 +1,47 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_userGroupMentions(t *testing.T) {
+	ids := map[string]string{"ios-oncall": "S0614TZR7"}
+	want := []string{"<!subteam^S0614TZR7|@ios-oncall>", "@unknown"}
+	if got := userGroupMentions([]string{"@ios-oncall", "unknown"}, ids); !reflect.DeepEqual(got, want) {
+		t.Errorf("userGroupMentions() = %v, want %v", got, want)
+	}
+}
+
+func Test_withMentions(t *testing.T) {
+	mentions := []string{"<!subteam^S0614TZR7|@ios-oncall>"}
+	tests := []struct {
+		name string
+		msg  Message
+		want Message
+	}{
+		{
+			name: "Text",
+			msg:  Message{Text: "Build failed"},
+			want: Message{Text: "<!subteam^S0614TZR7|@ios-oncall> Build failed"},
+		},
+		{
+			name: "Blocks",
+			msg:  Message{Blocks: []Block{{"type": "divider"}}},
+			want: Message{
+				Text: "<!subteam^S0614TZR7|@ios-oncall>",
+				Blocks: []Block{
+					{"type": "section", "text": map[string]interface{}{"type": "mrkdwn", "text": "<!subteam^S0614TZR7|@ios-oncall>"}},
+					{"type": "divider"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withMentions(tt.msg, mentions); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withMentions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// appVersion is the marketing version and the build number of the app.
+type appVersion struct {
+	Version     string
+	BuildNumber string
+}
+
+// String formats the version as eg. v2.14.0 (1234).
+func (v appVersion) String() string {
+	s := "v" + v.Version
+	if v.BuildNumber != "" {
+		s += " (" + v.BuildNumber + ")"
+	}
+	return s
+}
+
+var (
+	plistVersionPattern      = regexp.MustCompile(`<key>CFBundleShortVersionString</key>\s*<string>([^<]*)</string>`)
+	plistBuildPattern        = regexp.MustCompile(`<key>CFBundleVersion</key>\s*<string>([^<]*)</string>`)
+	gradleVersionPattern     = regexp.MustCompile(`(?m)^\s*versionName\s*=?\s*["']([^"']+)["']`)
+	gradleBuildPattern       = regexp.MustCompile(`(?m)^\s*versionCode\s*=?\s*(\d+)`)
+	pubspecVersionPattern    = regexp.MustCompile(`(?m)^version:\s*["']?([^\s"'+#]+)(?:\+([^\s"'#]+))?`)
+	unsupportedVersionFormat = "unsupported version file (%s), use an Info.plist, build.gradle(.kts) or pubspec.yaml"
+)
+
+// readAppVersion reads the app version from an Info.plist, a build.gradle or a pubspec.yaml.
+func readAppVersion(pth string) (*appVersion, error) {
+	b, err := os.ReadFile(pth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read version file: %s", err)
+	}
+	return parseAppVersion(filepath.Base(pth), string(b))
+}
+
+// parseAppVersion parses the version file by its name.
+func parseAppVersion(name, content string) (*appVersion, error) {
+	var versionPattern, buildPattern *regexp.Regexp
+	switch {
+	case strings.HasSuffix(name, ".plist"):
+		versionPattern, buildPattern = plistVersionPattern, plistBuildPattern
+	case strings.HasPrefix(name, "build.gradle"):
+		versionPattern, buildPattern = gradleVersionPattern, gradleBuildPattern
+	case name == "pubspec.yaml" || name == "pubspec.yml":
+		m := pubspecVersionPattern.FindStringSubmatch(content)
+		if m == nil {
+			return nil, fmt.Errorf("no version found in %s", name)
+		}
+		return &appVersion{Version: m[1], BuildNumber: m[2]}, nil
+	default:
+		return nil, fmt.Errorf(unsupportedVersionFormat, name)
+	}
+
+	m := versionPattern.FindStringSubmatch(content)
+	if m == nil {
+		return nil, fmt.Errorf("no version found in %s", name)
+	}
+	v := &appVersion{Version: strings.TrimSpace(m[1])}
+	if m := buildPattern.FindStringSubmatch(content); m != nil {
+		v.BuildNumber = strings.TrimSpace(m[1])
+	}
+	return v, nil
+}
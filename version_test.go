@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseAppVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    *appVersion
+		wantErr bool
+	}{
+		{
+			name: "Info.plist",
+			file: "Info.plist",
+			content: `<dict>
+	<key>CFBundleShortVersionString</key>
+	<string>2.14.0</string>
+	<key>CFBundleVersion</key>
+	<string>1234</string>
+</dict>`,
+			want: &appVersion{Version: "2.14.0", BuildNumber: "1234"},
+		},
+		{
+			name: "build.gradle",
+			file: "build.gradle",
+			content: `defaultConfig {
+        versionCode 1234
+        versionName "2.14.0"
+    }`,
+			want: &appVersion{Version: "2.14.0", BuildNumber: "1234"},
+		},
+		{
+			name: "build.gradle.kts",
+			file: "build.gradle.kts",
+			content: `defaultConfig {
+        versionCode = 1234
+        versionName = "2.14.0"
+    }`,
+			want: &appVersion{Version: "2.14.0", BuildNumber: "1234"},
+		},
+		{name: "pubspec.yaml", file: "pubspec.yaml", content: "name: app\nversion: 2.14.0+1234\n", want: &appVersion{Version: "2.14.0", BuildNumber: "1234"}},
+		{name: "pubspec.yaml without build number", file: "pubspec.yaml", content: "version: 2.14.0 # release\n", want: &appVersion{Version: "2.14.0"}},
+		{name: "Missing version", file: "Info.plist", content: "<dict></dict>", wantErr: true},
+		{name: "Unsupported file", file: "package.json", content: `{"version": "2.14.0"}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAppVersion(tt.file, tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAppVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAppVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
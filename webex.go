@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/bitrise-tools/go-steputils/stepconf"
+)
+
+// webexAPIURL is the base URL of the Webex REST API.
+const webexAPIURL = "https://webexapis.com/v1/"
+
+func init() {
+	registerProvider("webex", webexProvider{})
+}
+
+// webexProvider sends the message as Markdown to a Webex incoming webhook, or
+// with a bot to a Webex space.
+type webexProvider struct{}
+
+// webexConfig is the bot and the space of the webex provider. If not set, the
+// message is sent to the incoming webhook.
+type webexConfig struct {
+	BotToken string
+	RoomID   string
+}
+
+type webexMessage struct {
+	RoomID   string `json:"roomId,omitempty"`
+	Text     string `json:"text"`
+	Markdown string `json:"markdown"`
+}
+
+// newWebexMessage converts the message into a Webex message with a plain text fallback.
+func newWebexMessage(roomID string, msg Message) webexMessage {
+	return webexMessage{
+		RoomID:   roomID,
+		Text:     msg.PlainText(),
+		Markdown: messageMarkdown(msg),
+	}
+}
+
+// Send implements Provider.
+func (webexProvider) Send(conf config, msg Message) (*SendMessageResponse, error) {
+	b, err := json.Marshal(newWebexMessage(conf.Webex.RoomID, msg))
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Webex.BotToken != "" {
+		// The response is not decoded, as the message can't be referenced by later steps.
+		conf.WebhookURL = webexAPIURL + "messages"
+		conf.APIToken = stepconf.Secret(conf.Webex.BotToken)
+	}
+	return sendPayload(conf, b)
+}
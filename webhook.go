@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+	"github.com/bitrise-tools/go-steputils/stepconf"
+)
+
+// webhookHosts are the webhook hosts of the providers, matched as host suffixes.
+var webhookHosts = map[string][]string{
+	"slack-webhook": {"hooks.slack.com", "hooks.slack-gov.com"},
+	"teams":         {"webhook.office.com", "outlook.office.com", "logic.azure.com", "powerplatform.com"},
+	"discord":       {"discord.com", "discordapp.com"},
+	"webex":         {"webexapis.com"},
+}
+
+// checkWebhookURL validates the webhook URL of the provider. Obvious mistakes,
+// like a pasted API token or channel link, are reported as an error, unknown
+// webhook hosts (eg. proxies) only as a warning.
+func checkWebhookURL(provider, raw string, allowInsecure bool) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "xox") {
+		return "", fmt.Errorf("the webhook URL looks like a Slack API token, provide it as the API token instead")
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("the webhook URL is not a valid URL")
+	}
+	var warning string
+	switch {
+	case u.Scheme == "http" && allowInsecure:
+		warning = "the webhook URL uses plain http, the message is sent unencrypted"
+	case u.Scheme != "https":
+		return "", fmt.Errorf("the webhook URL must use https, got %s (set allow_insecure to allow plain http)", u.Scheme)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if strings.HasSuffix(host, ".slack.com") && host != "hooks.slack.com" {
+		return "", fmt.Errorf("the webhook URL (%s) looks like a link to a Slack channel or message, create an incoming webhook and use its URL", host)
+	}
+
+	hosts, ok := webhookHosts[provider]
+	if !ok {
+		return warning, nil
+	}
+	for _, h := range hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return warning, nil
+		}
+	}
+	return fmt.Sprintf("the webhook URL host (%s) is not a known %s webhook host", host, provider), nil
+}
+
+// selectEnvironmentWebhook returns the webhook URL of the environment from the
+// JSON map of environment names to webhook URLs.
+func selectEnvironmentWebhook(webhooks, environment string) (string, error) {
+	var m map[string]string
+	if err := json.Unmarshal([]byte(webhooks), &m); err != nil {
+		return "", fmt.Errorf("webhook URLs must be a JSON object of environment names to webhook URLs: %s", err)
+	}
+
+	environment = strings.TrimSpace(environment)
+	if environment == "" {
+		return "", fmt.Errorf("environment is required to select one of the webhook URLs")
+	}
+
+	u, ok := m[environment]
+	if !ok || strings.TrimSpace(u) == "" {
+		var names []string
+		for name := range m {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("no webhook URL for the %s environment, available environments: %s", environment, strings.Join(names, ", "))
+	}
+	return strings.TrimSpace(u), nil
+}
+
+// applyEnvironmentWebhook sets the webhook URL of the selected environment.
+func applyEnvironmentWebhook(inp *Input) error {
+	if strings.TrimSpace(string(inp.WebhookURLs)) == "" {
+		return nil
+	}
+	if inp.WebhookURL != "" {
+		return fmt.Errorf("Provide either the Webhook URL or the per-environment webhook URLs, not both")
+	}
+
+	u, err := selectEnvironmentWebhook(string(inp.WebhookURLs), inp.Environment)
+	if err != nil {
+		return fmt.Errorf("Failed to select the webhook URL: %s", err)
+	}
+	log.Infof("Using the webhook URL of the %s environment", strings.TrimSpace(inp.Environment))
+	inp.WebhookURL = stepconf.Secret(u)
+	return nil
+}
+
+// isSenderDefault reports whether the value is the step.yml default of the
+// sender input or of its _on_success and _on_error variants, normalized as the
+// input's value.
+func isSenderDefault(defaults map[string]string, key, value string, normalize func(string) string) bool {
+	for _, k := range []string{key, key + "_on_success", key + "_on_error"} {
+		def, ok := defaults[k]
+		if !ok || def == "" {
+			continue
+		}
+		if isInputDefault(defaults, k, value) || (normalize != nil && normalize(os.ExpandEnv(def)) == value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoredWebhookOverrides returns the customized sender inputs, which are
+// ignored by the webhooks of Slack apps: those always post as the app, to the
+// channel selected when the webhook was created. Inputs left at their step.yml
+// defaults are not reported.
+func ignoredWebhookOverrides(conf config) []string {
+	defaults := inputDefaults()
+	overrides := []struct {
+		name, key, value string
+		normalize        func(string) string
+	}{
+		{name: "username", key: "from_username", value: conf.Username},
+		{name: "emoji", key: "emoji", value: conf.IconEmoji, normalize: normalizeEmoji},
+		{name: "icon URL", key: "icon_url", value: conf.IconURL},
+		{name: "channel", key: "channel", value: conf.Channel, normalize: normalizeChannel},
+	}
+
+	var names []string
+	for _, o := range overrides {
+		if o.value != "" && !isSenderDefault(defaults, o.key, o.value, o.normalize) {
+			names = append(names, o.name)
+		}
+	}
+	return names
+}
+
+// warnIgnoredWebhookOverrides warns if the sender is customized but the message
+// is sent with a Slack webhook, which can not tell whether it honors them.
+func warnIgnoredWebhookOverrides(conf config) {
+	if strings.TrimSpace(conf.WebhookURL) == "" || providerName(conf.Provider, string(conf.APIToken)) != "slack-webhook" {
+		return
+	}
+	if names := ignoredWebhookOverrides(conf); len(names) > 0 {
+		log.Warnf("The %s inputs are ignored by the webhooks of Slack apps, only legacy webhooks honor them. "+
+			"Use the Slack API token with the chat:write.customize scope to customize the sender.", strings.Join(names, ", "))
+	}
+}
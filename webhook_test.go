@@ -0,0 +1,117 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_checkWebhookURL(t *testing.T) {
+	tests := []struct {
+		name          string
+		provider      string
+		raw           string
+		allowInsecure bool
+		wantWarning   bool
+		wantErr       bool
+	}{
+		{name: "Slack webhook", provider: "slack-webhook", raw: "https://hooks.slack.com/services/T000/B000/XXXX"},
+		{name: "Slack workflow webhook", provider: "slack-webhook", raw: "https://hooks.slack.com/triggers/T000/1/XXXX"},
+		{name: "Teams webhook", provider: "teams", raw: "https://example.webhook.office.com/webhookb2/XXXX"},
+		{name: "Generic webhook", provider: "generic", raw: "https://example.com/hook"},
+		{name: "Unknown host", provider: "slack-webhook", raw: "https://proxy.example.com/slack", wantWarning: true},
+		{name: "Discord URL for Teams", provider: "teams", raw: "https://discord.com/api/webhooks/1/XXXX", wantWarning: true},
+		{name: "API token", provider: "slack-webhook", raw: "xoxb-1234-5678", wantErr: true},
+		{name: "Channel link", provider: "slack-webhook", raw: "https://example.slack.com/archives/C024BE91L", wantErr: true},
+		{name: "Plain http", provider: "generic", raw: "http://example.com/hook", wantErr: true},
+		{name: "Allowed plain http", provider: "generic", raw: "http://relay.internal/hook", allowInsecure: true, wantWarning: true},
+		{name: "Not a URL", provider: "slack-webhook", raw: "#builds", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning, err := checkWebhookURL(tt.provider, tt.raw, tt.allowInsecure)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkWebhookURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (warning != "") != tt.wantWarning {
+				t.Errorf("checkWebhookURL() warning = %v, wantWarning %v", warning, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func Test_selectEnvironmentWebhook(t *testing.T) {
+	webhooks := `{"staging": "https://hooks.slack.com/services/T000/B001/XXXX", "production": " https://hooks.slack.com/services/T000/B002/XXXX "}`
+
+	tests := []struct {
+		name        string
+		webhooks    string
+		environment string
+		want        string
+		wantErr     bool
+	}{
+		{name: "Staging", webhooks: webhooks, environment: "staging", want: "https://hooks.slack.com/services/T000/B001/XXXX"},
+		{name: "Production", webhooks: webhooks, environment: " production ", want: "https://hooks.slack.com/services/T000/B002/XXXX"},
+		{name: "Unknown environment", webhooks: webhooks, environment: "qa", wantErr: true},
+		{name: "No environment", webhooks: webhooks, wantErr: true},
+		{name: "Invalid JSON", webhooks: `staging: https://hooks.slack.com`, environment: "staging", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectEnvironmentWebhook(tt.webhooks, tt.environment)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectEnvironmentWebhook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("selectEnvironmentWebhook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ignoredWebhookOverrides(t *testing.T) {
+	tests := []struct {
+		name string
+		conf config
+		want []string
+	}{
+		{name: "Defaults", conf: config{Username: "Bitrise", IconURL: "https://github.com/bitrise-io.png"}, want: nil},
+		{name: "Empty", conf: config{}, want: nil},
+		{
+			name: "Customized",
+			conf: config{Username: "Release bot", IconEmoji: ":rocket:", IconURL: "https://example.com/icon.png", Channel: "#builds"},
+			want: []string{"username", "emoji", "icon URL", "channel"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ignoredWebhookOverrides(tt.conf); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ignoredWebhookOverrides() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isSenderDefault(t *testing.T) {
+	defaults := map[string]string{"emoji": "", "emoji_on_error": "rotating_light", "channel": "Builds", "from_username": "Bitrise"}
+	tests := []struct {
+		name      string
+		key       string
+		value     string
+		normalize func(string) string
+		want      bool
+	}{
+		{name: "Default", key: "from_username", value: "Bitrise", want: true},
+		{name: "Customized", key: "from_username", value: "Release bot", want: false},
+		{name: "Normalized default of a variant", key: "emoji", value: ":rotating_light:", normalize: normalizeEmoji, want: true},
+		{name: "Normalized default channel", key: "channel", value: "#builds", normalize: normalizeChannel, want: true},
+		{name: "Customized channel", key: "channel", value: "#releases", normalize: normalizeChannel, want: false},
+		{name: "Empty default", key: "emoji", value: ":rocket:", normalize: normalizeEmoji, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSenderDefault(defaults, tt.key, tt.value, tt.normalize); got != tt.want {
+				t.Errorf("isSenderDefault() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+// parseWorkflowVariables parses the key|value lines into the flat map
+// expected by Slack Workflow Builder webhooks.
+func parseWorkflowVariables(s string) map[string]string {
+	vars := map[string]string{}
+	for _, p := range pairs(s) {
+		vars[p[0]] = ensureNewlines(p[1])
+	}
+	return vars
+}
+
+// postWorkflowVariables triggers a Slack Workflow Builder webhook with the
+// configured variables instead of a message payload.
+func postWorkflowVariables(conf config) error {
+	b, err := json.Marshal(parseWorkflowVariables(conf.WorkflowVariables))
+	if err != nil {
+		return err
+	}
+
+	if _, err := postPayload(conf, b); err != nil {
+		return err
+	}
+	log.Infof("Triggered the Slack workflow")
+	return nil
+}
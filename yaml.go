@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// parseYAML parses a YAML document of the step's structured inputs and files.
+// Mappings are returned as map[string]interface{}, sequences as []interface{}
+// and scalars as their string value, without resolving them to booleans or
+// numbers (eg. yes stays "yes"). Null values are returned as an empty string,
+// an empty document as nil.
+func parseYAML(s string) (interface{}, error) {
+	var v yamlValue
+	if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+		return nil, err
+	}
+	return v.value, nil
+}
+
+// yamlValue decodes any YAML node into the generic values of parseYAML.
+type yamlValue struct {
+	value interface{}
+}
+
+// orEmpty returns the value, or an empty string for null values, which are not
+// passed to UnmarshalYAML.
+func (v yamlValue) orEmpty() interface{} {
+	if v.value == nil {
+		return ""
+	}
+	return v.value
+}
+
+func (v *yamlValue) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	// Scalars decoded into a string keep their source text.
+	var s string
+	if err := unmarshal(&s); err == nil {
+		v.value = s
+		return nil
+	}
+
+	var seq []yamlValue
+	if err := unmarshal(&seq); err == nil {
+		items := make([]interface{}, len(seq))
+		for i, item := range seq {
+			items[i] = item.orEmpty()
+		}
+		v.value = items
+		return nil
+	}
+
+	var m map[string]yamlValue
+	if err := unmarshal(&m); err != nil {
+		return err
+	}
+	values := make(map[string]interface{}, len(m))
+	for key, item := range m {
+		values[key] = item.orEmpty()
+	}
+	v.value = values
+	return nil
+}
+
+func isSequenceItem(t string) bool {
+	return t == "-" || strings.HasPrefix(t, "- ")
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name: "Sequence of mappings",
+			s: `# fields
+- title: App
+  value: "My App"
+  short: yes
+- title: 'Release notes'
+  value: |
+    line1
+    line2
+`,
+			want: []interface{}{
+				map[string]interface{}{"title": "App", "value": "My App", "short": "yes"},
+				map[string]interface{}{"title": "Release notes", "value": "line1\nline2\n"},
+			},
+		},
+		{
+			name: "Nested mapping with an unindented sequence",
+			s: `theme:
+  color: "#3bc3a3" # green
+  url: https://example.com/a:b
+emails:
+- a@example.com
+- b@example.com
+empty:
+`,
+			want: map[string]interface{}{
+				"theme":  map[string]interface{}{"color": "#3bc3a3", "url": "https://example.com/a:b"},
+				"emails": []interface{}{"a@example.com", "b@example.com"},
+				"empty":  "",
+			},
+		},
+		{
+			name: "Folded block scalar with strip chomping",
+			s:    "text: >-\n  a\n  b\nnext: c",
+			want: map[string]interface{}{"text": "a b", "next": "c"},
+		},
+		{
+			name:    "Bad indentation",
+			s:       "a: b\n    c: d",
+			wantErr: true,
+		},
+		{
+			name: "Quoted brackets",
+			s:    "title: \"[release] v1.2.0\"\nitems:\n- '{build}'",
+			want: map[string]interface{}{"title": "[release] v1.2.0", "items": []interface{}{"{build}"}},
+		},
+		{
+			name: "Flow sequence",
+			s:    "mrkdwn_in: [text, pretext]",
+			want: map[string]interface{}{"mrkdwn_in": []interface{}{"text", "pretext"}},
+		},
+		{
+			name: "Flow mapping",
+			s:    "k: {a: 1, b: yes, c: ~}",
+			want: map[string]interface{}{"k": map[string]interface{}{"a": "1", "b": "yes", "c": ""}},
+		},
+		{
+			name: "Flow sequence item",
+			s:    "- [a, b]",
+			want: []interface{}{[]interface{}{"a", "b"}},
+		},
+		{
+			name: "Anchors and merge keys",
+			s:    "base: &base\n  color: good\n  footer: Bitrise\nerror:\n  <<: *base\n  color: danger\ncopy: *base",
+			want: map[string]interface{}{
+				"base":  map[string]interface{}{"color": "good", "footer": "Bitrise"},
+				"error": map[string]interface{}{"color": "danger", "footer": "Bitrise"},
+				"copy":  map[string]interface{}{"color": "good", "footer": "Bitrise"},
+			},
+		},
+		{name: "Empty document", s: "# nothing\n", want: nil},
+		{
+			name:    "Unterminated flow sequence",
+			s:       "mrkdwn_in: [text",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAML(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseYAML() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseYAML() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bitrise-steplib/steps-slack-message/internal/log"
+)
+
+func init() {
+	registerProvider("zulip", zulipProvider{})
+}
+
+// zulipProvider sends the message as Markdown to a topic of a Zulip stream.
+type zulipProvider struct{}
+
+// zulipConfig is the organization, the bot and the stream of the zulip provider.
+type zulipConfig struct {
+	SiteURL  string
+	BotEmail string
+	APIKey   string
+	Stream   string
+	Topic    string
+}
+
+type zulipMessage struct {
+	Type    string `json:"type"`
+	To      string `json:"to"`
+	Topic   string `json:"topic"`
+	Content string `json:"content"`
+}
+
+// newZulipMessage converts the message into a stream message of the topic.
+// The icon emoji is shown in front of the message, as Zulip has no message
+// colors or bot icons to show the build status.
+func newZulipMessage(stream, topic string, msg Message) zulipMessage {
+	content := messageMarkdown(msg)
+	if emoji := normalizeEmoji(msg.IconEmoji); emoji != "" {
+		content = emoji + " " + content
+	}
+	return zulipMessage{Type: "stream", To: stream, Topic: topic, Content: content}
+}
+
+// Send implements Provider.
+func (zulipProvider) Send(conf config, msg Message) (*SendMessageResponse, error) {
+	m := newZulipMessage(conf.Zulip.Stream, conf.Zulip.Topic, msg)
+	if b, err := json.Marshal(m); err == nil {
+		saveRenderedPayload(conf, b)
+		log.Debugf("Request to Zulip: %s\n", b)
+	}
+
+	form := url.Values{"type": {m.Type}, "to": {m.To}, "topic": {m.Topic}, "content": {m.Content}}
+	apiURL := strings.TrimSuffix(conf.Zulip.SiteURL, "/") + "/api/v1/messages"
+	req, err := http.NewRequest("POST", apiURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(conf.Zulip.BotEmail, conf.Zulip.APIKey)
+
+	body, err := sendProviderRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Response from Zulip: %s\n", body)
+	return nil, nil
+}